@@ -8,15 +8,26 @@ import (
 	"os/signal"
 	"syscall"
 
+	"serverbot/internal/agent"
 	"serverbot/internal/bot"
+	"serverbot/internal/redact"
 )
 
 func main() {
-	logger := log.New(os.Stdout, "serverbot: ", log.LstdFlags)
+	// Errors can carry command lines and request URLs that embed secrets.
+	logger := log.New(redact.NewWriter(os.Stdout), "serverbot: ", log.LstdFlags)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// "serverbot agent" serves this host to a bot running elsewhere.
+	if len(os.Args) > 1 && os.Args[1] == "agent" {
+		if err := agent.Run(ctx, logger); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Fatalf("agent stopped: %v", err)
+		}
+		return
+	}
+
 	runner := bot.New(logger)
 	if err := runner.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		logger.Fatalf("bot stopped: %v", err)
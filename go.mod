@@ -3,10 +3,14 @@ module serverbot
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
+	github.com/godbus/dbus/v5 v5.2.2
 	github.com/gofrs/flock v0.13.0
 	github.com/shirou/gopsutil/v4 v4.25.10
 	github.com/shogo82148/androidbinary v1.0.5
+	golang.org/x/crypto v0.43.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,196 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/metrics"
+	"serverbot/internal/testutil"
+)
+
+type recordingRunner struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, strings.Join(append([]string{name}, args...), " "))
+	return "web Up 2 hours\n", "", nil
+}
+
+// startAgent serves an agent for host "nas" in-process.
+func startAgent(t *testing.T) (*httptest.Server, *recordingRunner) {
+	t.Helper()
+	runner := &recordingRunner{}
+	srv := &Server{
+		Name:  "nas",
+		Token: "secret",
+		Sampler: &metrics.Sampler{Collector: metrics.NewCollector(metrics.Options{
+			GPUProviders: []metrics.GPUProvider{},
+		})},
+		Runner:  runner,
+		Allow:   ParseRules(DefaultAllow),
+		Timeout: 10 * time.Second,
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts, runner
+}
+
+func TestClientStatsAndRun(t *testing.T) {
+	ts, runner := startAgent(t)
+	client := NewClient("nas", ts.URL, "secret")
+	ctx := context.Background()
+
+	snap, err := client.Stats(ctx, false)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if snap.Host != "nas" || snap.At.IsZero() || snap.Stats.CPU.Cores == 0 || len(snap.Stats.Sources) == 0 {
+		t.Fatalf("Stats() = host %q at %v, %d cores, %d sources", snap.Host, snap.At, snap.Stats.CPU.Cores, len(snap.Stats.Sources))
+	}
+
+	stdout, _, err := client.Run(ctx, "docker", "ps", "--format", "{{.Names}}")
+	if err != nil || stdout != "web Up 2 hours\n" {
+		t.Fatalf("Run(docker ps) = %q, %v", stdout, err)
+	}
+
+	if _, _, err := client.Run(ctx, "docker", "rm", "-f", "web"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("Run(docker rm) error = %v, want not allowed", err)
+	}
+	if len(runner.calls) != 1 || runner.calls[0] != "docker ps --format {{.Names}}" {
+		t.Fatalf("agent ran %q", runner.calls)
+	}
+}
+
+func TestServerRejectsBadRequests(t *testing.T) {
+	ts, runner := startAgent(t)
+	ctx := context.Background()
+
+	wrong := NewClient("nas", ts.URL, "guess")
+	if _, _, err := wrong.Run(ctx, "docker", "ps"); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Fatalf("wrong token error = %v", err)
+	}
+
+	stale := NewClient("nas", ts.URL, "secret")
+	stale.now = func() time.Time { return time.Now().Add(-2 * MaxSkew) }
+	if _, _, err := stale.Run(ctx, "docker", "ps"); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Fatalf("stale timestamp error = %v", err)
+	}
+
+	// Replaying a captured request is refused even within MaxSkew.
+	body := `{"name":"docker","args":["ps"]}`
+	stamp := strconv.FormatInt(time.Now().Unix(), 10)
+	send := func() int {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+pathRun, strings.NewReader(body))
+		req.Header.Set(headerTimestamp, stamp)
+		req.Header.Set(headerSignature, sign("secret", http.MethodPost, pathRun, body, stamp))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := send(); code != http.StatusOK {
+		t.Fatalf("first request status = %d", code)
+	}
+	if code := send(); code != http.StatusUnauthorized {
+		t.Fatalf("replayed request status = %d, want 401", code)
+	}
+	if len(runner.calls) != 1 {
+		t.Fatalf("agent ran %d commands, want 1", len(runner.calls))
+	}
+}
+
+func TestClientRejectsUnsignedResponse(t *testing.T) {
+	fake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"stdout":"forged"}`))
+	}))
+	defer fake.Close()
+
+	client := NewClient("nas", fake.URL, "secret")
+	if _, _, err := client.Run(context.Background(), "docker", "ps"); err == nil || !strings.Contains(err.Error(), "unverified") {
+		t.Fatalf("unsigned response error = %v", err)
+	}
+}
+
+func TestStatsCommandOnAgent(t *testing.T) {
+	ts, _ := startAgent(t)
+	reg := commands.NewRegistry(commands.Dependencies{Config: app.Config{OwnerID: 1, CommandTimeout: 10 * time.Second}})
+	reg.Handle("stats", "", commands.ScopePublic, commands.NewStatsHandler(&metrics.Sampler{}))
+	reg.AllowRemote("stats")
+	reg.SetHosts(NewClient("nas", ts.URL, "secret"))
+
+	bot, client := testutil.NewFakeBot()
+	if err := reg.Dispatch(context.Background(), bot, commands.SyntheticUpdate(1, "stats", "cpu @nas")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	reqs := client.Requests()
+	last := reqs[len(reqs)-1]
+	if last.Endpoint != "editMessageText" || !strings.Contains(last.Values.Get("text"), "Estado de nas") {
+		t.Fatalf("last request = %s %q", last.Endpoint, last.Values.Get("text"))
+	}
+}
+
+func TestParseHostAndRules(t *testing.T) {
+	name, url, err := ParseHost(" NAS = http://10.0.0.5:9102/ ")
+	if err != nil || name != "nas" || url != "http://10.0.0.5:9102" {
+		t.Fatalf("ParseHost() = %q, %q, %v", name, url, err)
+	}
+	for _, spec := range []string{"nas", "nas=10.0.0.5:9102", "n@s=http://x", "=http://x"} {
+		if _, _, err := ParseHost(spec); err == nil {
+			t.Errorf("ParseHost(%q) expected error", spec)
+		}
+	}
+
+	rules := ParseRules([]string{"docker ps", " ", "systemctl status"})
+	if len(rules) != 2 {
+		t.Fatalf("ParseRules() = %v", rules)
+	}
+	if !Allowed(rules, "systemctl", []string{"status", "nginx"}) {
+		t.Error("systemctl status nginx refused")
+	}
+	if Allowed(rules, "systemctl", []string{"stop", "nginx"}) || Allowed(rules, "docker", nil) {
+		t.Error("command outside the rules allowed")
+	}
+}
+
+func TestAllowedRestrictsExtraOptions(t *testing.T) {
+	rules := ParseRules(DefaultAllow)
+	for _, argv := range [][]string{
+		{"docker", "logs", "--tail", "20", "web"},
+		{"docker", "ps", "--no-trunc", "--format", "{{.ID}} {{.Names}}"},
+		{"systemctl", "status", "nginx", "--no-pager", "--lines=20"},
+		{"ps", "-eo", "pid=,user=,pcpu=,pmem=,rss=,args=", "--sort=-pcpu"},
+		{"ping", "-c", "4", "example.com"},
+	} {
+		if !Allowed(rules, argv[0], argv[1:]) {
+			t.Errorf("%v refused", argv)
+		}
+	}
+	for _, argv := range [][]string{
+		{"docker", "logs", "--tail", "20", "--follow", "web"},
+		{"docker", "logs", "--tail", "20", "-f", "web"},
+		{"ping", "-c", "4", "-f", "example.com"},
+		{"ping", "-c", "4", "-i", "0", "example.com"},
+		{"systemctl", "status", "--lines=20", "--output=export"},
+	} {
+		if Allowed(rules, argv[0], argv[1:]) {
+			t.Errorf("%v allowed", argv)
+		}
+	}
+	if got := ParseRules([]string{"ps -eo [--sort=*]"})[0].String(); got != "ps -eo [--sort=*]" {
+		t.Errorf("String() = %q", got)
+	}
+}
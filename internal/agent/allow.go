@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"path"
+	"strings"
+)
+
+// DefaultAllow are the command rules an agent runs when AGENT_ALLOW is
+// unset: the read-only commands behind /docker, /docker_stats, /docker_logs,
+// /service_status, /top and /ping.
+var DefaultAllow = []string{
+	"docker ps [--format] [--no-trunc] [--filter]",
+	"docker stats --no-stream [--format]",
+	"docker logs --tail",
+	"systemctl status [--no-pager] [--lines=*]",
+	"ps -eo [--sort=*]",
+	"ping -c",
+}
+
+// Rule is an argv prefix plus the options allowed after it. A command is
+// allowed when its name and first arguments match Prefix word for word and
+// every further argument starting with "-" matches one of Options, so a
+// rule such as "docker logs --tail" cannot be stretched with --follow.
+type Rule struct {
+	Prefix []string
+	// Options are path.Match patterns, such as "--lines=*".
+	Options []string
+}
+
+// ParseRules reads each spec such as "systemctl status [--no-pager]" into a
+// Rule: bracketed words are allowed options, the others the prefix. Blank
+// specs are skipped.
+func ParseRules(specs []string) []Rule {
+	rules := make([]Rule, 0, len(specs))
+	for _, spec := range specs {
+		var rule Rule
+		for _, word := range strings.Fields(spec) {
+			if opt, ok := strings.CutPrefix(word, "["); ok && strings.HasSuffix(opt, "]") {
+				rule.Options = append(rule.Options, strings.TrimSuffix(opt, "]"))
+				continue
+			}
+			rule.Prefix = append(rule.Prefix, word)
+		}
+		if len(rule.Prefix) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Allowed reports whether name and args match one of rules.
+func Allowed(rules []Rule, name string, args []string) bool {
+	argv := append([]string{name}, args...)
+	for _, rule := range rules {
+		if rule.matches(argv) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matches(argv []string) bool {
+	if len(r.Prefix) > len(argv) {
+		return false
+	}
+	for i, word := range r.Prefix {
+		if argv[i] != word {
+			return false
+		}
+	}
+	for _, arg := range argv[len(r.Prefix):] {
+		if strings.HasPrefix(arg, "-") && !r.allowsOption(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) allowsOption(arg string) bool {
+	for _, pattern := range r.Options {
+		if ok, _ := path.Match(pattern, arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) String() string {
+	words := append([]string(nil), r.Prefix...)
+	for _, opt := range r.Options {
+		words = append(words, "["+opt+"]")
+	}
+	return strings.Join(words, " ")
+}
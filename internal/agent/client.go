@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+var hostNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ParseHost splits an AGENT_HOSTS entry "name=http://addr:port" and
+// validates both halves.
+func ParseHost(spec string) (name, url string, err error) {
+	name, url, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	if !ok || name == "" || url == "" {
+		return "", "", fmt.Errorf("%q: want name=http://host:port", spec)
+	}
+	if !hostNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("%q: host name may only contain a-z, 0-9, _ and -", spec)
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", "", fmt.Errorf("%q: agent URL must start with http:// or https://", spec)
+	}
+	return name, url, nil
+}
+
+// Client talks to one agent. It is a commands.StatsHost and its Runner
+// forwards commands to the agent.
+type Client struct {
+	name    string
+	baseURL string
+	token   string
+
+	HTTPClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient returns a client for the agent called name at baseURL.
+func NewClient(name, baseURL, token string) *Client {
+	return &Client{
+		name:       name,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// Name returns the host name used in "@name" selectors and alerts.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Runner returns c, which runs commands through the agent.
+func (c *Client) Runner() system.Runner {
+	return c
+}
+
+// Stats returns the agent's latest snapshot, or a new one when fresh.
+func (c *Client) Stats(ctx context.Context, fresh bool) (metrics.Snapshot, error) {
+	var wire wireSnapshot
+	if err := c.call(ctx, pathStats, statsRequest{Fresh: fresh}, &wire); err != nil {
+		return metrics.Snapshot{}, err
+	}
+	snap := fromWire(wire)
+	snap.Host = c.name
+	return snap, nil
+}
+
+// Run executes an allowlisted command on the agent's host.
+func (c *Client) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	var resp runResponse
+	if err := c.call(ctx, pathRun, runRequest{Name: name, Args: args}, &resp); err != nil {
+		return "", "", err
+	}
+	if resp.Error != "" {
+		return resp.Stdout, resp.Stderr, fmt.Errorf("agent %s: %s", c.name, resp.Error)
+	}
+	return resp.Stdout, resp.Stderr, nil
+}
+
+// call posts a signed request and decodes the verified response into dst.
+func (c *Client) call(ctx context.Context, path string, in, dst any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	ts := strconv.FormatInt(c.clock().Unix(), 10)
+	signature := sign(c.token, http.MethodPost, path, string(body), ts)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", c.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerTimestamp, ts)
+	req.Header.Set(headerSignature, signature)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return fmt.Errorf("agent %s: read response: %w", c.name, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("agent %s: request rejected (check AGENT_TOKEN and clocks)", c.name)
+	}
+	if err := verify(c.token, resp.Header.Get(headerSignature), resp.Header.Get(headerTimestamp), c.clock(), signature, string(data)); err != nil {
+		return fmt.Errorf("agent %s: unverified response: %w", c.name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.Unmarshal(data, &e) != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("agent %s: %s", c.name, e.Error)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("agent %s: decode response: %w", c.name, err)
+	}
+	return nil
+}
+
+func (c *Client) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,123 @@
+// Package agent implements the agent mode of serverbot: a small HTTP server
+// that reports metrics and runs an allowlisted set of commands for the main
+// bot, and the client the bot uses to reach it.
+//
+// Every request and response is signed with HMAC-SHA256 over a shared token.
+// A request signs its method, path, Unix timestamp and body; the response
+// signs the request signature, its own timestamp and its body, so a reply
+// cannot be replayed against a different request.
+package agent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/metrics"
+)
+
+const (
+	headerTimestamp = "X-Serverbot-Timestamp"
+	headerSignature = "X-Serverbot-Signature"
+
+	pathStats = "/v1/stats"
+	pathRun   = "/v1/run"
+
+	// MaxSkew is how far a signed timestamp may be from the local clock.
+	MaxSkew = 30 * time.Second
+
+	// maxBody bounds request and response bodies.
+	maxBody = 8 << 20
+)
+
+var (
+	errBadSignature = errors.New("bad signature")
+	errStale        = errors.New("timestamp outside the allowed skew")
+)
+
+// sign returns the hex HMAC-SHA256 of the newline-joined parts.
+func sign(token string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks a signature and that its timestamp is within MaxSkew of now.
+func verify(token, signature, timestamp string, now time.Time, parts ...string) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > MaxSkew || skew < -MaxSkew {
+		return errStale
+	}
+	want := sign(token, append(parts, timestamp)...)
+	if !hmac.Equal([]byte(signature), []byte(want)) {
+		return errBadSignature
+	}
+	return nil
+}
+
+type statsRequest struct {
+	Fresh bool `json:"fresh"`
+}
+
+type runRequest struct {
+	Name string   `json:"name"`
+	Args []string `json:"args"`
+}
+
+type runResponse struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// wireSnapshot carries a metrics.Snapshot; source errors travel as strings.
+type wireSnapshot struct {
+	Host    string        `json:"host"`
+	At      time.Time     `json:"at"`
+	Stats   metrics.Stats `json:"stats"`
+	Sources []wireSource  `json:"sources"`
+}
+
+type wireSource struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+func toWire(snap metrics.Snapshot) wireSnapshot {
+	out := wireSnapshot{Host: snap.Host, At: snap.At, Stats: snap.Stats}
+	out.Stats.Sources = nil
+	for _, src := range snap.Stats.Sources {
+		ws := wireSource{Name: src.Name, Duration: src.Duration}
+		if src.Err != nil {
+			ws.Error = src.Err.Error()
+		}
+		out.Sources = append(out.Sources, ws)
+	}
+	return out
+}
+
+func fromWire(in wireSnapshot) metrics.Snapshot {
+	snap := metrics.Snapshot{Host: in.Host, At: in.At, Stats: in.Stats}
+	for _, src := range in.Sources {
+		report := metrics.SourceReport{Name: src.Name, Duration: src.Duration}
+		if src.Error != "" {
+			report.Err = errors.New(src.Error)
+		}
+		snap.Stats.Sources = append(snap.Stats.Sources, report)
+	}
+	return snap
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"log"
+
+	"serverbot/internal/app"
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+// Run starts agent mode: it samples this host in the background and serves
+// the agent protocol on AGENT_LISTEN_ADDR until ctx is cancelled.
+func Run(ctx context.Context, logger *log.Logger) error {
+	cfg, err := app.LoadAgentConfig()
+	if err != nil {
+		return err
+	}
+
+	collector := metrics.NewCollector(metrics.Options{
+		DiskTargets:   cfg.DiskTargets,
+		NetInclude:    cfg.NetInclude,
+		NetExclude:    cfg.NetExclude,
+		SourceTimeout: cfg.MetricsSourceTimeout,
+	})
+	sampler := &metrics.Sampler{
+		Collector: collector,
+		Interval:  cfg.MetricsSampleInterval,
+		Timeout:   cfg.CommandTimeout + 2*collector.SampleInterval(),
+		Logger:    logger,
+	}
+	go sampler.Run(ctx)
+
+	allow := cfg.Allow
+	if len(allow) == 0 {
+		allow = DefaultAllow
+	}
+	srv := &Server{
+		Name:    cfg.Name,
+		Token:   cfg.Token,
+		Sampler: sampler,
+		Runner:  system.NewCommandRunner(),
+		Allow:   ParseRules(allow),
+		Timeout: cfg.CommandTimeout,
+		Logger:  logger,
+	}
+
+	logger.Printf("Agente %s escuchando en %s", cfg.Name, cfg.ListenAddr)
+	return srv.ListenAndServe(ctx, cfg.ListenAddr)
+}
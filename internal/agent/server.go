@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+// Server answers the main bot on behalf of one host.
+type Server struct {
+	// Name is the host name reported in snapshots.
+	Name    string
+	Token   string
+	Sampler *metrics.Sampler
+	Runner  system.Runner
+	// Allow lists the commands /v1/run may execute.
+	Allow []Rule
+	// Timeout bounds a command or a fresh sample.
+	Timeout time.Duration
+	Logger  *log.Logger
+
+	mu   sync.Mutex
+	seen map[string]time.Time // request signatures inside MaxSkew
+	now  func() time.Time
+}
+
+// Handler returns the HTTP handler serving the agent protocol.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+pathStats, s.handleStats)
+	mux.HandleFunc("POST "+pathRun, s.handleRun)
+	return mux
+}
+
+// ListenAndServe serves the agent protocol on addr until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	var req statsRequest
+	signature, ok := s.authenticate(w, r, &req)
+	if !ok {
+		return
+	}
+
+	snap, cached := s.Sampler.Latest()
+	if req.Fresh || !cached {
+		ctx, cancel := system.WithTimeout(r.Context(), s.Timeout)
+		defer cancel()
+		var err error
+		if snap, err = s.Sampler.Sample(ctx); err != nil {
+			s.reply(w, signature, http.StatusServiceUnavailable, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+	snap.Host = s.Name
+	s.reply(w, signature, http.StatusOK, toWire(snap))
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	signature, ok := s.authenticate(w, r, &req)
+	if !ok {
+		return
+	}
+
+	if req.Name == "" || !Allowed(s.Allow, req.Name, req.Args) {
+		s.log("refused command %q", append([]string{req.Name}, req.Args...))
+		s.reply(w, signature, http.StatusForbidden, errorResponse{Error: "command not allowed"})
+		return
+	}
+
+	ctx, cancel := system.WithTimeout(r.Context(), s.Timeout)
+	defer cancel()
+
+	stdout, stderr, err := s.Runner.Run(ctx, req.Name, req.Args...)
+	resp := runResponse{Stdout: stdout, Stderr: stderr}
+	if err != nil {
+		resp.Error = err.Error()
+		resp.ExitCode = -1
+		var exitErr *system.ExitError
+		if errors.As(err, &exitErr) {
+			resp.ExitCode = exitErr.Code
+		}
+	}
+	s.reply(w, signature, http.StatusOK, resp)
+}
+
+// authenticate verifies the request signature, rejects replays and decodes
+// the body into dst. It returns the request signature for the reply.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request, dst any) (string, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return "", false
+	}
+
+	signature := r.Header.Get(headerSignature)
+	now := s.clock()
+	if err := verify(s.Token, signature, r.Header.Get(headerTimestamp), now, r.Method, r.URL.Path, string(body)); err != nil {
+		s.log("rejected %s from %s: %v", r.URL.Path, r.RemoteAddr, err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+	if s.replayed(signature, now) {
+		s.log("rejected replayed %s from %s", r.URL.Path, r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return "", false
+	}
+	return signature, true
+}
+
+// replayed records signature and reports whether it was already used.
+// Signatures older than twice MaxSkew can no longer verify and are dropped.
+func (s *Server) replayed(signature string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen == nil {
+		s.seen = make(map[string]time.Time)
+	}
+	for sig, at := range s.seen {
+		if now.Sub(at) > 2*MaxSkew {
+			delete(s.seen, sig)
+		}
+	}
+	if _, ok := s.seen[signature]; ok {
+		return true
+	}
+	s.seen[signature] = now
+	return false
+}
+
+// reply writes v as JSON, signed against the request signature.
+func (s *Server) reply(w http.ResponseWriter, requestSignature string, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		s.log("encode response: %v", err)
+		http.Error(w, "encode response", http.StatusInternalServerError)
+		return
+	}
+
+	ts := strconv.FormatInt(s.clock().Unix(), 10)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(headerTimestamp, ts)
+	w.Header().Set(headerSignature, sign(s.Token, requestSignature, string(body), ts))
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		s.log("write response: %v", err)
+	}
+}
+
+func (s *Server) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *Server) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("agent: "+format, args...)
+	}
+}
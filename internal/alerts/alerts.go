@@ -0,0 +1,200 @@
+package alerts
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"serverbot/internal/i18n"
+)
+
+// historyRetention bounds how long fired/resolved events are kept in memory.
+const historyRetention = 8 * 24 * time.Hour
+
+// SendFunc delivers an alert message to its destination.
+type SendFunc func(message string) error
+
+// Event records a state change of an alert key. Message is in the default
+// language.
+type Event struct {
+	Key      string
+	Message  string
+	Resolved bool
+	At       time.Time
+}
+
+// Notifier sends alerts with a per-key cooldown and remembers which keys are
+// currently firing so resolutions can be detected and reported.
+type Notifier struct {
+	mu       sync.Mutex
+	send     SendFunc
+	cooldown time.Duration
+	lastSent map[string]time.Time
+	active   map[string]bool
+	history  []Event
+	observer func(Event)
+	language func() string
+	now      func() time.Time
+}
+
+// NewNotifier builds a Notifier that delivers messages through send.
+func NewNotifier(cooldown time.Duration, send SendFunc) *Notifier {
+	return &Notifier{
+		send:     send,
+		cooldown: cooldown,
+		lastSent: make(map[string]time.Time),
+		active:   make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+// SetCooldown changes the cooldown applied to subsequent alerts.
+func (n *Notifier) SetCooldown(cooldown time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.cooldown = cooldown
+}
+
+// SetLanguage makes fn pick the language of every message at delivery, so a
+// change of the recipient's language applies to the next alert. Without it
+// messages are in the default language.
+func (n *Notifier) SetLanguage(fn func() string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.language = fn
+}
+
+// OnEvent registers fn to be called for every fired or resolved event.
+func (n *Notifier) OnEvent(fn func(Event)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observer = fn
+}
+
+// Fire marks key as firing and sends message unless the same key was sent
+// within the cooldown window. The cooldown only starts after a successful send.
+func (n *Notifier) Fire(key string, message i18n.Text) error {
+	n.mu.Lock()
+	now := n.now()
+	if !n.active[key] {
+		n.active[key] = true
+		n.record(Event{Key: key, Message: render(message, i18n.Default()), At: now})
+	}
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < n.cooldown {
+		n.mu.Unlock()
+		return nil
+	}
+	n.mu.Unlock()
+
+	if err := n.deliver(message); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.lastSent[key] = now
+	n.mu.Unlock()
+	return nil
+}
+
+// Resolve clears a firing key. When message is not nil it is sent as a
+// recovery notice and the next incident is not held back by the cooldown; a
+// silent resolve keeps the cooldown so a value hovering around its threshold
+// does not re-alert on every check. Resolving a key that is not firing is a
+// no-op.
+func (n *Notifier) Resolve(key string, message i18n.Text) error {
+	n.mu.Lock()
+	if !n.active[key] {
+		n.mu.Unlock()
+		return nil
+	}
+	delete(n.active, key)
+	if message != nil {
+		delete(n.lastSent, key)
+	}
+	n.record(Event{Key: key, Message: render(message, i18n.Default()), Resolved: true, At: n.now()})
+	n.mu.Unlock()
+
+	if message == nil {
+		return nil
+	}
+	return n.deliver(message)
+}
+
+// Active reports whether key is currently firing.
+func (n *Notifier) Active(key string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.active[key]
+}
+
+// ActiveKeys returns the firing keys that start with prefix, sorted.
+func (n *Notifier) ActiveKeys(prefix string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var keys []string
+	for key := range n.active {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Events returns the fired and resolved events recorded at or after since.
+func (n *Notifier) Events(since time.Time) []Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var out []Event
+	for _, ev := range n.history {
+		if !ev.At.Before(since) {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func (n *Notifier) deliver(message i18n.Text) error {
+	n.mu.Lock()
+	send, language := n.send, n.language
+	n.mu.Unlock()
+	if send == nil {
+		return nil
+	}
+	lang := i18n.Default()
+	if language != nil {
+		lang = language()
+	}
+	text := render(message, lang)
+	if text == "" {
+		return nil
+	}
+	return send(text)
+}
+
+func render(message i18n.Text, lang string) string {
+	if message == nil {
+		return ""
+	}
+	return message(lang)
+}
+
+// record appends an event and prunes old history. Callers hold n.mu.
+func (n *Notifier) record(ev Event) {
+	n.history = append(n.history, ev)
+	if n.observer != nil {
+		n.observer(ev)
+	}
+
+	cutoff := ev.At.Add(-historyRetention)
+	drop := 0
+	for drop < len(n.history) && n.history[drop].At.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		n.history = append([]Event(nil), n.history[drop:]...)
+	}
+}
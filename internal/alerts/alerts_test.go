@@ -0,0 +1,149 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"serverbot/internal/i18n"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestNotifierCooldownAndResolve(t *testing.T) {
+	var sent []string
+	n := NewNotifier(5*time.Minute, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	c := &clock{now: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	n.now = c.Now
+
+	if err := n.Fire("cpu", i18n.Raw("cpu high")); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	c.now = c.now.Add(time.Minute)
+	_ = n.Fire("cpu", i18n.Raw("cpu high again"))
+	if len(sent) != 1 {
+		t.Fatalf("sent = %v, want single message inside cooldown", sent)
+	}
+	if !n.Active("cpu") {
+		t.Fatalf("cpu should be active")
+	}
+
+	c.now = c.now.Add(time.Minute)
+	if err := n.Resolve("cpu", i18n.Raw("cpu ok")); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if n.Active("cpu") {
+		t.Fatalf("cpu should be resolved")
+	}
+	if err := n.Resolve("cpu", i18n.Raw("cpu ok")); err != nil {
+		t.Fatalf("second Resolve() error = %v", err)
+	}
+	if len(sent) != 2 || sent[1] != "cpu ok" {
+		t.Fatalf("sent = %v, want recovery notice once", sent)
+	}
+
+	// A new incident is not held back by the previous cooldown.
+	c.now = c.now.Add(time.Minute)
+	_ = n.Fire("cpu", i18n.Raw("cpu high"))
+	if len(sent) != 3 {
+		t.Fatalf("sent = %v, want re-fired alert", sent)
+	}
+
+	events := n.Events(time.Time{})
+	if len(events) != 3 {
+		t.Fatalf("events = %+v, want fired, resolved, fired", events)
+	}
+	if events[0].Resolved || !events[1].Resolved || events[2].Resolved {
+		t.Fatalf("unexpected event sequence %+v", events)
+	}
+}
+
+func TestNotifierSilentResolveKeepsCooldown(t *testing.T) {
+	var sent []string
+	n := NewNotifier(5*time.Minute, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	c := &clock{now: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	n.now = c.Now
+
+	// A value hovering around its threshold fires and resolves every check.
+	for i := 0; i < 4; i++ {
+		if err := n.Fire("cpu", i18n.Raw("cpu high")); err != nil {
+			t.Fatalf("Fire() error = %v", err)
+		}
+		c.now = c.now.Add(time.Minute)
+		if err := n.Resolve("cpu", nil); err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		c.now = c.now.Add(time.Second)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent = %v, want a single alert inside the cooldown", sent)
+	}
+
+	c.now = c.now.Add(5 * time.Minute)
+	_ = n.Fire("cpu", i18n.Raw("cpu high"))
+	if len(sent) != 2 {
+		t.Fatalf("sent = %v, want a new alert once the cooldown passed", sent)
+	}
+}
+
+func TestNotifierRetriesAfterSendError(t *testing.T) {
+	fail := true
+	var attempts int
+	n := NewNotifier(time.Hour, func(string) error {
+		attempts++
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if err := n.Fire("disk:/", i18n.Raw("disk")); err == nil {
+		t.Fatalf("expected send error")
+	}
+	fail = false
+	if err := n.Fire("disk:/", i18n.Raw("disk")); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if keys := n.ActiveKeys("disk:"); len(keys) != 1 || keys[0] != "disk:/" {
+		t.Fatalf("ActiveKeys() = %v", keys)
+	}
+	if len(n.Events(time.Time{})) != 1 {
+		t.Fatalf("failed sends should not duplicate the fired event")
+	}
+}
+
+func TestNotifierRendersInLanguageAtDelivery(t *testing.T) {
+	var sent []string
+	n := NewNotifier(0, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	lang := "en"
+	n.SetLanguage(func() string { return lang })
+	message := func(lang string) string { return lang + ": disk" }
+
+	if err := n.Fire("disk:/", message); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	lang = "es"
+	if err := n.Resolve("disk:/", message); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(sent) != 2 || sent[0] != "en: disk" || sent[1] != "es: disk" {
+		t.Fatalf("sent = %v", sent)
+	}
+	if ev := n.Events(time.Time{}); ev[0].Message != i18n.Default()+": disk" {
+		t.Fatalf("event message = %q, want the default language", ev[0].Message)
+	}
+}
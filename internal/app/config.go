@@ -1,12 +1,14 @@
 package app
 
 import (
-	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"serverbot/internal/redact"
 )
 
 // Config groups together the configuration required by the bot.
@@ -18,6 +20,11 @@ type Config struct {
 	DiskTargets    []string
 	Alerts         AlertConfig
 
+	// NetInclude and NetExclude are interface globs passed to the metrics
+	// collector (see metrics.Options).
+	NetInclude []string
+	NetExclude []string
+
 	// TelegramAPIURL overrides the Telegram Bot API endpoint (local sidecar).
 	TelegramAPIURL string
 
@@ -26,6 +33,85 @@ type Config struct {
 	RevancedServeDir     string // Directory where built APKs are copied for serving.
 	RevancedNginxBaseURL string // Public base URL exposed by Nginx for downloads.
 	RevancedStateFile    string // Path to the JSON state file (flock-protected).
+
+	// ScheduleFile is the JSON file where scheduled commands are persisted.
+	ScheduleFile string
+	// CommandsFile is the YAML file defining custom commands, re-read on
+	// SIGHUP.
+	CommandsFile string
+
+	// Language is the language of chats that chose none with /lang and of
+	// alerts and digests; empty means Spanish.
+	Language string
+	// LanguageFile is the JSON file where /lang choices are persisted.
+	LanguageFile string
+
+	Digest DigestConfig
+	Uptime UptimeConfig
+	Certs  CertConfig
+
+	Bandwidth BandwidthConfig
+
+	// MetricsListenAddr enables the Prometheus /metrics endpoint (e.g. ":9101").
+	MetricsListenAddr string
+	// MetricsSourceTimeout bounds each metrics source beyond the one-second
+	// sampling window.
+	MetricsSourceTimeout time.Duration
+	// MetricsSampleInterval is how often the background sampler refreshes
+	// the snapshot shared by /stats, alerts, the digest and the exporter.
+	MetricsSampleInterval time.Duration
+
+	// AuditLogFile receives one JSON line per privileged action (/kill, /renice,
+	// service control).
+	AuditLogFile string
+	// ProtectedProcesses extends the built-in list of process names that
+	// /kill and /renice refuse to touch.
+	ProtectedProcesses []string
+
+	// ServiceAllowOwner and ServiceAllowAdmin are glob patterns of the systemd
+	// units each role may start, stop, restart or enable. An empty owner list
+	// allows every unit; an empty admin list allows none.
+	ServiceAllowOwner []string
+	ServiceAllowAdmin []string
+
+	// AgentHosts are "name=http://addr:port" agents that commands reach
+	// with "@name" and that alerts poll.
+	AgentHosts []string
+	// AgentToken is the secret shared with every agent to sign requests.
+	AgentToken string
+
+	SSH SSHConfig
+
+	// settings are the raw values behind the config, for Settings and
+	// RestartRequired.
+	settings []Setting
+}
+
+// SSHConfig lists hosts whose commands run over SSH, without an agent.
+type SSHConfig struct {
+	// Hosts are "name=user@host[:port]" entries reachable with "@name".
+	Hosts   []string
+	KeyFile string
+	// KnownHosts is verified strictly; unknown host keys are refused.
+	KnownHosts string
+}
+
+// AgentConfig configures "serverbot agent", which serves one host's metrics
+// and an allowlisted set of commands to the main bot.
+type AgentConfig struct {
+	// Name is reported in snapshots; it defaults to the hostname.
+	Name       string
+	ListenAddr string
+	Token      string
+	// Allow lists the command rules the agent may run, e.g. "docker ps [--format]".
+	// Empty means the agent's built-in read-only set.
+	Allow                 []string
+	CommandTimeout        time.Duration
+	DiskTargets           []string
+	NetInclude            []string
+	NetExclude            []string
+	MetricsSourceTimeout  time.Duration
+	MetricsSampleInterval time.Duration
 }
 
 // AlertConfig contains settings for automatic alert notifications.
@@ -36,6 +122,55 @@ type AlertConfig struct {
 	CPUThreshold    float64
 	MemoryThreshold float64
 	DiskThreshold   float64
+	// TempThreshold is the sensor temperature in ºC that raises an alert;
+	// a reading at its chip's critical limit alerts too. 0 disables both.
+	TempThreshold float64
+	FailedUnits   bool
+	JournalUnits  []string
+	// JournalPriority is the journalctl priority name or number (default "err")
+	// at or above which JournalUnits entries raise an alert.
+	JournalPriority string
+}
+
+// UptimeConfig lists the external checks probed by the uptime monitor.
+type UptimeConfig struct {
+	// Checks are "[name=]target" specs: an http(s) URL, tcp://host:port or
+	// icmp://host.
+	Checks   []string
+	Interval time.Duration
+	Timeout  time.Duration
+	// Failures is the number of consecutive failed probes before alerting.
+	Failures int
+}
+
+// CertConfig lists the TLS certificates checked for expiry.
+type CertConfig struct {
+	Hosts    []string // host[:port], port 443 by default
+	Files    []string // PEM paths or glob patterns
+	Interval time.Duration
+}
+
+// BandwidthConfig controls traffic accounting. It is enabled by File.
+type BandwidthConfig struct {
+	File     string
+	Interval time.Duration
+	// Quota is the monthly cap in bytes; 0 disables the quota alert.
+	Quota uint64
+	// QuotaDirection is "total" (default), "out" or "in".
+	QuotaDirection   string
+	QuotaWarnPercent float64
+}
+
+// DigestConfig controls the periodic summary report.
+type DigestConfig struct {
+	Enabled        bool
+	Hour           int
+	Minute         int
+	Weekly         bool
+	WeeklyDay      time.Weekday
+	Location       *time.Location
+	ChatIDs        []int64
+	SampleInterval time.Duration
 }
 
 const (
@@ -43,69 +178,321 @@ const (
 	defaultDiskTargets    = "/"
 )
 
-// LoadConfig reads the necessary environment variables and returns a validated Config.
+// LoadConfig reads the configuration from the environment and, when
+// CONFIG_FILE is set, from that YAML or TOML file; environment variables take
+// precedence. Secrets may also come from NAME_FILE or a systemd credential.
+// Every invalid setting is reported in the returned error.
 func LoadConfig() (Config, error) {
-	token := strings.TrimSpace(os.Getenv("TELEGRAM_BOT_TOKEN"))
-	adminIDs := strings.TrimSpace(os.Getenv("ADMIN_IDS"))
-	ownerStr := strings.TrimSpace(os.Getenv("OWNER_ID"))
-	fallbackOwner := strings.TrimSpace(os.Getenv("ADMIN_ID"))
-	diskTargets := strings.TrimSpace(os.Getenv("DISK_TARGETS"))
-	enableAlerts := strings.TrimSpace(os.Getenv("ENABLE_ALERTS"))
-	alertInterval := strings.TrimSpace(os.Getenv("ALERT_INTERVAL"))
-	alertCooldown := strings.TrimSpace(os.Getenv("ALERT_COOLDOWN"))
-	alertCPU := strings.TrimSpace(os.Getenv("ALERT_CPU_THRESHOLD"))
-	alertMem := strings.TrimSpace(os.Getenv("ALERT_MEMORY_THRESHOLD"))
-	alertDisk := strings.TrimSpace(os.Getenv("ALERT_DISK_THRESHOLD"))
+	src := newSource()
 
+	token := src.get("TELEGRAM_BOT_TOKEN")
 	if token == "" {
-		return Config{}, errors.New("missing TELEGRAM_BOT_TOKEN")
+		src.errorf("missing TELEGRAM_BOT_TOKEN")
 	}
+
+	var ownerID int64
+	ownerStr := src.get("OWNER_ID")
 	if ownerStr == "" {
-		ownerStr = fallbackOwner
+		ownerStr = src.get("ADMIN_ID")
 	}
 	if ownerStr == "" {
-		return Config{}, errors.New("missing OWNER_ID")
+		src.errorf("missing OWNER_ID")
+	} else if id, err := strconv.ParseInt(ownerStr, 10, 64); err != nil {
+		src.errorf("invalid OWNER_ID: %v", err)
+	} else {
+		ownerID = id
 	}
 
-	ownerID, err := strconv.ParseInt(ownerStr, 10, 64)
-	if err != nil {
-		return Config{}, fmt.Errorf("invalid OWNER_ID: %w", err)
+	cfg := Config{
+		Token:                 token,
+		OwnerID:               ownerID,
+		AdminIDs:              src.ids("ADMIN_IDS"),
+		CommandTimeout:        defaultCommandTimeout,
+		DiskTargets:           parseDiskTargets(src.get("DISK_TARGETS")),
+		NetInclude:            src.list("NET_INCLUDE"),
+		NetExclude:            src.list("NET_EXCLUDE"),
+		TelegramAPIURL:        src.get("TELEGRAM_BOT_API_URL"),
+		RevancedRepo:          src.get("REVANCED_REPO"),
+		RevancedServeDir:      src.get("REVANCED_SERVE_DIR"),
+		RevancedNginxBaseURL:  src.get("REVANCED_NGINX_BASE_URL"),
+		RevancedStateFile:     src.get("REVANCED_STATE_FILE"),
+		ScheduleFile:          src.get("SCHEDULE_FILE"),
+		CommandsFile:          src.get("COMMANDS_FILE"),
+		Language:              src.get("BOT_LANGUAGE"),
+		LanguageFile:          src.get("LANGUAGE_FILE"),
+		MetricsListenAddr:     src.get("METRICS_LISTEN_ADDR"),
+		MetricsSourceTimeout:  src.duration("METRICS_SOURCE_TIMEOUT", 5*time.Second),
+		MetricsSampleInterval: src.duration("METRICS_SAMPLE_INTERVAL", 30*time.Second),
+		AuditLogFile:          src.get("AUDIT_LOG_FILE"),
+		ProtectedProcesses:    src.list("PROTECTED_PROCESSES"),
+		ServiceAllowOwner:     src.list("SERVICE_ALLOWLIST_OWNER"),
+		ServiceAllowAdmin:     src.list("SERVICE_ALLOWLIST_ADMIN"),
+		AgentHosts:            src.list("AGENT_HOSTS"),
+		AgentToken:            src.get("AGENT_TOKEN"),
+		Alerts: AlertConfig{
+			Enabled:         src.bool("ENABLE_ALERTS"),
+			Interval:        src.duration("ALERT_INTERVAL", time.Minute),
+			Cooldown:        src.duration("ALERT_COOLDOWN", 5*time.Minute),
+			CPUThreshold:    src.percent("ALERT_CPU_THRESHOLD", 90),
+			MemoryThreshold: src.percent("ALERT_MEMORY_THRESHOLD", 90),
+			DiskThreshold:   src.percent("ALERT_DISK_THRESHOLD", 90),
+			TempThreshold:   src.float("ALERT_TEMP_THRESHOLD", 85),
+			FailedUnits:     src.bool("ALERT_FAILED_UNITS"),
+			JournalUnits:    src.list("JOURNAL_ALERT_UNITS"),
+			JournalPriority: src.get("JOURNAL_ALERT_PRIORITY"),
+		},
+		SSH:       parseSSH(src),
+		Uptime:    parseUptime(src),
+		Bandwidth: parseBandwidth(src),
+		Digest:    parseDigest(src, ownerID),
+		Certs: CertConfig{
+			Hosts:    src.list("CERT_HOSTS"),
+			Files:    src.list("CERT_FILES"),
+			Interval: src.duration("CERT_CHECK_INTERVAL", 24*time.Hour),
+		},
+		settings: src.settings(),
+	}
+
+	if len(cfg.AgentHosts) > 0 && cfg.AgentToken == "" {
+		src.errorf("AGENT_HOSTS requires AGENT_TOKEN")
+	}
+	if cfg.Alerts.JournalPriority == "" {
+		cfg.Alerts.JournalPriority = "err"
+	}
+
+	if err := src.err(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// LoadAgentConfig reads the configuration for agent mode, from the
+// environment and CONFIG_FILE like LoadConfig. It needs no Telegram
+// settings, only AGENT_TOKEN.
+func LoadAgentConfig() (AgentConfig, error) {
+	src := newSource()
+	cfg := AgentConfig{
+		Name:                  strings.ToLower(src.get("AGENT_NAME")),
+		ListenAddr:            src.get("AGENT_LISTEN_ADDR"),
+		Token:                 src.get("AGENT_TOKEN"),
+		Allow:                 src.list("AGENT_ALLOW"),
+		CommandTimeout:        defaultCommandTimeout,
+		DiskTargets:           parseDiskTargets(src.get("DISK_TARGETS")),
+		NetInclude:            src.list("NET_INCLUDE"),
+		NetExclude:            src.list("NET_EXCLUDE"),
+		MetricsSourceTimeout:  src.duration("METRICS_SOURCE_TIMEOUT", 5*time.Second),
+		MetricsSampleInterval: src.duration("METRICS_SAMPLE_INTERVAL", 30*time.Second),
+	}
+	if cfg.Token == "" {
+		src.errorf("missing AGENT_TOKEN")
+	}
+	if cfg.Name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			src.errorf("AGENT_NAME unset and hostname unavailable: %v", err)
+		}
+		cfg.Name = strings.ToLower(host)
+	}
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = ":9102"
+	}
+	if err := src.err(); err != nil {
+		return AgentConfig{}, err
+	}
+	return cfg, nil
+}
+
+// Settings lists the settings given in the environment or CONFIG_FILE, with
+// secrets and credentials in any value, such as a URL's user:pass@,
+// redacted. Unlisted settings use their defaults.
+func (c Config) Settings() []Setting {
+	out := make([]Setting, len(c.settings))
+	for i, s := range c.settings {
+		if k, _ := lookupKey(s.Key); k.secret {
+			s.Value = redact.Mask
+		} else {
+			s.Value = redact.String(s.Value)
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// Reload returns c with the reloadable settings taken from next: roles, disk
+// targets and the alert switch, timing and thresholds. Everything else keeps
+// the value c has, since it is only read at startup.
+func (c Config) Reload(next Config) Config {
+	c.OwnerID = next.OwnerID
+	c.AdminIDs = next.AdminIDs
+	c.DiskTargets = next.DiskTargets
+	c.Language = next.Language
+	c.Alerts.Enabled = next.Alerts.Enabled
+	c.Alerts.Interval = next.Alerts.Interval
+	c.Alerts.Cooldown = next.Alerts.Cooldown
+	c.Alerts.CPUThreshold = next.Alerts.CPUThreshold
+	c.Alerts.MemoryThreshold = next.Alerts.MemoryThreshold
+	c.Alerts.DiskThreshold = next.Alerts.DiskThreshold
+	c.Alerts.TempThreshold = next.Alerts.TempThreshold
+	c.settings = next.settings
+	return c
+}
+
+// RestartRequired returns the settings that differ between c and next but
+// are only read at startup, such as the bot token or the list of hosts.
+func (c Config) RestartRequired(next Config) []string {
+	values := func(cfg Config) map[string]string {
+		m := make(map[string]string, len(cfg.settings))
+		for _, s := range cfg.settings {
+			m[s.Key] = s.Value
+		}
+		return m
+	}
+	before, after := values(c), values(next)
+
+	var changed []string
+	for _, k := range keys {
+		if !k.reload && before[k.name] != after[k.name] {
+			changed = append(changed, k.name)
+		}
+	}
+	return changed
+}
+
+func parseSSH(src *source) SSHConfig {
+	ssh := SSHConfig{
+		Hosts:      src.list("SSH_HOSTS"),
+		KeyFile:    src.get("SSH_KEY_FILE"),
+		KnownHosts: src.get("SSH_KNOWN_HOSTS"),
+	}
+	if len(ssh.Hosts) == 0 {
+		return ssh
+	}
+	if ssh.KeyFile == "" {
+		src.errorf("SSH_HOSTS requires SSH_KEY_FILE")
+	}
+	if ssh.KnownHosts == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			src.errorf("SSH_KNOWN_HOSTS unset and home directory unavailable: %v", err)
+			return ssh
+		}
+		ssh.KnownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	return ssh
+}
+
+func parseUptime(src *source) UptimeConfig {
+	uptime := UptimeConfig{
+		Checks:   src.list("UPTIME_CHECKS"),
+		Interval: src.duration("UPTIME_INTERVAL", time.Minute),
+		Timeout:  src.duration("UPTIME_TIMEOUT", 10*time.Second),
+		Failures: 3,
+	}
+	if raw := src.get("UPTIME_FAILURES"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			src.errorf("invalid UPTIME_FAILURES %q: want a positive integer", raw)
+		} else {
+			uptime.Failures = n
+		}
+	}
+	return uptime
+}
+
+func parseBandwidth(src *source) BandwidthConfig {
+	bw := BandwidthConfig{
+		File:             src.get("BANDWIDTH_FILE"),
+		Interval:         src.duration("BANDWIDTH_INTERVAL", time.Minute),
+		QuotaDirection:   src.get("BANDWIDTH_QUOTA_DIRECTION"),
+		QuotaWarnPercent: src.percent("BANDWIDTH_QUOTA_WARN", 90),
+	}
+	if raw := src.get("BANDWIDTH_MONTHLY_QUOTA"); raw != "" {
+		quota, err := parseBytes(raw)
+		if err != nil {
+			src.errorf("invalid BANDWIDTH_MONTHLY_QUOTA: %v", err)
+		}
+		bw.Quota = quota
+	}
+	return bw
+}
+
+// parseBytes reads sizes such as "500GB", "1.5T" or "2048" using binary
+// multiples, matching how sizes are displayed.
+func parseBytes(raw string) (uint64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := 1.0
+	if n := len(s); n > 0 {
+		if i := strings.IndexByte("KMGTP", s[n-1]); i >= 0 {
+			for ; i >= 0; i-- {
+				multiplier *= 1024
+			}
+			s = s[:n-1]
+		}
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("size %q: want a positive number with an optional K, M, G, T or P unit", raw)
+	}
+	return uint64(v * multiplier), nil
+}
+
+func parseDigest(src *source, ownerID int64) DigestConfig {
+	digest := DigestConfig{
+		Location:       time.Local,
+		ChatIDs:        []int64{ownerID},
+		SampleInterval: src.duration("DIGEST_SAMPLE_INTERVAL", 5*time.Minute),
+	}
+
+	sendTime := src.get("DIGEST_TIME")
+	if sendTime == "" {
+		return digest
 	}
 
-	adminIDList, err := parseAdminIDs(adminIDs)
+	parsed, err := time.Parse("15:04", sendTime)
 	if err != nil {
-		return Config{}, fmt.Errorf("invalid ADMIN_IDS: %w", err)
+		src.errorf("invalid DIGEST_TIME %q: want HH:MM", sendTime)
 	}
+	digest.Enabled = true
+	digest.Hour, digest.Minute = parsed.Hour(), parsed.Minute()
 
-	cfg := Config{
-		Token:                token,
-		OwnerID:              ownerID,
-		AdminIDs:             adminIDList,
-		CommandTimeout:       defaultCommandTimeout,
-		DiskTargets:          parseDiskTargets(diskTargets),
-		TelegramAPIURL:       strings.TrimSpace(os.Getenv("TELEGRAM_BOT_API_URL")),
-		RevancedRepo:         strings.TrimSpace(os.Getenv("REVANCED_REPO")),
-		RevancedServeDir:     strings.TrimSpace(os.Getenv("REVANCED_SERVE_DIR")),
-		RevancedNginxBaseURL: strings.TrimSpace(os.Getenv("REVANCED_NGINX_BASE_URL")),
-		RevancedStateFile:    strings.TrimSpace(os.Getenv("REVANCED_STATE_FILE")),
-		Alerts: AlertConfig{
-			Enabled:         parseBool(enableAlerts),
-			Interval:        parseDuration(alertInterval, time.Minute),
-			Cooldown:        parseDuration(alertCooldown, 5*time.Minute),
-			CPUThreshold:    parseFloat(alertCPU, 90),
-			MemoryThreshold: parseFloat(alertMem, 90),
-			DiskThreshold:   parseFloat(alertDisk, 90),
-		},
+	if tz := src.get("DIGEST_TIMEZONE"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			src.errorf("invalid DIGEST_TIMEZONE: %v", err)
+		} else {
+			digest.Location = loc
+		}
 	}
 
-	if cfg.Alerts.Interval <= 0 {
-		cfg.Alerts.Interval = time.Minute
+	if day := src.get("DIGEST_WEEKLY_DAY"); day != "" {
+		weekday, err := parseWeekday(day)
+		if err != nil {
+			src.errorf("invalid DIGEST_WEEKLY_DAY: %v", err)
+		}
+		digest.Weekly, digest.WeeklyDay = true, weekday
 	}
-	if cfg.Alerts.Cooldown <= 0 {
-		cfg.Alerts.Cooldown = 5 * time.Minute
+
+	if src.get("DIGEST_CHAT_IDS") != "" {
+		digest.ChatIDs = src.ids("DIGEST_CHAT_IDS")
 	}
+	return digest
+}
 
-	return cfg, nil
+func parseWeekday(raw string) (time.Weekday, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if raw == name || raw == name[:3] {
+			return d, nil
+		}
+	}
+	if n, err := strconv.Atoi(raw); err == nil && n >= 0 && n <= 7 {
+		return time.Weekday(n % 7), nil
+	}
+	return 0, fmt.Errorf("unknown weekday %q", raw)
 }
 
 func parseDiskTargets(raw string) []string {
@@ -127,6 +514,16 @@ func parseDiskTargets(raw string) []string {
 	return targets
 }
 
+func parseList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
 func parseAdminIDs(raw string) ([]int64, error) {
 	if raw == "" {
 		return nil, nil
@@ -146,35 +543,20 @@ func parseAdminIDs(raw string) ([]int64, error) {
 	return ids, nil
 }
 
-func parseBool(raw string) bool {
-	if raw == "" {
-		return false
-	}
-	v, err := strconv.ParseBool(raw)
-	if err != nil {
-		return strings.EqualFold(raw, "on") || strings.EqualFold(raw, "yes")
-	}
-	return v
-}
-
-func parseDuration(raw string, fallback time.Duration) time.Duration {
+// parseBool accepts true/false, yes/no, on/off and 1/0 in any case. An
+// empty value is false.
+func parseBool(raw string) (bool, bool) {
 	if raw == "" {
-		return fallback
+		return false, true
 	}
-	d, err := time.ParseDuration(raw)
-	if err != nil {
-		return fallback
+	if v, err := strconv.ParseBool(raw); err == nil {
+		return v, true
 	}
-	return d
-}
-
-func parseFloat(raw string, fallback float64) float64 {
-	if raw == "" {
-		return fallback
-	}
-	v, err := strconv.ParseFloat(raw, 64)
-	if err != nil {
-		return fallback
+	switch strings.ToLower(raw) {
+	case "on", "yes":
+		return true, true
+	case "off", "no":
+		return false, true
 	}
-	return v
+	return false, false
 }
@@ -0,0 +1,387 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/redact"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// key describes one configuration setting.
+type key struct {
+	name string
+	// secret values are never shown by Settings and are redacted from logs.
+	// They can also be read from NAME_FILE or from a systemd credential.
+	secret bool
+	// reload marks settings the bot applies again on SIGHUP or /reload.
+	reload bool
+}
+
+// keys lists every setting the bot and the agent read, in README order.
+var keys = []key{
+	{name: "TELEGRAM_BOT_TOKEN", secret: true},
+	{name: "OWNER_ID", reload: true},
+	{name: "ADMIN_ID", reload: true},
+	{name: "ADMIN_IDS", reload: true},
+	{name: "DISK_TARGETS", reload: true},
+	{name: "ENABLE_ALERTS", reload: true},
+	{name: "ALERT_INTERVAL", reload: true},
+	{name: "ALERT_COOLDOWN", reload: true},
+	{name: "ALERT_CPU_THRESHOLD", reload: true},
+	{name: "ALERT_MEMORY_THRESHOLD", reload: true},
+	{name: "ALERT_DISK_THRESHOLD", reload: true},
+	{name: "ALERT_TEMP_THRESHOLD", reload: true},
+	{name: "JOURNAL_ALERT_UNITS"},
+	{name: "JOURNAL_ALERT_PRIORITY"},
+	{name: "ALERT_FAILED_UNITS"},
+	{name: "UPTIME_CHECKS"},
+	{name: "UPTIME_INTERVAL"},
+	{name: "UPTIME_TIMEOUT"},
+	{name: "UPTIME_FAILURES"},
+	{name: "CERT_HOSTS"},
+	{name: "CERT_FILES"},
+	{name: "CERT_CHECK_INTERVAL"},
+	{name: "NET_INCLUDE"},
+	{name: "NET_EXCLUDE"},
+	{name: "BANDWIDTH_FILE"},
+	{name: "BANDWIDTH_INTERVAL"},
+	{name: "BANDWIDTH_MONTHLY_QUOTA"},
+	{name: "BANDWIDTH_QUOTA_DIRECTION"},
+	{name: "BANDWIDTH_QUOTA_WARN"},
+	{name: "TELEGRAM_BOT_API_URL"},
+	{name: "REVANCED_REPO"},
+	{name: "REVANCED_SERVE_DIR"},
+	{name: "REVANCED_NGINX_BASE_URL"},
+	{name: "REVANCED_STATE_FILE"},
+	{name: "DIGEST_TIME"},
+	{name: "DIGEST_TIMEZONE"},
+	{name: "DIGEST_WEEKLY_DAY"},
+	{name: "DIGEST_CHAT_IDS"},
+	{name: "DIGEST_SAMPLE_INTERVAL"},
+	{name: "METRICS_LISTEN_ADDR"},
+	{name: "METRICS_SAMPLE_INTERVAL"},
+	{name: "METRICS_SOURCE_TIMEOUT"},
+	{name: "SCHEDULE_FILE"},
+	{name: "COMMANDS_FILE"},
+	{name: "BOT_LANGUAGE", reload: true},
+	{name: "LANGUAGE_FILE"},
+	{name: "AUDIT_LOG_FILE"},
+	{name: "SERVICE_ALLOWLIST_OWNER"},
+	{name: "SERVICE_ALLOWLIST_ADMIN"},
+	{name: "PROTECTED_PROCESSES"},
+	{name: "AGENT_HOSTS"},
+	{name: "AGENT_TOKEN", secret: true},
+	{name: "AGENT_NAME"},
+	{name: "AGENT_LISTEN_ADDR"},
+	{name: "AGENT_ALLOW"},
+	{name: "SSH_HOSTS"},
+	{name: "SSH_KEY_FILE"},
+	{name: "SSH_KNOWN_HOSTS"},
+}
+
+func lookupKey(name string) (key, bool) {
+	for _, k := range keys {
+		if k.name == name {
+			return k, true
+		}
+	}
+	return key{}, false
+}
+
+// Setting is one configuration value as loaded, for display.
+type Setting struct {
+	Key   string
+	Value string
+	// Origin is "env" or the path of the file the value was read from: the
+	// config file, a NAME_FILE file or a systemd credential.
+	Origin string
+}
+
+// source resolves settings from the environment and then from CONFIG_FILE,
+// collecting every invalid value so they can be reported together.
+type source struct {
+	path string
+	file map[string]string
+	// secrets holds the secret settings read from NAME_FILE or a credential.
+	secrets map[string]Setting
+	errs    []error
+}
+
+// newSource reads CONFIG_FILE when it is set and the secret files. Problems
+// with the files are recorded like any other invalid setting.
+func newSource() *source {
+	s := &source{file: map[string]string{}, secrets: map[string]Setting{}}
+	s.readSecrets()
+	s.path = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	if s.path == "" {
+		return s
+	}
+
+	values, err := readConfigFile(s.path)
+	if err != nil {
+		s.errorf("CONFIG_FILE: %v", err)
+		return s
+	}
+	if err := flatten("", values, s.file); err != nil {
+		s.errorf("CONFIG_FILE %s: %v", s.path, err)
+	}
+	names := make([]string, 0, len(s.file))
+	for name := range s.file {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := lookupKey(name); !ok {
+			s.errorf("CONFIG_FILE %s: unknown setting %q", s.path, strings.ToLower(name))
+		}
+	}
+	return s
+}
+
+// readSecrets resolves every secret setting given as NAME_FILE or as a
+// systemd credential (LoadCredential=name:path), whose file is named after
+// the lowercase setting in $CREDENTIALS_DIRECTORY. The plain variable wins
+// over the credential; setting both it and NAME_FILE is an error.
+func (s *source) readSecrets() {
+	credentials := strings.TrimSpace(os.Getenv("CREDENTIALS_DIRECTORY"))
+	for _, k := range keys {
+		if !k.secret {
+			continue
+		}
+		if path := strings.TrimSpace(os.Getenv(k.name + "_FILE")); path != "" {
+			if strings.TrimSpace(os.Getenv(k.name)) != "" {
+				s.errorf("set either %s or %s_FILE, not both", k.name, k.name)
+				continue
+			}
+			value, err := readSecret(path)
+			if err != nil {
+				s.errorf("invalid %s_FILE: %v", k.name, err)
+				continue
+			}
+			s.secrets[k.name] = Setting{Key: k.name, Value: value, Origin: path}
+			continue
+		}
+		if credentials == "" {
+			continue
+		}
+		path := filepath.Join(credentials, strings.ToLower(k.name))
+		value, err := readSecret(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			s.errorf("invalid credential %s: %v", strings.ToLower(k.name), err)
+		default:
+			s.secrets[k.name] = Setting{Key: k.name, Value: value, Origin: path}
+		}
+	}
+}
+
+// readSecret returns the contents of path without the trailing newline most
+// editors add. An empty file is an error.
+func readSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return value, nil
+}
+
+// readConfigFile decodes a YAML or TOML file, chosen by its extension.
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("%s: want a .yaml, .yml or .toml file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flatten turns nested tables into setting names, so "alert: {cpu_threshold:
+// 80}" sets ALERT_CPU_THRESHOLD. Lists become comma-separated values.
+func flatten(prefix string, values map[string]any, out map[string]string) error {
+	var errs []error
+	for name, value := range values {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(name, v, out); err != nil {
+				errs = append(errs, err)
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := scalar(item)
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: list items must be plain values", strings.ToLower(name)))
+					break
+				}
+				items = append(items, s)
+			}
+			out[name] = strings.Join(items, ",")
+		default:
+			s, ok := scalar(v)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unsupported value", strings.ToLower(name)))
+				continue
+			}
+			out[name] = s
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func scalar(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case nil:
+		return "", true
+	}
+	return "", false
+}
+
+// get returns the value of name and registers secrets for redaction.
+func (s *source) get(name string) string {
+	setting, _ := s.lookup(name)
+	if k, _ := lookupKey(name); k.secret {
+		redact.Add(setting.Value)
+	}
+	return setting.Value
+}
+
+// lookup resolves name from the environment, then from NAME_FILE or a
+// credential for secrets, and then from the config file.
+func (s *source) lookup(name string) (Setting, bool) {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return Setting{Key: name, Value: v, Origin: "env"}, true
+	}
+	if setting, ok := s.secrets[name]; ok {
+		return setting, true
+	}
+	if v, ok := s.file[name]; ok {
+		return Setting{Key: name, Value: v, Origin: s.path}, true
+	}
+	return Setting{Key: name}, false
+}
+
+func (s *source) errorf(format string, args ...any) {
+	s.errs = append(s.errs, fmt.Errorf(format, args...))
+}
+
+// err returns every recorded problem, or nil.
+func (s *source) err() error {
+	return errors.Join(s.errs...)
+}
+
+func (s *source) list(name string) []string {
+	return parseList(s.get(name))
+}
+
+// duration reads a positive Go duration, returning fallback when unset.
+func (s *source) duration(name string, fallback time.Duration) time.Duration {
+	raw := s.get(name)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		s.errorf("invalid %s %q: want a positive duration such as 30s or 5m", name, raw)
+		return fallback
+	}
+	return d
+}
+
+// percent reads a number between 0 and 100, returning fallback when unset.
+func (s *source) percent(name string, fallback float64) float64 {
+	raw := s.get(name)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 || v > 100 {
+		s.errorf("invalid %s %q: want a number from 0 to 100", name, raw)
+		return fallback
+	}
+	return v
+}
+
+// float reads a non-negative number, returning fallback when unset.
+func (s *source) float(name string, fallback float64) float64 {
+	raw := s.get(name)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 {
+		s.errorf("invalid %s %q: want a non-negative number", name, raw)
+		return fallback
+	}
+	return v
+}
+
+// bool reads true/false, yes/no, on/off or 1/0; unset is false.
+func (s *source) bool(name string) bool {
+	raw := s.get(name)
+	v, ok := parseBool(raw)
+	if !ok {
+		s.errorf("invalid %s %q: want true or false", name, raw)
+	}
+	return v
+}
+
+// ids reads comma-separated chat IDs.
+func (s *source) ids(name string) []int64 {
+	ids, err := parseAdminIDs(s.get(name))
+	if err != nil {
+		s.errorf("invalid %s: %v", name, err)
+	}
+	return ids
+}
+
+// settings lists every setting given in the environment or a file.
+func (s *source) settings() []Setting {
+	var out []Setting
+	for _, k := range keys {
+		if setting, ok := s.lookup(k.name); ok {
+			out = append(out, setting)
+		}
+	}
+	return out
+}
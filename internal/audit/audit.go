@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"serverbot/internal/redact"
+)
+
+// Entry records a privileged action requested from Telegram.
+type Entry struct {
+	At      time.Time `json:"at"`
+	UserID  int64     `json:"user_id"`
+	ChatID  int64     `json:"chat_id"`
+	Action  string    `json:"action"`
+	Target  string    `json:"target"`
+	Detail  string    `json:"detail,omitempty"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Outcomes used by callers.
+const (
+	OutcomeOK     = "ok"
+	OutcomeDenied = "denied"
+	OutcomeFailed = "failed"
+)
+
+var mu sync.Mutex
+
+// Append writes e as one JSON line to path. Lines are written with O_APPEND
+// so concurrent writers never interleave within an entry. Secrets in the
+// detail and error are redacted.
+func Append(path string, e Entry) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	e.Detail = redact.String(e.Detail)
+	e.Error = redact.String(e.Error)
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// String renders e for the process log.
+func (e Entry) String() string {
+	s := fmt.Sprintf("user=%d chat=%d action=%s target=%s outcome=%s", e.UserID, e.ChatID, e.Action, e.Target, e.Outcome)
+	if e.Detail != "" {
+		s += " detail=" + e.Detail
+	}
+	if e.Error != "" {
+		s += " error=" + e.Error
+	}
+	return s
+}
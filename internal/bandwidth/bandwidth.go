@@ -0,0 +1,322 @@
+// Package bandwidth accounts the traffic of the selected network interfaces
+// per day and per month and alerts when a monthly quota is close.
+package bandwidth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+)
+
+const (
+	quotaKey    = "bandwidth:quota"
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+	// keepDays and keepMonths bound the history kept in the state file.
+	keepDays   = 62
+	keepMonths = 13
+	// recentDays is how many days /bandwidth lists.
+	recentDays = 7
+)
+
+// Direction selects which traffic counts towards the quota.
+type Direction string
+
+const (
+	DirectionTotal Direction = "total"
+	DirectionOut   Direction = "out"
+	DirectionIn    Direction = "in"
+)
+
+// ParseDirection accepts total, out and in (empty means total).
+func ParseDirection(raw string) (Direction, error) {
+	switch d := Direction(strings.ToLower(strings.TrimSpace(raw))); d {
+	case "":
+		return DirectionTotal, nil
+	case DirectionTotal, DirectionOut, DirectionIn:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown direction %q: want total, out or in", raw)
+}
+
+// Of returns the bytes of t that count in direction d.
+func (d Direction) Of(t Traffic) uint64 {
+	switch d {
+	case DirectionOut:
+		return t.Sent
+	case DirectionIn:
+		return t.Recv
+	}
+	return t.Total()
+}
+
+func (d Direction) label(lang string) string {
+	switch d {
+	case DirectionOut:
+		return i18n.T(lang, "bandwidth.out")
+	case DirectionIn:
+		return i18n.T(lang, "bandwidth.in")
+	}
+	return i18n.T(lang, "bandwidth.both")
+}
+
+// Quota is a monthly transfer cap. A zero Limit disables it.
+type Quota struct {
+	Limit       uint64
+	Direction   Direction
+	WarnPercent float64
+}
+
+// level returns 100 once used reaches the limit, WarnPercent once it reaches
+// that share and 0 otherwise.
+func (q Quota) level(used uint64) int {
+	if q.Limit == 0 {
+		return 0
+	}
+	pct := float64(used) * 100 / float64(q.Limit)
+	switch {
+	case pct >= 100:
+		return 100
+	case q.WarnPercent > 0 && pct >= q.WarnPercent:
+		return int(q.WarnPercent)
+	}
+	return 0
+}
+
+// Accountant samples interface counters every Interval and adds the deltas
+// to the persisted daily and monthly totals.
+type Accountant struct {
+	Store    *Store
+	Counters func(context.Context) ([]metrics.InterfaceStats, error)
+	Notifier *alerts.Notifier
+	Quota    Quota
+	Interval time.Duration
+	Location *time.Location
+	Logger   *log.Logger
+
+	now func() time.Time
+}
+
+// Run samples immediately and then every Interval until ctx is cancelled.
+func (a *Accountant) Run(ctx context.Context) {
+	a.sampleAndLog(ctx)
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.sampleAndLog(ctx)
+		}
+	}
+}
+
+func (a *Accountant) sampleAndLog(ctx context.Context) {
+	if err := a.Sample(ctx); err != nil {
+		a.log("sample: %v", err)
+	}
+}
+
+// Sample reads the counters once, updates the totals and checks the quota.
+// The first reading of an interface only sets its baseline; a counter lower
+// than the stored one (reboot) is counted from zero.
+func (a *Accountant) Sample(ctx context.Context) error {
+	counters, err := a.Counters(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := a.clock().In(a.location())
+	day, month := now.Format(dayLayout), now.Format(monthLayout)
+
+	var used uint64
+	var level, announced int
+	err = a.Store.Update(func(st *State) error {
+		for _, c := range counters {
+			current := Traffic{Sent: c.BytesSent, Recv: c.BytesRecv}
+			previous, seen := st.Counters[c.Name]
+			st.Counters[c.Name] = current
+			if !seen {
+				continue
+			}
+			delta := Traffic{Sent: counterDelta(previous.Sent, current.Sent), Recv: counterDelta(previous.Recv, current.Recv)}
+			addTraffic(st.Days, day, c.Name, delta)
+			addTraffic(st.Months, month, c.Name, delta)
+		}
+		prune(st, now)
+
+		used = a.Quota.Direction.Of(st.Months[month].Sum())
+		level, announced = a.Quota.level(used), st.QuotaAlerted[month]
+		if level > announced {
+			st.QuotaAlerted[month] = level
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.checkQuota(month, used, level, announced)
+	return nil
+}
+
+func (a *Accountant) checkQuota(month string, used uint64, level, announced int) {
+	if a.Notifier == nil {
+		return
+	}
+
+	var err error
+	switch {
+	case level == 0 && a.Notifier.Active(quotaKey):
+		err = a.Notifier.Resolve(quotaKey, i18n.Tf("alert.quota_reset", month))
+	case level > announced:
+		direction, limit := a.Quota.Direction, metrics.HumanBytes(a.Quota.Limit)
+		err = a.Notifier.Fire(quotaKey, func(lang string) string {
+			if level >= 100 {
+				return i18n.T(lang, "alert.quota_exceeded", month, direction.label(lang), metrics.HumanBytes(used), limit)
+			}
+			return i18n.T(lang, "alert.quota_reached", month, direction.label(lang), level, metrics.HumanBytes(used), limit)
+		})
+	}
+	if err != nil {
+		a.log("alert send error: %v", err)
+	}
+}
+
+// HandleBandwidth shows today's, this month's and recent totals and the
+// quota usage.
+func (a *Accountant) HandleBandwidth(ctx *commands.Context) error {
+	if err := a.Sample(ctx.RequestContext); err != nil {
+		a.log("sample: %v", err)
+	}
+	st, err := a.Store.Load()
+	if err != nil {
+		return ctx.ReplyError(ctx.T("bandwidth.read_failed"), err)
+	}
+	return ctx.ReplyHTML(a.Format(ctx.Lang, st, a.clock().In(a.location())), false)
+}
+
+// Format renders st as the /bandwidth HTML message in lang.
+func (a *Accountant) Format(lang string, st State, now time.Time) string {
+	day, month := now.Format(dayLayout), now.Format(monthLayout)
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "bandwidth.title") + "\n")
+	metrics.WriteSection(&b, "📅", i18n.T(lang, "bandwidth.today"), totalsLines(lang, st.Days[day]))
+	metrics.WriteSection(&b, "🗓️", i18n.T(lang, "bandwidth.month", month), totalsLines(lang, st.Months[month]))
+
+	if a.Quota.Limit > 0 {
+		used := a.Quota.Direction.Of(st.Months[month].Sum())
+		metrics.WriteSection(&b, "🚦", i18n.T(lang, "bandwidth.quota"), []string{
+			i18n.T(lang, "bandwidth.quota_line", metrics.HumanBytes(used), metrics.HumanBytes(a.Quota.Limit),
+				float64(used)*100/float64(a.Quota.Limit), a.Quota.Direction.label(lang)),
+		})
+	}
+
+	var recent []string
+	for i := 1; i <= recentDays; i++ {
+		d := now.AddDate(0, 0, -i).Format(dayLayout)
+		if totals, ok := st.Days[d]; ok {
+			recent = append(recent, d+": "+trafficLine(totals.Sum()))
+		}
+	}
+	metrics.WriteSection(&b, "📈", i18n.T(lang, "bandwidth.recent"), recent)
+
+	prev := now.AddDate(0, 0, -now.Day()).Format(monthLayout)
+	if totals, ok := st.Months[prev]; ok {
+		metrics.WriteSection(&b, "🗂️", i18n.T(lang, "bandwidth.month", prev), []string{trafficLine(totals.Sum())})
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func totalsLines(lang string, totals Totals) []string {
+	if len(totals) == 0 {
+		return []string{i18n.T(lang, "bandwidth.no_data")}
+	}
+	lines := []string{"Total: " + trafficLine(totals.Sum())}
+	if len(totals) > 1 {
+		names := make([]string, 0, len(totals))
+		for name := range totals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, name+": "+trafficLine(totals[name]))
+		}
+	}
+	return lines
+}
+
+func trafficLine(t Traffic) string {
+	return fmt.Sprintf("↑ %s ↓ %s", metrics.HumanBytes(t.Sent), metrics.HumanBytes(t.Recv))
+}
+
+func addTraffic(periods map[string]Totals, period, iface string, delta Traffic) {
+	totals := periods[period]
+	if totals == nil {
+		totals = make(Totals)
+		periods[period] = totals
+	}
+	t := totals[iface]
+	t.Sent += delta.Sent
+	t.Recv += delta.Recv
+	totals[iface] = t
+}
+
+func prune(st *State, now time.Time) {
+	oldestDay := now.AddDate(0, 0, -keepDays).Format(dayLayout)
+	for day := range st.Days {
+		if day < oldestDay {
+			delete(st.Days, day)
+		}
+	}
+	oldestMonth := now.AddDate(0, -keepMonths, 0).Format(monthLayout)
+	for month := range st.Months {
+		if month < oldestMonth {
+			delete(st.Months, month)
+		}
+	}
+	for month := range st.QuotaAlerted {
+		if month < oldestMonth {
+			delete(st.QuotaAlerted, month)
+		}
+	}
+}
+
+func counterDelta(before, after uint64) uint64 {
+	if after < before {
+		return after
+	}
+	return after - before
+}
+
+func (a *Accountant) location() *time.Location {
+	if a.Location != nil {
+		return a.Location
+	}
+	return time.Local
+}
+
+func (a *Accountant) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+func (a *Accountant) log(format string, args ...any) {
+	if a.Logger != nil {
+		a.Logger.Printf("bandwidth: "+format, args...)
+	}
+}
@@ -0,0 +1,173 @@
+package bandwidth
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/metrics"
+)
+
+type fakeCounters struct {
+	stats []metrics.InterfaceStats
+}
+
+func (f *fakeCounters) set(name string, sent, recv uint64) {
+	for i := range f.stats {
+		if f.stats[i].Name == name {
+			f.stats[i].BytesSent, f.stats[i].BytesRecv = sent, recv
+			return
+		}
+	}
+	f.stats = append(f.stats, metrics.InterfaceStats{Name: name, BytesSent: sent, BytesRecv: recv})
+}
+
+func (f *fakeCounters) read(context.Context) ([]metrics.InterfaceStats, error) {
+	return append([]metrics.InterfaceStats(nil), f.stats...), nil
+}
+
+func newAccountant(t *testing.T, counters *fakeCounters, now *time.Time, sent *[]string) *Accountant {
+	t.Helper()
+	return &Accountant{
+		Store:    NewStore(filepath.Join(t.TempDir(), "bandwidth.json")),
+		Counters: counters.read,
+		Notifier: alerts.NewNotifier(0, func(msg string) error { *sent = append(*sent, msg); return nil }),
+		Location: time.UTC,
+		now:      func() time.Time { return *now },
+	}
+}
+
+func TestSampleAccumulatesAndSurvivesReset(t *testing.T) {
+	counters := &fakeCounters{}
+	now := time.Date(2026, 5, 31, 23, 50, 0, 0, time.UTC)
+	var sent []string
+	a := newAccountant(t, counters, &now, &sent)
+	ctx := context.Background()
+
+	counters.set("eth0", 1000, 5000)
+	if err := a.Sample(ctx); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+
+	counters.set("eth0", 1500, 7000)
+	counters.set("wg0", 10, 10)
+	now = now.Add(5 * time.Minute)
+	if err := a.Sample(ctx); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+
+	// Reboot: counters restart below the stored baseline, on the next day.
+	counters.set("eth0", 300, 400)
+	counters.set("wg0", 30, 60)
+	now = now.Add(10 * time.Minute)
+	if err := a.Sample(ctx); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+
+	st, err := a.Store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := st.Days["2026-05-31"]["eth0"]; got != (Traffic{Sent: 500, Recv: 2000}) {
+		t.Fatalf("May 31 eth0 = %+v", got)
+	}
+	if _, ok := st.Days["2026-05-31"]["wg0"]; ok {
+		t.Fatalf("first reading of wg0 should only set the baseline")
+	}
+	if got := st.Days["2026-06-01"].Sum(); got != (Traffic{Sent: 320, Recv: 450}) {
+		t.Fatalf("June 1 total = %+v", got)
+	}
+	if got := st.Months["2026-05"].Sum(); got != (Traffic{Sent: 500, Recv: 2000}) {
+		t.Fatalf("May total = %+v", got)
+	}
+	if got := st.Counters["eth0"]; got != (Traffic{Sent: 300, Recv: 400}) {
+		t.Fatalf("stored counters = %+v", got)
+	}
+
+	out := a.Format("", st, now)
+	for _, needle := range []string{
+		"<b>📶 Trafico de red</b>",
+		"📅 <b>Hoy</b>", "Total: ↑ 320B ↓ 450B", "eth0: ↑ 300B ↓ 400B", "wg0: ↑ 20B ↓ 50B",
+		"🗓️ <b>Mes 2026-06</b>",
+		"2026-05-31: ↑ 500B ↓ 2.0KB",
+		"🗂️ <b>Mes 2026-05</b>",
+	} {
+		if !strings.Contains(out, needle) {
+			t.Fatalf("Format() missing %q:\n%s", needle, out)
+		}
+	}
+	if strings.Contains(out, "Cuota") {
+		t.Fatalf("quota shown without a limit:\n%s", out)
+	}
+}
+
+func TestQuotaAlertsOncePerLevel(t *testing.T) {
+	counters := &fakeCounters{}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	var sent []string
+	a := newAccountant(t, counters, &now, &sent)
+	a.Quota = Quota{Limit: 1000, Direction: DirectionOut, WarnPercent: 90}
+	ctx := context.Background()
+
+	steps := []struct {
+		sent      uint64
+		wantAlert string
+	}{
+		{0, ""},
+		{500, ""},
+		{920, "ha alcanzado el 90% de la cuota mensual: 920B de 1000B"},
+		{950, ""},
+		{1200, "ha superado la cuota mensual: 1.2KB de 1000B"},
+		{1300, ""},
+	}
+	for _, step := range steps {
+		counters.set("eth0", step.sent, 1<<30)
+		before := len(sent)
+		if err := a.Sample(ctx); err != nil {
+			t.Fatalf("Sample() error = %v", err)
+		}
+		switch {
+		case step.wantAlert == "" && len(sent) != before:
+			t.Fatalf("sent=%d: unexpected alert %q", step.sent, sent[len(sent)-1])
+		case step.wantAlert != "" && (len(sent) != before+1 || !strings.Contains(sent[before], step.wantAlert)):
+			t.Fatalf("sent=%d: alerts = %v, want %q", step.sent, sent[before:], step.wantAlert)
+		}
+	}
+
+	// A restarted accountant reads the announced level from the file.
+	var resent []string
+	restarted := newAccountant(t, counters, &now, &resent)
+	restarted.Store, restarted.Quota = a.Store, a.Quota
+	counters.set("eth0", 1400, 1<<30)
+	if err := restarted.Sample(ctx); err != nil || len(resent) != 0 {
+		t.Fatalf("restart re-announced: %v, %v", resent, err)
+	}
+
+	now = time.Date(2026, 6, 1, 0, 5, 0, 0, time.UTC)
+	counters.set("eth0", 1410, 1<<30)
+	if err := a.Sample(ctx); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if last := sent[len(sent)-1]; !strings.HasPrefix(last, "[✅ RECUPERADO] Nuevo periodo 2026-06") {
+		t.Fatalf("rollover = %q", last)
+	}
+
+	st, _ := a.Store.Load()
+	if out := a.Format("", st, now); !strings.Contains(out, "10B de 1000B (1.0%) - salida") {
+		t.Fatalf("quota line missing:\n%s", out)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	for raw, want := range map[string]Direction{"": DirectionTotal, "OUT": DirectionOut, "in": DirectionIn} {
+		if got, err := ParseDirection(raw); err != nil || got != want {
+			t.Fatalf("ParseDirection(%q) = %q, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseDirection("both"); err == nil {
+		t.Fatalf("ParseDirection(both) expected error")
+	}
+}
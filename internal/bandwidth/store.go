@@ -0,0 +1,133 @@
+package bandwidth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/gofrs/flock"
+)
+
+// Totals are transferred bytes per interface.
+type Totals map[string]Traffic
+
+// Traffic is a sent/received byte pair.
+type Traffic struct {
+	Sent uint64 `json:"sent"`
+	Recv uint64 `json:"recv"`
+}
+
+// Total returns sent plus received bytes.
+func (t Traffic) Total() uint64 {
+	return t.Sent + t.Recv
+}
+
+// Sum adds up every interface.
+func (t Totals) Sum() Traffic {
+	var sum Traffic
+	for _, traffic := range t {
+		sum.Sent += traffic.Sent
+		sum.Recv += traffic.Recv
+	}
+	return sum
+}
+
+// State is the on-disk accounting: the last kernel counters seen per
+// interface and the totals per day (YYYY-MM-DD) and month (YYYY-MM).
+type State struct {
+	Counters map[string]Traffic `json:"counters"`
+	Days     map[string]Totals  `json:"days"`
+	Months   map[string]Totals  `json:"months"`
+	// QuotaAlerted maps a month to the highest quota level already
+	// announced (warning percentage or 100), so restarts do not repeat it.
+	QuotaAlerted map[string]int `json:"quota_alerted,omitempty"`
+}
+
+// Store provides atomic read/write of the accounting state backed by a JSON
+// file protected with a file lock.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store that reads/writes at the given path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the current state from disk. A missing file yields an empty
+// state.
+func (s *Store) Load() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return State{}, fmt.Errorf("lock bandwidth file: %w", err)
+	}
+	defer fl.Unlock()
+
+	return s.readUnsafe()
+}
+
+// Update applies fn to the current state and writes back the result.
+func (s *Store) Update(fn func(*State) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("lock bandwidth file: %w", err)
+	}
+	defer fl.Unlock()
+
+	st, err := s.readUnsafe()
+	if err != nil {
+		return err
+	}
+	if err := fn(&st); err != nil {
+		return err
+	}
+	return s.writeUnsafe(st)
+}
+
+func (s *Store) readUnsafe() (State, error) {
+	var st State
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return State{}, fmt.Errorf("read bandwidth: %w", err)
+	default:
+		if err := json.Unmarshal(data, &st); err != nil {
+			return State{}, fmt.Errorf("parse bandwidth: %w", err)
+		}
+	}
+
+	if st.Counters == nil {
+		st.Counters = make(map[string]Traffic)
+	}
+	if st.Days == nil {
+		st.Days = make(map[string]Totals)
+	}
+	if st.Months == nil {
+		st.Months = make(map[string]Totals)
+	}
+	if st.QuotaAlerted == nil {
+		st.QuotaAlerted = make(map[string]int)
+	}
+	return st, nil
+}
+
+func (s *Store) writeUnsafe(st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bandwidth: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("write bandwidth: %w", err)
+	}
+	return nil
+}
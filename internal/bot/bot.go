@@ -4,14 +4,28 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"sync"
 	"time"
 
+	"serverbot/internal/agent"
+	"serverbot/internal/alerts"
 	"serverbot/internal/app"
+	"serverbot/internal/bandwidth"
+	"serverbot/internal/certs"
 	"serverbot/internal/commands"
+	"serverbot/internal/custom"
+	"serverbot/internal/digest"
+	"serverbot/internal/exporter"
+	"serverbot/internal/i18n"
 	"serverbot/internal/metrics"
+	"serverbot/internal/redact"
 	"serverbot/internal/revanced"
+	"serverbot/internal/scheduler"
 	"serverbot/internal/system"
+	"serverbot/internal/systemd"
+	"serverbot/internal/uptime"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -24,7 +38,7 @@ type Runner struct {
 // New constructs a Runner with the provided logger.
 func New(logger *log.Logger) *Runner {
 	if logger == nil {
-		logger = log.New(os.Stdout, "serverbot: ", log.LstdFlags)
+		logger = log.New(redact.NewWriter(os.Stdout), "serverbot: ", log.LstdFlags)
 	}
 	return &Runner{logger: logger}
 }
@@ -36,10 +50,19 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 
 	cfg, err := app.LoadConfig()
+	if err == nil {
+		err = checkConfig(cfg)
+	}
 	if err != nil {
 		return err
 	}
 
+	// The library logs polling errors with the request URL, which embeds the
+	// token; route them through the redacting logger.
+	if err := tgbotapi.SetLogger(r.logger); err != nil {
+		return fmt.Errorf("set bot api logger: %w", err)
+	}
+
 	var botAPI *tgbotapi.BotAPI
 	if cfg.TelegramAPIURL != "" {
 		botAPI, err = tgbotapi.NewBotAPIWithAPIEndpoint(cfg.Token, cfg.TelegramAPIURL+"/bot%s/%s")
@@ -54,30 +77,200 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	commandRunner := system.NewCommandRunner()
 	collector := metrics.NewCollector(metrics.Options{
-		DiskTargets: cfg.DiskTargets,
+		DiskTargets:   cfg.DiskTargets,
+		NetInclude:    cfg.NetInclude,
+		NetExclude:    cfg.NetExclude,
+		SourceTimeout: cfg.MetricsSourceTimeout,
 	})
+	sampler := &metrics.Sampler{
+		Collector: collector,
+		Interval:  cfg.MetricsSampleInterval,
+		Timeout:   cfg.CommandTimeout + 2*collector.SampleInterval(),
+		Logger:    r.logger,
+	}
+	go sampler.Run(ctx)
+
+	if err := i18n.SetDefault(defaultLanguage(cfg)); err != nil {
+		return err
+	}
+	languages, err := i18n.NewStore(cfg.LanguageFile)
+	if err != nil {
+		return fmt.Errorf("invalid LANGUAGE_FILE: %w", err)
+	}
 
 	deps := commands.Dependencies{
-		Config: cfg,
-		Runner: commandRunner,
-		Logger: r.logger,
+		Config:    cfg,
+		Runner:    commandRunner,
+		Logger:    r.logger,
+		Languages: languages,
 	}
 
 	registry := commands.NewRegistry(deps)
 
+	agents, err := newAgents(cfg)
+	if err != nil {
+		return err
+	}
+	sshHosts, err := newSSHHosts(cfg)
+	if err != nil {
+		return err
+	}
+	hosts := make([]commands.Host, 0, len(agents)+len(sshHosts))
+	for _, a := range agents {
+		hosts = append(hosts, a)
+	}
+	hosts = append(hosts, sshHosts...)
+	if err := checkHostNames(hosts); err != nil {
+		return err
+	}
+	registry.SetHosts(hosts...)
+
 	var revSvc *revanced.Service
 	if cfg.RevancedRepo != "" && cfg.RevancedStateFile != "" {
 		revSvc = revanced.NewService(cfg.RevancedStateFile, cfg.RevancedRepo, cfg.RevancedServeDir, cfg.RevancedNginxBaseURL, r.logger)
 	}
 
-	registerCommands(registry, collector, revSvc)
+	var schedSvc *scheduler.Service
+	if cfg.ScheduleFile != "" {
+		schedSvc = scheduler.NewService(cfg.ScheduleFile, registry, r.logger)
+		schedSvc.Languages = languages
+	}
+
+	reload := newReloader(cfg, r.logger)
+	notifier := alerts.NewNotifier(cfg.Alerts.Cooldown, func(message string) error {
+		return sendAlert(botAPI, reload.current().OwnerID, message)
+	})
+	notifier.SetLanguage(func() string {
+		return languages.Resolve(reload.current().OwnerID, "")
+	})
+
+	var exp *exporter.Exporter
+	if cfg.MetricsListenAddr != "" {
+		exp = exporter.New(sampler, revSvc, r.logger)
+		botAPI.Client = exp.WrapClient(botAPI.Client)
+		notifier.OnEvent(exp.ObserveAlert)
+		registry.Use(exp.Middleware())
+		go func() {
+			if err := exp.ListenAndServe(ctx, cfg.MetricsListenAddr); err != nil {
+				r.logger.Printf("metrics exporter stopped: %v", err)
+			}
+		}()
+	}
+
+	var digestSvc *digest.Service
+	if cfg.Digest.Enabled {
+		digestSvc = digest.NewService(cfg, sampler, commandRunner, notifier, revSvc, r.logger)
+		digestSvc.Languages = languages
+	}
+
+	systemdClient := systemd.NewClient(commandRunner, r.logger)
+	systemdSvc := systemd.NewService(systemdClient, commandRunner, systemd.ACL{
+		Owner: cfg.ServiceAllowOwner,
+		Admin: cfg.ServiceAllowAdmin,
+	}, r.logger)
+
+	monitor, err := newUptimeMonitor(cfg, commandRunner, notifier, r.logger)
+	if err != nil {
+		return err
+	}
+
+	certWatcher, err := newCertWatcher(cfg, notifier, r.logger)
+	if err != nil {
+		return err
+	}
+
+	accountant, err := newAccountant(cfg, collector, notifier, r.logger)
+	if err != nil {
+		return err
+	}
+
+	registerCommands(registry, sampler, services{
+		languages: languages,
+		revanced:  revSvc,
+		scheduler: schedSvc,
+		digest:    digestSvc,
+		systemd:   systemdSvc,
+		uptime:    monitor,
+		certs:     certWatcher,
+		bandwidth: accountant,
+		reload:    reload,
+	})
+
+	customSet, err := newCustomCommands(cfg, registry, r.logger)
+	if err != nil {
+		return err
+	}
+	reload.registry = registry
+	reload.notifier = notifier
+	reload.collector = collector
+	reload.custom = customSet
+	reload.menu = &menu{bot: botAPI, registry: registry}
+	if err := reload.menu.publish(cfg); err != nil {
+		r.logger.Printf("command menu not published: %v", err)
+	}
+	go reload.watchHangup(ctx)
 
 	registry.SetNotFound(func(ctx *commands.Context) error {
-		return ctx.Reply("Comando no reconocido.")
+		return ctx.Reply(ctx.T("bot.unknown_command"))
 	})
 
 	registry.Use(logCommand(r.logger))
-	r.startAlerts(ctx, botAPI, collector, cfg)
+	r.startAlerts(ctx, notifier, sampler, agents, reload.current)
+	// The watchers follow ENABLE_ALERTS, ALERT_INTERVAL and ALERT_COOLDOWN
+	// across reloads; which units they watch is read at startup.
+	if cfg.Alerts.FailedUnits {
+		watcher := &systemd.Watcher{
+			Client:   systemdClient,
+			Runner:   commandRunner,
+			Notifier: notifier,
+			Interval: cfg.Alerts.Interval,
+			Timeout:  cfg.CommandTimeout,
+			Logger:   r.logger,
+			Config:   reload.current,
+		}
+		go watcher.Run(ctx)
+	}
+	if len(cfg.Alerts.JournalUnits) > 0 {
+		priority, err := systemd.ParsePriority(cfg.Alerts.JournalPriority)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNAL_ALERT_PRIORITY: %w", err)
+		}
+		units := make([]string, 0, len(cfg.Alerts.JournalUnits))
+		for _, raw := range cfg.Alerts.JournalUnits {
+			unit, err := systemd.NormalizeUnit(raw)
+			if err != nil {
+				return fmt.Errorf("invalid JOURNAL_ALERT_UNITS: %w", err)
+			}
+			units = append(units, unit)
+		}
+		journalWatcher := &systemd.JournalWatcher{
+			Runner:   commandRunner,
+			Notifier: notifier,
+			Units:    units,
+			Priority: priority,
+			Interval: cfg.Alerts.Interval,
+			Timeout:  cfg.CommandTimeout,
+			Quiet:    cfg.Alerts.Cooldown,
+			Logger:   r.logger,
+			Config:   reload.current,
+		}
+		go journalWatcher.Run(ctx)
+	}
+	if monitor != nil {
+		go monitor.Run(ctx)
+	}
+	if certWatcher != nil {
+		go certWatcher.Run(ctx)
+	}
+	if accountant != nil {
+		go accountant.Run(ctx)
+	}
+	if schedSvc != nil {
+		go schedSvc.Run(ctx, botAPI)
+	}
+	if digestSvc != nil {
+		go digestSvc.Run(ctx, botAPI)
+	}
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
@@ -97,7 +290,7 @@ func (r *Runner) Run(ctx context.Context) error {
 			}
 
 			// Intercept document uploads for revanced APK pipeline.
-			if revSvc != nil && update.Message.Document != nil && update.Message.Chat.ID == cfg.OwnerID {
+			if revSvc != nil && update.Message.Document != nil && update.Message.Chat.ID == reload.current().OwnerID {
 				if revSvc.HandleDocument(ctx, botAPI, update, r.logger) {
 					continue
 				}
@@ -113,27 +306,86 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 }
 
-func registerCommands(registry *commands.Registry, collector *metrics.Collector, revSvc *revanced.Service) {
-	registry.Handle("help", "Muestra esta ayuda", commands.ScopePublic, commands.NewHelpHandler(registry))
-	registry.Handle("stats", "Uso de CPU, RAM, red, discos y GPU", commands.ScopePublic, commands.NewStatsHandler(collector))
+// services groups the optional subsystems that contribute commands.
+type services struct {
+	languages *i18n.Store
+	revanced  *revanced.Service
+	scheduler *scheduler.Service
+	digest    *digest.Service
+	systemd   *systemd.Service
+	uptime    *uptime.Monitor
+	certs     *certs.Watcher
+	bandwidth *bandwidth.Accountant
+	reload    *reloader
+}
+
+func registerCommands(registry *commands.Registry, sampler *metrics.Sampler, svc services) {
+	registry.Handle("help", "cmd.help", commands.ScopePublic, commands.NewHelpHandler(registry))
+	registry.Handle("stats", "cmd.stats", commands.ScopePublic, commands.NewStatsHandler(sampler))
+
+	registry.Handle("top", "cmd.top", commands.ScopeAdmin, commands.Top, commands.AdminOnly())
+	registry.Handle("proc", "cmd.proc", commands.ScopeAdmin, commands.Proc, commands.AdminOnly())
+	registry.Handle("docker", "cmd.docker", commands.ScopeAdmin, commands.Docker, commands.AdminOnly())
+	registry.Handle("docker_exec", "cmd.docker_exec", commands.ScopeAdmin, commands.DockerExec, commands.AdminOnly())
+	registry.Handle("swap_mc_server", "cmd.swap_mc_server", commands.ScopeAdmin, commands.SwapMC, commands.AdminOnly())
+	registry.Handle("docker_logs", "cmd.docker_logs", commands.ScopeOwner, commands.DockerLogs, commands.OwnerOnly())
+	registry.Handle("logs_suscripcion", "cmd.logs_suscripcion", commands.ScopeOwner, commands.DockerLogsSubscribe, commands.OwnerOnly())
+	registry.Handle("docker_stats", "cmd.docker_stats", commands.ScopeOwner, commands.DockerStats, commands.OwnerOnly())
+	registry.Handle("docker_restart", "cmd.docker_restart", commands.ScopeOwner, commands.DockerRestart, commands.AdminOnly())
+	registry.Handle("service_status", "cmd.service_status", commands.ScopeOwner, commands.ServiceStatus, commands.OwnerOnly())
+	registry.Handle("ping", "cmd.ping", commands.ScopeOwner, commands.Ping, commands.OwnerOnly())
+	registry.Handle("traceroute", "cmd.traceroute", commands.ScopeOwner, commands.Traceroute, commands.OwnerOnly())
+	registry.Handle("dns", "cmd.dns", commands.ScopeOwner, commands.DNS, commands.OwnerOnly())
+	registry.Handle("http_check", "cmd.http_check", commands.ScopeOwner, commands.HTTPCheck, commands.OwnerOnly())
+	registry.Handle("port_check", "cmd.port_check", commands.ScopeOwner, commands.PortCheck, commands.OwnerOnly())
+	registry.Handle("kill", "cmd.kill", commands.ScopeOwner, commands.Kill, commands.OwnerOnly())
+	registry.Handle("renice", "cmd.renice", commands.ScopeOwner, commands.Renice, commands.OwnerOnly())
+	registry.Handle("reboot", "cmd.reboot", commands.ScopeOwner, commands.Reboot, commands.OwnerOnly())
+
+	if svc.languages != nil {
+		registry.Handle("lang", "cmd.lang", commands.ScopePublic, commands.NewLangHandler(svc.languages))
+	}
+
+	if svc.systemd != nil {
+		registry.Handle("service_start", "cmd.service_start", commands.ScopeAdmin, svc.systemd.HandleStart, commands.AdminOnly())
+		registry.Handle("service_stop", "cmd.service_stop", commands.ScopeAdmin, svc.systemd.HandleStop, commands.AdminOnly())
+		registry.Handle("service_restart", "cmd.service_restart", commands.ScopeAdmin, svc.systemd.HandleRestart, commands.AdminOnly())
+		registry.Handle("service_enable", "cmd.service_enable", commands.ScopeAdmin, svc.systemd.HandleEnable, commands.AdminOnly())
+		registry.Handle("failed_units", "cmd.failed_units", commands.ScopeAdmin, svc.systemd.HandleFailed, commands.AdminOnly())
+		registry.Handle("journal", "cmd.journal", commands.ScopeOwner, svc.systemd.HandleJournal, commands.OwnerOnly())
+		registry.Handle("journal_follow", "cmd.journal_follow", commands.ScopeOwner, svc.systemd.HandleJournalFollow, commands.OwnerOnly())
+	}
 
-	registry.Handle("top", "Procesos con mayor uso de CPU/RAM", commands.ScopeAdmin, commands.Top, commands.AdminOnly())
-	registry.Handle("docker", "Contenedores activos y estado", commands.ScopeAdmin, commands.Docker, commands.AdminOnly())
-	registry.Handle("docker_exec", "Ejecuta un comando en un contenedor Docker", commands.ScopeAdmin, commands.DockerExec, commands.AdminOnly())
-	registry.Handle("swap_mc_server", "Modifica el servidor de minecraft en activo", commands.ScopeAdmin, commands.SwapMC, commands.AdminOnly())
-	registry.Handle("docker_logs", "Ultimas 20 lineas del log de un contenedor", commands.ScopeOwner, commands.DockerLogs, commands.OwnerOnly())
-	registry.Handle("logs_suscripcion", "Envia actualizaciones periodicas de logs", commands.ScopeOwner, commands.DockerLogsSubscribe, commands.OwnerOnly())
-	registry.Handle("docker_stats", "Uso de recursos de un contenedor", commands.ScopeOwner, commands.DockerStats, commands.OwnerOnly())
-	registry.Handle("docker_restart", "Reinicia un contenedor Docker", commands.ScopeOwner, commands.DockerRestart, commands.AdminOnly())
-	registry.Handle("service_status", "Estado de un servicio systemd", commands.ScopeOwner, commands.ServiceStatus, commands.OwnerOnly())
-	registry.Handle("ping", "Prueba de conectividad", commands.ScopeOwner, commands.Ping, commands.OwnerOnly())
-	registry.Handle("reboot", "Reinicia el servidor", commands.ScopeOwner, commands.Reboot, commands.OwnerOnly())
+	if svc.revanced != nil {
+		registry.Handle("revanced_build", "cmd.revanced_build", commands.ScopeOwner, svc.revanced.HandleBuild, commands.OwnerOnly())
+		registry.Handle("revanced_status", "cmd.revanced_status", commands.ScopeOwner, svc.revanced.HandleStatus, commands.OwnerOnly())
+		registry.Handle("revanced_cancel", "cmd.revanced_cancel", commands.ScopeOwner, svc.revanced.HandleCancel, commands.OwnerOnly())
+	}
 
-	if revSvc != nil {
-		registry.Handle("revanced_build", "Inicia el pipeline de build de ReVanced", commands.ScopeOwner, revSvc.HandleBuild, commands.OwnerOnly())
-		registry.Handle("revanced_status", "Muestra el estado del pipeline de ReVanced", commands.ScopeOwner, revSvc.HandleStatus, commands.OwnerOnly())
-		registry.Handle("revanced_cancel", "Cancela el pipeline de ReVanced", commands.ScopeOwner, revSvc.HandleCancel, commands.OwnerOnly())
+	if svc.uptime != nil {
+		registry.Handle("status_page", "cmd.status_page", commands.ScopeAdmin, svc.uptime.HandleStatusPage, commands.AdminOnly())
+	}
+	if svc.certs != nil {
+		registry.Handle("certs", "cmd.certs", commands.ScopeAdmin, svc.certs.HandleCerts, commands.AdminOnly())
+	}
+	if svc.bandwidth != nil {
+		registry.Handle("bandwidth", "cmd.bandwidth", commands.ScopeAdmin, svc.bandwidth.HandleBandwidth, commands.AdminOnly())
+	}
+	if svc.reload != nil {
+		registry.Handle("config", "cmd.config", commands.ScopeOwner, svc.reload.HandleConfig, commands.OwnerOnly())
+		registry.Handle("reload", "cmd.reload", commands.ScopeOwner, svc.reload.HandleReload, commands.OwnerOnly())
 	}
+	if svc.digest != nil {
+		registry.Handle("digest", "cmd.digest", commands.ScopeAdmin, svc.digest.HandleDigest, commands.AdminOnly())
+	}
+
+	if svc.scheduler != nil {
+		registry.Handle("schedule", "cmd.schedule", commands.ScopeOwner, svc.scheduler.HandleSchedule, commands.OwnerOnly())
+	}
+
+	// Commands whose agent-side counterpart is in agent.DefaultAllow; SSH
+	// hosts run the same commands.
+	registry.AllowRemote("stats", "top", "docker", "docker_stats", "docker_logs", "service_status", "ping")
 }
 
 func logCommand(logger *log.Logger) commands.Middleware {
@@ -147,88 +399,291 @@ func logCommand(logger *log.Logger) commands.Middleware {
 	}
 }
 
-func (r *Runner) startAlerts(ctx context.Context, bot *tgbotapi.BotAPI, collector *metrics.Collector, cfg app.Config) {
-	if !cfg.Alerts.Enabled || bot == nil || collector == nil {
-		return
+// newCustomCommands registers the commands defined in COMMANDS_FILE. It
+// returns nil when no file is configured.
+func newCustomCommands(cfg app.Config, registry *commands.Registry, logger *log.Logger) (*custom.Set, error) {
+	if cfg.CommandsFile == "" {
+		return nil, nil
+	}
+	cmds, err := custom.Load(cfg.CommandsFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid COMMANDS_FILE: %w", err)
 	}
+	set := &custom.Set{Registry: registry, Logger: logger}
+	if err := set.Apply(cmds); err != nil {
+		return nil, fmt.Errorf("invalid COMMANDS_FILE: %w", err)
+	}
+	return set, nil
+}
 
-	if cfg.OwnerID == 0 {
-		return
+// newAgents builds a client for every AGENT_HOSTS entry.
+func newAgents(cfg app.Config) ([]*agent.Client, error) {
+	agents := make([]*agent.Client, 0, len(cfg.AgentHosts))
+	for _, spec := range cfg.AgentHosts {
+		name, url, err := agent.ParseHost(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AGENT_HOSTS: %w", err)
+		}
+		agents = append(agents, agent.NewClient(name, url, cfg.AgentToken))
 	}
+	return agents, nil
+}
 
-	state := make(map[string]time.Time)
+// newSSHHosts builds a pooled SSH runner for every SSH_HOSTS entry.
+func newSSHHosts(cfg app.Config) ([]commands.Host, error) {
+	if len(cfg.SSH.Hosts) == 0 {
+		return nil, nil
+	}
+	signer, hostKeys, err := system.LoadSSHAuth(cfg.SSH.KeyFile, cfg.SSH.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]commands.Host, 0, len(cfg.SSH.Hosts))
+	for _, spec := range cfg.SSH.Hosts {
+		target, err := system.ParseSSHTarget(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH_HOSTS: %w", err)
+		}
+		hosts = append(hosts, commands.NewRunnerHost(target.Name, system.NewSSHRunner(target.User, target.Addr, signer, hostKeys)))
+	}
+	return hosts, nil
+}
+
+// checkHostNames rejects a name used twice across AGENT_HOSTS and SSH_HOSTS.
+func checkHostNames(hosts []commands.Host) error {
+	seen := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		if seen[h.Name()] {
+			return fmt.Errorf("host %q is configured twice in AGENT_HOSTS/SSH_HOSTS", h.Name())
+		}
+		seen[h.Name()] = true
+	}
+	return nil
+}
+
+// newUptimeMonitor builds the monitor for UPTIME_CHECKS, adding the public
+// ReVanced URL when configured. It returns nil when there is nothing to check.
+func newUptimeMonitor(cfg app.Config, runner system.Runner, notifier *alerts.Notifier, logger *log.Logger) (*uptime.Monitor, error) {
+	if len(cfg.Uptime.Checks) == 0 {
+		return nil, nil
+	}
+
+	checks := make([]uptime.Check, 0, len(cfg.Uptime.Checks)+1)
+	targets := make(map[string]bool)
+	for _, spec := range cfg.Uptime.Checks {
+		check, err := uptime.ParseCheck(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UPTIME_CHECKS: %w", err)
+		}
+		checks = append(checks, check)
+		targets[check.Target] = true
+	}
+	if cfg.RevancedNginxBaseURL != "" {
+		check, err := uptime.ParseCheck("revanced=" + cfg.RevancedNginxBaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REVANCED_NGINX_BASE_URL: %w", err)
+		}
+		if !targets[check.Target] {
+			checks = append(checks, check)
+		}
+	}
+
+	return &uptime.Monitor{
+		Checks:   checks,
+		Prober:   &uptime.Prober{HTTPClient: &http.Client{}, Runner: runner},
+		Notifier: notifier,
+		Interval: cfg.Uptime.Interval,
+		Timeout:  cfg.Uptime.Timeout,
+		Failures: cfg.Uptime.Failures,
+		Logger:   logger,
+	}, nil
+}
+
+// newCertWatcher builds the expiry watcher for CERT_HOSTS and CERT_FILES. It
+// returns nil when neither is set.
+func newCertWatcher(cfg app.Config, notifier *alerts.Notifier, logger *log.Logger) (*certs.Watcher, error) {
+	if len(cfg.Certs.Hosts) == 0 && len(cfg.Certs.Files) == 0 {
+		return nil, nil
+	}
+
+	hosts := make([]string, 0, len(cfg.Certs.Hosts))
+	for _, spec := range cfg.Certs.Hosts {
+		host, err := certs.ParseHost(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CERT_HOSTS: %w", err)
+		}
+		hosts = append(hosts, host)
+	}
+	if _, err := certs.ExpandFiles(cfg.Certs.Files); err != nil {
+		return nil, fmt.Errorf("invalid CERT_FILES: %w", err)
+	}
+
+	return &certs.Watcher{
+		Hosts:    hosts,
+		Files:    cfg.Certs.Files,
+		Notifier: notifier,
+		Interval: cfg.Certs.Interval,
+		Timeout:  cfg.CommandTimeout,
+		Logger:   logger,
+	}, nil
+}
+
+// newAccountant builds the traffic accountant when BANDWIDTH_FILE is set.
+func newAccountant(cfg app.Config, collector *metrics.Collector, notifier *alerts.Notifier, logger *log.Logger) (*bandwidth.Accountant, error) {
+	if cfg.Bandwidth.File == "" {
+		return nil, nil
+	}
+
+	direction, err := bandwidth.ParseDirection(cfg.Bandwidth.QuotaDirection)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BANDWIDTH_QUOTA_DIRECTION: %w", err)
+	}
+
+	return &bandwidth.Accountant{
+		Store:    bandwidth.NewStore(cfg.Bandwidth.File),
+		Counters: collector.InterfaceCounters,
+		Notifier: notifier,
+		Quota: bandwidth.Quota{
+			Limit:       cfg.Bandwidth.Quota,
+			Direction:   direction,
+			WarnPercent: cfg.Bandwidth.QuotaWarnPercent,
+		},
+		Interval: cfg.Bandwidth.Interval,
+		Logger:   logger,
+	}, nil
+}
+
+// startAlerts checks the thresholds every ALERT_INTERVAL. current returns
+// the running configuration, so a reload can change the interval, the
+// thresholds or switch alerts on and off.
+func (r *Runner) startAlerts(ctx context.Context, notifier *alerts.Notifier, sampler *metrics.Sampler, agents []*agent.Client, current func() app.Config) {
+	if notifier == nil || sampler == nil {
+		return
+	}
 
 	go func() {
-		// goroutine (multithread)
-		ticker := time.NewTicker(cfg.Alerts.Interval)
+		interval := current().Alerts.Interval
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
-		// if the app is closed, exit the goroutine (ctx.Done())
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				// each interval, runAlertCycle
-				r.runAlertCycle(ctx, bot, collector, cfg, state)
+				cfg := current()
+				if cfg.Alerts.Interval != interval {
+					interval = cfg.Alerts.Interval
+					ticker.Reset(interval)
+				}
+				if !cfg.Alerts.Enabled || cfg.OwnerID == 0 {
+					continue
+				}
+				r.runAlertCycle(ctx, notifier, sampler, cfg)
+				r.runAgentAlertCycle(ctx, notifier, agents, cfg)
 			}
 		}
 	}()
 }
 
-func (r *Runner) runAlertCycle(ctx context.Context, bot *tgbotapi.BotAPI, collector *metrics.Collector, cfg app.Config, state map[string]time.Time) {
+func (r *Runner) runAlertCycle(ctx context.Context, notifier *alerts.Notifier, sampler *metrics.Sampler, cfg app.Config) {
 	alertCtx, cancel := context.WithTimeout(ctx, cfg.CommandTimeout)
 	defer cancel()
 
-	stats, err := collector.Collect(alertCtx)
+	// Reuse the background sample unless it is older than one alert interval.
+	snap, err := sampler.Get(alertCtx, cfg.Alerts.Interval)
 	if err != nil {
 		if r.logger != nil {
 			r.logger.Printf("alert collect error: %v", err)
 		}
 		return
 	}
+	r.checkStats(notifier, snap, cfg)
+}
 
-	type alert struct {
-		key  string
-		body string
-	}
+// runAgentAlertCycle checks every agent's latest snapshot concurrently. An
+// agent that cannot be reached raises its own alert.
+func (r *Runner) runAgentAlertCycle(ctx context.Context, notifier *alerts.Notifier, agents []*agent.Client, cfg app.Config) {
+	alertCtx, cancel := context.WithTimeout(ctx, cfg.CommandTimeout)
+	defer cancel()
 
-	var alerts []alert
-	if stats.CPU.Usage >= cfg.Alerts.CPUThreshold && stats.CPU.Usage > 0 {
-		alerts = append(alerts, alert{
-			key:  "cpu",
-			body: fmt.Sprintf("[⚠️ ALERTA] CPU alta: %.1f%% (umbral %.0f%%)", stats.CPU.Usage, cfg.Alerts.CPUThreshold),
-		})
-	}
-	if stats.Memory.UsedPercent >= cfg.Alerts.MemoryThreshold && stats.Memory.UsedPercent > 0 {
-		alerts = append(alerts, alert{
-			key:  "memory",
-			body: fmt.Sprintf("[⚠️ ALERTA] RAM alta: %.1f%% (umbral %.0f%%)", stats.Memory.UsedPercent, cfg.Alerts.MemoryThreshold),
-		})
+	var wg sync.WaitGroup
+	for _, a := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "agent:" + a.Name()
+			snap, err := a.Stats(alertCtx, false)
+			if err != nil {
+				if err := notifier.Fire(key, i18n.Tf("alert.agent_down", a.Name(), err)); err != nil && r.logger != nil {
+					r.logger.Printf("alert send error: %v", err)
+				}
+				return
+			}
+			if err := notifier.Resolve(key, i18n.Tf("alert.agent_up", a.Name())); err != nil && r.logger != nil {
+				r.logger.Printf("alert send error: %v", err)
+			}
+			r.checkStats(notifier, snap, cfg)
+		}()
 	}
-	for _, disk := range stats.Disks {
-		if disk.UsedPercent >= cfg.Alerts.DiskThreshold && disk.UsedPercent > 0 {
-			alerts = append(alerts, alert{
-				key:  "disk:" + disk.Mount,
-				body: fmt.Sprintf("[⚠️ ALERTA] Disco %s al %.1f%% (umbral %.0f%%)", disk.Mount, disk.UsedPercent, cfg.Alerts.DiskThreshold),
-			})
+	wg.Wait()
+}
+
+// checkStats applies the resource thresholds to snap. Alerts for an agent's
+// snapshot are tagged with its name, in the key ("cpu@nas") and the message.
+func (r *Runner) checkStats(notifier *alerts.Notifier, snap metrics.Snapshot, cfg app.Config) {
+	stats := snap.Stats
+	check := func(key string, firing bool, messageKey string, args ...any) {
+		message := func(lang string) string {
+			return i18n.T(lang, "alert.firing", i18n.T(lang, messageKey, args...))
+		}
+		if snap.Host != "" {
+			key += "@" + snap.Host
+			message = func(lang string) string {
+				return i18n.T(lang, "alert.on_host", snap.Host, i18n.T(lang, messageKey, args...))
+			}
 		}
+		r.checkThreshold(notifier, key, firing, message)
 	}
 
-	now := time.Now()
-	for _, a := range alerts {
-		if last, ok := state[a.key]; ok && now.Sub(last) < cfg.Alerts.Cooldown {
-			continue
+	check("cpu", stats.CPU.Usage >= cfg.Alerts.CPUThreshold && stats.CPU.Usage > 0,
+		"alert.cpu", stats.CPU.Usage, cfg.Alerts.CPUThreshold)
+	check("memory", stats.Memory.UsedPercent >= cfg.Alerts.MemoryThreshold && stats.Memory.UsedPercent > 0,
+		"alert.memory", stats.Memory.UsedPercent, cfg.Alerts.MemoryThreshold)
+	for _, disk := range stats.Disks {
+		check("disk:"+disk.Mount, disk.UsedPercent >= cfg.Alerts.DiskThreshold && disk.UsedPercent > 0,
+			"alert.disk", disk.Mount, disk.UsedPercent, cfg.Alerts.DiskThreshold)
+	}
+	if cfg.Alerts.TempThreshold > 0 {
+		for _, temp := range stats.Sensors.Temperatures {
+			limit := cfg.Alerts.TempThreshold
+			if temp.Critical > 0 && temp.Critical < limit {
+				limit = temp.Critical
+			}
+			check("temp:"+temp.Name(), temp.Celsius >= limit,
+				"alert.temp", temp.Name(), temp.Celsius, limit)
 		}
-
-		if err := sendAlert(bot, cfg.OwnerID, a.body); err != nil && r.logger != nil {
-			r.logger.Printf("alert send error: %v", err)
-		} else {
-			state[a.key] = now
+		for _, gpu := range stats.GPU {
+			check("temp:"+gpu.Label(), gpu.Temperature >= cfg.Alerts.TempThreshold,
+				"alert.temp", gpu.Label(), gpu.Temperature, cfg.Alerts.TempThreshold)
 		}
 	}
 }
 
+// checkThreshold fires or silently resolves a host alert depending on firing.
+func (r *Runner) checkThreshold(notifier *alerts.Notifier, key string, firing bool, message i18n.Text) {
+	var err error
+	if firing {
+		err = notifier.Fire(key, message)
+	} else {
+		err = notifier.Resolve(key, nil)
+	}
+	if err != nil && r.logger != nil {
+		r.logger.Printf("alert send error: %v", err)
+	}
+}
+
 func sendAlert(bot *tgbotapi.BotAPI, chatID int64, message string) error {
 	if bot == nil || chatID == 0 || message == "" {
 		return nil
@@ -2,10 +2,14 @@ package bot
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"log"
 	"strings"
 	"testing"
+	"time"
 
+	"serverbot/internal/alerts"
 	"serverbot/internal/app"
 	"serverbot/internal/commands"
 	"serverbot/internal/metrics"
@@ -91,9 +95,9 @@ func TestRegisterCommandsCatalog(t *testing.T) {
 	reg := commands.NewRegistry(commands.Dependencies{
 		Config: app.Config{OwnerID: 123},
 	})
-	collector := metrics.NewCollector(metrics.Options{})
+	sampler := &metrics.Sampler{Collector: metrics.NewCollector(metrics.Options{})}
 
-	registerCommands(reg, collector, nil)
+	registerCommands(reg, sampler, services{})
 
 	public := reg.List(commands.ScopePublic)
 	if len(public) != 2 {
@@ -107,7 +111,7 @@ func TestRegisterCommandsCatalog(t *testing.T) {
 	}
 
 	admin := reg.List(commands.ScopeAdmin)
-	expectedAdmin := []string{"top", "docker", "swap_mc_server", "docker_exec"}
+	expectedAdmin := []string{"top", "proc", "docker", "swap_mc_server", "docker_exec"}
 	if len(admin) != len(expectedAdmin) {
 		t.Fatalf("admin commands length = %d, want %d", len(admin), len(expectedAdmin))
 	}
@@ -118,7 +122,7 @@ func TestRegisterCommandsCatalog(t *testing.T) {
 	}
 
 	owner := reg.List(commands.ScopeOwner)
-	expectedOwner := []string{"docker_logs", "logs_suscripcion", "docker_stats", "docker_restart", "service_status", "ping", "reboot"}
+	expectedOwner := []string{"docker_logs", "logs_suscripcion", "docker_stats", "docker_restart", "service_status", "ping", "traceroute", "dns", "http_check", "port_check", "kill", "renice", "reboot"}
 	if len(owner) != len(expectedOwner) {
 		t.Fatalf("owner commands length = %d, want %d", len(owner), len(expectedOwner))
 	}
@@ -128,3 +132,157 @@ func TestRegisterCommandsCatalog(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckStatsTagsAgentAlerts(t *testing.T) {
+	var sent []string
+	notifier := alerts.NewNotifier(time.Minute, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	cfg := app.Config{Alerts: app.AlertConfig{CPUThreshold: 90, MemoryThreshold: 90, DiskThreshold: 90}}
+	r := New(log.New(io.Discard, "", 0))
+
+	stats := metrics.Stats{CPU: metrics.CPUStats{Usage: 95}}
+	r.checkStats(notifier, metrics.Snapshot{Stats: stats, Host: "nas"}, cfg)
+	r.checkStats(notifier, metrics.Snapshot{Stats: stats}, cfg)
+
+	if !notifier.Active("cpu@nas") || !notifier.Active("cpu") {
+		t.Fatalf("active keys = %v", notifier.ActiveKeys(""))
+	}
+	if len(sent) != 2 || !strings.HasPrefix(sent[0], "[⚠️ ALERTA] [nas] CPU alta: 95.0%") || strings.Contains(sent[1], "[nas]") {
+		t.Fatalf("sent = %q", sent)
+	}
+}
+
+func TestReloaderAppliesSettings(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:secret")
+	t.Setenv("OWNER_ID", "1")
+	started, err := app.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := commands.NewRegistry(commands.Dependencies{Config: started})
+	collector := metrics.NewCollector(metrics.Options{DiskTargets: started.DiskTargets})
+	rl := newReloader(started, log.New(io.Discard, "", 0))
+	rl.registry, rl.collector = registry, collector
+	rl.notifier = alerts.NewNotifier(time.Minute, func(string) error { return nil })
+	registerCommands(registry, nil, services{reload: rl})
+
+	t.Setenv("OWNER_ID", "2")
+	t.Setenv("ALERT_CPU_THRESHOLD", "50")
+	t.Setenv("METRICS_LISTEN_ADDR", ":9100")
+	restart, err := rl.reload()
+	if err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	cfg := rl.current()
+	if cfg.OwnerID != 2 || cfg.Alerts.CPUThreshold != 50 || cfg.MetricsListenAddr != "" {
+		t.Fatalf("current() = %+v", cfg)
+	}
+	if strings.Join(restart, ",") != "METRICS_LISTEN_ADDR" {
+		t.Fatalf("restart = %v", restart)
+	}
+
+	// The new owner can read the configuration, with the token redacted.
+	bot, client := testutil.NewFakeBot()
+	if err := registry.Dispatch(context.Background(), bot, commands.SyntheticUpdate(2, "config", "")); err != nil {
+		t.Fatal(err)
+	}
+	text := client.Requests()[0].Values.Get("text")
+	if !strings.Contains(text, "OWNER_ID = 2 (env)") || strings.Contains(text, "secret") {
+		t.Fatalf("config reply = %q", text)
+	}
+
+	// An invalid configuration leaves the running one in place.
+	t.Setenv("OWNER_ID", "3")
+	t.Setenv("ALERT_INTERVAL", "soon")
+	if _, err := rl.reload(); err == nil || !strings.Contains(err.Error(), "ALERT_INTERVAL") {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if rl.current().OwnerID != 2 {
+		t.Fatalf("owner after rejected reload = %d", rl.current().OwnerID)
+	}
+}
+
+func TestMenuFollowsRoles(t *testing.T) {
+	registry := commands.NewRegistry(commands.Dependencies{})
+	noop := func(*commands.Context) error { return nil }
+	registry.Handle("lang", "cmd.lang", commands.ScopePublic, noop)
+	registry.Handle("status", "Estado", commands.ScopeAdmin, noop)
+	registry.Handle("reload", "cmd.reload", commands.ScopeOwner, noop)
+	registry.HandleHidden("debug", noop)
+
+	bot, client := testutil.NewFakeBot()
+	m := &menu{bot: bot, registry: registry}
+	if err := m.publish(app.Config{OwnerID: 1, AdminIDs: []int64{1, 5}}); err != nil {
+		t.Fatalf("publish() error = %v", err)
+	}
+
+	menus := make(map[string]string)
+	for _, req := range client.Requests() {
+		if req.Endpoint != "setMyCommands" {
+			t.Fatalf("unexpected request %s", req.Endpoint)
+		}
+		menus[req.Values.Get("scope")+req.Values.Get("language_code")] = req.Values.Get("commands")
+	}
+	if want := 3 * len(menuLanguages()); len(client.Requests()) != want {
+		t.Fatalf("sent %d menus, want %d", len(client.Requests()), want)
+	}
+	want := map[string][]string{
+		`{"type":"default"}`:            {`"lang"`},
+		`{"type":"chat","chat_id":5}`:   {`"lang"`, `"status"`},
+		`{"type":"chat","chat_id":1}`:   {`"lang"`, `"status"`, `"reload"`},
+		`{"type":"chat","chat_id":1}en`: {`"Chat language"`},
+		`{"type":"chat","chat_id":1}es`: {`"Idioma del chat"`},
+		`{"type":"default"}en`:          {`"lang"`},
+		`{"type":"chat","chat_id":5}en`: {`"status"`, `"Estado"`},
+		`{"type":"chat","chat_id":5}es`: {`"status"`},
+		`{"type":"default"}es`:          {`"lang"`},
+	}
+	for key, needles := range want {
+		got, ok := menus[key]
+		if !ok {
+			t.Fatalf("no menu for %s in %v", key, menus)
+		}
+		for _, needle := range needles {
+			if !strings.Contains(got, needle) {
+				t.Fatalf("menu %s = %s, want %s", key, got, needle)
+			}
+		}
+		if strings.Contains(got, "debug") {
+			t.Fatalf("menu %s lists a hidden command: %s", key, got)
+		}
+	}
+	if got := menus[`{"type":"default"}`]; strings.Contains(got, "status") {
+		t.Fatalf("public menu = %s", got)
+	}
+
+	// A chat that loses its role gets its scoped menu removed.
+	before := len(client.Requests())
+	if err := m.publish(app.Config{OwnerID: 1}); err != nil {
+		t.Fatalf("publish() error = %v", err)
+	}
+	var deleted []string
+	for _, req := range client.Requests()[before:] {
+		if req.Endpoint == "deleteMyCommands" {
+			deleted = append(deleted, req.Values.Get("scope"))
+		}
+	}
+	if len(deleted) != len(menuLanguages()) || deleted[0] != `{"type":"chat","chat_id":5}` {
+		t.Fatalf("deleted = %v", deleted)
+	}
+}
+
+func TestMenuKeepsGoingAfterAFailedLanguage(t *testing.T) {
+	registry := commands.NewRegistry(commands.Dependencies{})
+	registry.Handle("lang", "cmd.lang", commands.ScopePublic, func(*commands.Context) error { return nil })
+
+	bot, client := testutil.NewFakeBot(testutil.FakeResponse{StatusCode: 400, Body: `{"ok":false,"error_code":400,"description":"Bad Request"}`})
+	m := &menu{bot: bot, registry: registry}
+	if err := m.publish(app.Config{}); err == nil || !strings.Contains(err.Error(), "Bad Request") {
+		t.Fatalf("publish() error = %v", err)
+	}
+	if got, want := len(client.Requests()), len(menuLanguages()); got != want {
+		t.Fatalf("sent %d menus, want %d", got, want)
+	}
+}
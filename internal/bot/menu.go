@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// menuLimit is the most commands Telegram accepts in one menu.
+	menuLimit = 100
+	// menuDescriptionLimit is the longest description Telegram accepts.
+	menuDescriptionLimit = 256
+)
+
+// menu publishes the registry as the Telegram command menu: the public
+// commands for everyone and, scoped to their chats, the admin and owner sets,
+// so autocomplete offers what /help lists. Every set is sent once without a
+// language code, in the default language, and once per catalog language.
+type menu struct {
+	bot      *tgbotapi.BotAPI
+	registry *commands.Registry
+
+	mu sync.Mutex
+	// chats holds the chats given a scoped menu by the last publish, so a
+	// chat that loses its role falls back to the public menu.
+	chats []int64
+}
+
+// publish sends the menus for the roles in cfg and clears the menus of
+// chats that no longer have a role. It keeps going after a failed request
+// and returns every error.
+func (m *menu) publish(cfg app.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	public := m.registry.List(commands.ScopePublic)
+	admin := maps.Clone(public)
+	maps.Copy(admin, m.registry.List(commands.ScopeAdmin))
+	owner := maps.Clone(admin)
+	maps.Copy(owner, m.registry.List(commands.ScopeOwner))
+
+	errs := []error{m.set(tgbotapi.NewBotCommandScopeDefault(), public)}
+	var chats []int64
+	for _, id := range cfg.AdminIDs {
+		if id == cfg.OwnerID || slices.Contains(chats, id) {
+			continue
+		}
+		chats = append(chats, id)
+		errs = append(errs, m.set(tgbotapi.NewBotCommandScopeChat(id), admin))
+	}
+	if cfg.OwnerID != 0 {
+		chats = append(chats, cfg.OwnerID)
+		errs = append(errs, m.set(tgbotapi.NewBotCommandScopeChat(cfg.OwnerID), owner))
+	}
+
+	for _, id := range m.chats {
+		if !slices.Contains(chats, id) {
+			errs = append(errs, m.clear(tgbotapi.NewBotCommandScopeChat(id)))
+		}
+	}
+	m.chats = chats
+	return errors.Join(errs...)
+}
+
+// set sends cmds, a name to description map, as the menu of scope in every
+// language, going on after a failed request.
+func (m *menu) set(scope tgbotapi.BotCommandScope, cmds map[string]string) error {
+	var errs []error
+	for _, code := range menuLanguages() {
+		lang := code
+		if lang == "" {
+			lang = i18n.Default()
+		}
+		req := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, code, menuCommands(lang, cmds)...)
+		if _, err := m.bot.Request(req); err != nil {
+			errs = append(errs, fmt.Errorf("set %s menu: %w", menuScope(scope, code), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// clear removes the menus of scope in every language, going on after a
+// failed request.
+func (m *menu) clear(scope tgbotapi.BotCommandScope) error {
+	var errs []error
+	for _, code := range menuLanguages() {
+		req := tgbotapi.NewDeleteMyCommandsWithScopeAndLanguage(scope, code)
+		if _, err := m.bot.Request(req); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s menu: %w", menuScope(scope, code), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// menuLanguages lists the language codes a menu is sent with; "" is the
+// fallback Telegram shows when the user's language has no menu of its own.
+func menuLanguages() []string {
+	return append([]string{""}, i18n.Languages()...)
+}
+
+// menuCommands turns cmds into a menu in lang, sorted by name and cut to
+// what Telegram accepts.
+func menuCommands(lang string, cmds map[string]string) []tgbotapi.BotCommand {
+	names := slices.Sorted(maps.Keys(cmds))
+	if len(names) > menuLimit {
+		names = names[:menuLimit]
+	}
+	out := make([]tgbotapi.BotCommand, 0, len(names))
+	for _, name := range names {
+		desc := []rune(i18n.T(lang, cmds[name]))
+		if len(desc) > menuDescriptionLimit {
+			desc = append(desc[:menuDescriptionLimit-1], '…')
+		}
+		out = append(out, tgbotapi.BotCommand{Command: name, Description: string(desc)})
+	}
+	return out
+}
+
+func menuScope(scope tgbotapi.BotCommandScope, code string) string {
+	name := scope.Type
+	if scope.ChatID != 0 {
+		name = fmt.Sprintf("chat %d", scope.ChatID)
+	}
+	if code != "" {
+		name += " (" + code + ")"
+	}
+	return name
+}
@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"serverbot/internal/agent"
+	"serverbot/internal/alerts"
+	"serverbot/internal/app"
+	"serverbot/internal/bandwidth"
+	"serverbot/internal/certs"
+	"serverbot/internal/commands"
+	"serverbot/internal/custom"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+	"serverbot/internal/systemd"
+	"serverbot/internal/uptime"
+)
+
+// reloader holds the running configuration and applies the reloadable
+// settings again on SIGHUP or /reload: roles, disk targets, alert settings,
+// the default language and the custom commands. The Telegram connection is left
+// untouched; only the command menu is published again.
+type reloader struct {
+	registry  *commands.Registry
+	notifier  *alerts.Notifier
+	collector *metrics.Collector
+	custom    *custom.Set
+	menu      *menu
+	logger    *log.Logger
+	// load reads the configuration; app.LoadConfig unless a test replaces it.
+	load func() (app.Config, error)
+
+	mu      sync.Mutex
+	started app.Config
+	cfg     app.Config
+}
+
+func newReloader(cfg app.Config, logger *log.Logger) *reloader {
+	return &reloader{logger: logger, load: app.LoadConfig, started: cfg, cfg: cfg}
+}
+
+// current returns the configuration in effect.
+func (rl *reloader) current() app.Config {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.cfg
+}
+
+// reload reads the configuration again. An invalid configuration is
+// rejected whole and the running one stays. It returns the changed settings
+// that only take effect after a restart.
+func (rl *reloader) reload() ([]string, error) {
+	next, err := rl.load()
+	if err == nil {
+		err = checkConfig(next)
+	}
+	var cmds []custom.Command
+	if err == nil && rl.custom != nil {
+		cmds, err = custom.Load(rl.started.CommandsFile)
+		if err != nil {
+			err = fmt.Errorf("invalid COMMANDS_FILE: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rl.custom != nil {
+		if err := rl.custom.Apply(cmds); err != nil {
+			return nil, fmt.Errorf("invalid COMMANDS_FILE: %w", err)
+		}
+	}
+
+	rl.mu.Lock()
+	rl.cfg = rl.cfg.Reload(next)
+	cfg := rl.cfg
+	rl.mu.Unlock()
+
+	if rl.registry != nil {
+		rl.registry.SetConfig(cfg)
+	}
+	// checkConfig accepted the language.
+	_ = i18n.SetDefault(defaultLanguage(cfg))
+	if rl.notifier != nil {
+		rl.notifier.SetCooldown(cfg.Alerts.Cooldown)
+	}
+	if rl.collector != nil {
+		rl.collector.SetDiskTargets(cfg.DiskTargets)
+	}
+	if rl.menu != nil {
+		// The reload stands even if Telegram cannot be reached.
+		if err := rl.menu.publish(cfg); err != nil {
+			rl.logger.Printf("command menu not updated: %v", err)
+		}
+	}
+	return rl.started.RestartRequired(next), nil
+}
+
+// watchHangup reloads the configuration on every SIGHUP.
+func (rl *reloader) watchHangup(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			restart, err := rl.reload()
+			switch {
+			case err != nil:
+				rl.logger.Printf("reload rejected, keeping the running configuration: %v", err)
+			case len(restart) > 0:
+				rl.logger.Printf("configuration reloaded; restart to apply %s", strings.Join(restart, ", "))
+			default:
+				rl.logger.Printf("configuration reloaded")
+			}
+		}
+	}
+}
+
+// HandleReload is the handler for /reload.
+func (rl *reloader) HandleReload(ctx *commands.Context) error {
+	restart, err := rl.reload()
+	if err != nil {
+		return ctx.ReplyHTML(ctx.T("reload.invalid", html.EscapeString(err.Error())), false)
+	}
+	text := ctx.T("reload.done")
+	if len(restart) > 0 {
+		text += "\n" + ctx.T("reload.restart", strings.Join(restart, ", "))
+	}
+	return ctx.Reply(text)
+}
+
+// HandleConfig is the handler for /config. Secrets are redacted.
+func (rl *reloader) HandleConfig(ctx *commands.Context) error {
+	settings := rl.current().Settings()
+	if len(settings) == 0 {
+		return ctx.Reply(ctx.T("config.empty"))
+	}
+	var b strings.Builder
+	for _, s := range settings {
+		origin := s.Origin
+		if origin != "env" {
+			origin = ctx.T("config.origin_file")
+		}
+		fmt.Fprintf(&b, "%s = %s (%s)\n", s.Key, s.Value, origin)
+	}
+	return ctx.ReplyHTML(ctx.T("config.list", html.EscapeString(strings.TrimSpace(b.String()))), false)
+}
+
+// checkConfig validates the settings that other packages parse, reporting
+// every problem at once like app.LoadConfig.
+func checkConfig(cfg app.Config) error {
+	var errs []error
+	check := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+		}
+	}
+	for _, spec := range cfg.AgentHosts {
+		_, _, err := agent.ParseHost(spec)
+		check("AGENT_HOSTS", err)
+	}
+	for _, spec := range cfg.SSH.Hosts {
+		_, err := system.ParseSSHTarget(spec)
+		check("SSH_HOSTS", err)
+	}
+	for _, spec := range cfg.Uptime.Checks {
+		_, err := uptime.ParseCheck(spec)
+		check("UPTIME_CHECKS", err)
+	}
+	for _, spec := range cfg.Certs.Hosts {
+		_, err := certs.ParseHost(spec)
+		check("CERT_HOSTS", err)
+	}
+	_, err := bandwidth.ParseDirection(cfg.Bandwidth.QuotaDirection)
+	check("BANDWIDTH_QUOTA_DIRECTION", err)
+	_, err = systemd.ParsePriority(cfg.Alerts.JournalPriority)
+	check("JOURNAL_ALERT_PRIORITY", err)
+	for _, unit := range cfg.Alerts.JournalUnits {
+		_, err := systemd.NormalizeUnit(unit)
+		check("JOURNAL_ALERT_UNITS", err)
+	}
+	if lang := defaultLanguage(cfg); !i18n.Supported(lang) {
+		check("BOT_LANGUAGE", fmt.Errorf("unsupported language %q: want one of %s", lang, strings.Join(i18n.Languages(), ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// defaultLanguage returns BOT_LANGUAGE, or the built-in default when unset.
+func defaultLanguage(cfg app.Config) string {
+	if cfg.Language == "" {
+		return i18n.DefaultLanguage
+	}
+	return cfg.Language
+}
@@ -0,0 +1,153 @@
+// Package certs reads TLS certificates from remote hosts and local PEM files
+// and alerts before they expire.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"serverbot/internal/netdiag"
+)
+
+// Cert is the leaf certificate found at a source.
+type Cert struct {
+	Source   string // host:port or file path
+	Subject  string
+	Issuer   string
+	SANs     []string
+	NotAfter time.Time
+	// VerifyErr is set when a host's chain does not verify against the
+	// trust store. Files are not verified.
+	VerifyErr string
+}
+
+// DaysLeft returns the whole days until expiry, negative once expired.
+func (c Cert) DaysLeft(now time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(now).Hours() / 24))
+}
+
+// ParseHost validates a "host[:port]" spec, defaulting to port 443.
+func ParseHost(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if _, _, err := net.SplitHostPort(spec); err != nil {
+		spec = net.JoinHostPort(strings.Trim(spec, "[]"), "443")
+	}
+	return netdiag.ValidateHostPort(spec)
+}
+
+// FetchHost performs a TLS handshake with addr and returns the served leaf.
+// The chain is verified separately so an invalid or expired certificate is
+// still reported. A nil roots uses the system trust store.
+func FetchHost(ctx context.Context, addr string, roots *x509.CertPool) (Cert, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return Cert{}, err
+	}
+
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return Cert{}, fmt.Errorf("tls dial %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	peers := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return Cert{}, fmt.Errorf("tls dial %s: no certificate presented", addr)
+	}
+
+	cert := fromX509(addr, peers[0])
+	intermediates := x509.NewCertPool()
+	for _, c := range peers[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = peers[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		cert.VerifyErr = err.Error()
+	}
+	return cert, nil
+}
+
+// ReadFile parses the first certificate of a PEM file, which for a
+// fullchain.pem is the leaf.
+func ReadFile(path string) (Cert, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Cert{}, err
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return Cert{}, fmt.Errorf("%s: no PEM certificate found", path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		leaf, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Cert{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return fromX509(path, leaf), nil
+	}
+}
+
+// ExpandFiles resolves the glob patterns in patterns, sorted and without
+// duplicates. Patterns that are plain paths are kept even when missing so the
+// error is reported.
+func ExpandFiles(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			matches = []string{pattern}
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				out = append(out, m)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func fromX509(source string, c *x509.Certificate) Cert {
+	sans := append([]string(nil), c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return Cert{
+		Source:   source,
+		Subject:  nameOf(c.Subject.CommonName, c.Subject.String()),
+		Issuer:   nameOf(c.Issuer.CommonName, c.Issuer.String()),
+		SANs:     sans,
+		NotAfter: c.NotAfter,
+	}
+}
+
+func nameOf(commonName, full string) string {
+	if commonName != "" {
+		return commonName
+	}
+	return full
+}
@@ -0,0 +1,199 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+)
+
+func writeCert(t *testing.T, path string, notAfter time.Time, names ...string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	// A key block first checks that non-certificate blocks are skipped.
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("x")})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+}
+
+func TestReadFileAndExpand(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"a.example", "b.example"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeCert(t, filepath.Join(dir, name, "fullchain.pem"), notAfter, name, "www."+name)
+	}
+
+	files, err := ExpandFiles([]string{filepath.Join(dir, "*", "fullchain.pem"), filepath.Join(dir, "missing.pem")})
+	if err != nil {
+		t.Fatalf("ExpandFiles() error = %v", err)
+	}
+	if len(files) != 3 || !strings.HasSuffix(files[0], "a.example/fullchain.pem") || !strings.HasSuffix(files[2], "missing.pem") {
+		t.Fatalf("files = %v", files)
+	}
+
+	cert, err := ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if cert.Subject != "a.example" || strings.Join(cert.SANs, ",") != "a.example,www.a.example" || !cert.NotAfter.Equal(notAfter) {
+		t.Fatalf("cert = %+v", cert)
+	}
+	if got := cert.DaysLeft(notAfter.Add(-36 * time.Hour)); got != 1 {
+		t.Fatalf("DaysLeft = %d, want 1", got)
+	}
+	if got := cert.DaysLeft(notAfter.Add(time.Hour)); got != -1 {
+		t.Fatalf("DaysLeft after expiry = %d, want -1", got)
+	}
+
+	if _, err := ReadFile(files[2]); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ReadFile(missing) error = %v", err)
+	}
+}
+
+func TestFetchHost(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	addr, err := ParseHost(strings.TrimPrefix(srv.URL, "https://"))
+	if err != nil {
+		t.Fatalf("ParseHost() error = %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	cert, err := FetchHost(context.Background(), addr, roots)
+	if err != nil {
+		t.Fatalf("FetchHost() error = %v", err)
+	}
+	if cert.VerifyErr != "" || !cert.NotAfter.Equal(srv.Certificate().NotAfter) {
+		t.Fatalf("cert = %+v", cert)
+	}
+
+	// Against the system store the test CA is unknown but the cert is still read.
+	cert, err = FetchHost(context.Background(), addr, nil)
+	if err != nil || cert.VerifyErr == "" {
+		t.Fatalf("FetchHost(system roots) = %+v, %v", cert, err)
+	}
+}
+
+func TestParseHost(t *testing.T) {
+	cases := map[string]string{
+		"example.com":      "example.com:443",
+		"Example.com:8443": "example.com:8443",
+		"10.0.0.1":         "10.0.0.1:443",
+		"[::1]:443":        "[::1]:443",
+	}
+	for spec, want := range cases {
+		if got, err := ParseHost(spec); err != nil || got != want {
+			t.Fatalf("ParseHost(%q) = %q, %v; want %q", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"", "-oProxy", "example.com:0", "bad host"} {
+		if _, err := ParseHost(spec); err == nil {
+			t.Fatalf("ParseHost(%q) expected error", spec)
+		}
+	}
+}
+
+func TestWatcherAnnouncesEachThresholdOnce(t *testing.T) {
+	var sent []string
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	w := &Watcher{
+		Notifier: alerts.NewNotifier(0, func(msg string) error { sent = append(sent, msg); return nil }),
+		now:      func() time.Time { return now },
+	}
+	cert := Cert{Source: "example.com:443", Issuer: "R11", NotAfter: now.Add(30 * 24 * time.Hour)}
+
+	steps := []struct {
+		daysLeft  int
+		wantAlert string
+	}{
+		{30, ""},
+		{20, "caduca en 20 dias"},
+		{15, ""},
+		{6, "caduca en 6 dias"},
+		{5, ""},
+		{1, "caduca en 1 dia ("},
+		{0, ""},
+		{-1, "ha caducado"},
+		{-2, ""},
+	}
+	for _, step := range steps {
+		before := len(sent)
+		w.evaluate(cert, cert.NotAfter.Add(-time.Duration(step.daysLeft)*24*time.Hour-time.Hour))
+		switch {
+		case step.wantAlert == "" && len(sent) != before:
+			t.Fatalf("%d days: unexpected alert %q", step.daysLeft, sent[len(sent)-1])
+		case step.wantAlert != "" && (len(sent) != before+1 || !strings.Contains(sent[before], step.wantAlert)):
+			t.Fatalf("%d days: alerts = %v, want %q", step.daysLeft, sent[before:], step.wantAlert)
+		}
+	}
+
+	renewed := cert
+	renewed.NotAfter = now.Add(90 * 24 * time.Hour)
+	w.evaluate(renewed, now)
+	if last := sent[len(sent)-1]; !strings.HasPrefix(last, "[✅ RECUPERADO] El certificado de example.com:443 se ha renovado") {
+		t.Fatalf("recovery = %q", last)
+	}
+	if w.Notifier.Active(keyPrefix + cert.Source) {
+		t.Fatalf("alert still active after renewal")
+	}
+}
+
+func TestFormatResults(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	got := FormatResults("", []Result{
+		{Source: "late.example:443", Cert: Cert{Issuer: "R11", SANs: []string{"late.example"}, NotAfter: now.Add(60 * 24 * time.Hour)}},
+		{Source: "/etc/missing.pem", Err: errors.New("open /etc/missing.pem: no such file or directory")},
+		{Source: "soon.example:443", Cert: Cert{Issuer: "R10", SANs: []string{"a", "b", "c", "d", "e", "f", "g"},
+			NotAfter: now.Add(5 * 24 * time.Hour), VerifyErr: "x509: certificate signed by unknown authority"}},
+	}, now)
+
+	order := []string{"❌ <b>/etc/missing.pem</b>", "🔴 <b>soon.example:443</b>", "🟢 <b>late.example:443</b>"}
+	pos := -1
+	for _, needle := range order {
+		i := strings.Index(got, needle)
+		if i <= pos {
+			t.Fatalf("%q missing or out of order:\n%s", needle, got)
+		}
+		pos = i
+	}
+	for _, needle := range []string{"Caduca: 2026-05-06 (5 dias)", "SAN: a, b, c, d, e y 2 mas", "Cadena no valida: x509", "Emisor: R11"} {
+		if !strings.Contains(got, needle) {
+			t.Fatalf("output missing %q:\n%s", needle, got)
+		}
+	}
+}
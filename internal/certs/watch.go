@@ -0,0 +1,240 @@
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+const keyPrefix = "cert:"
+
+// Thresholds are the days before expiry at which an alert is sent.
+var Thresholds = []int{21, 7, 1}
+
+// maxSANs bounds the names listed per certificate in /certs.
+const maxSANs = 5
+
+// Result is the outcome of reading one source.
+type Result struct {
+	Source string
+	Cert   Cert
+	Err    error
+}
+
+// Watcher checks Hosts and Files every Interval and alerts when a certificate
+// crosses one of the Thresholds or expires. Each threshold is announced once;
+// a renewed certificate sends a recovery notice.
+type Watcher struct {
+	Hosts    []string // host:port
+	Files    []string // paths or glob patterns
+	Roots    *x509.CertPool
+	Notifier *alerts.Notifier
+	Interval time.Duration
+	Timeout  time.Duration
+	Logger   *log.Logger
+
+	mu      sync.Mutex
+	alerted map[string]int // source -> last threshold announced, 0 once expired
+	now     func() time.Time
+}
+
+// Run checks the certificates immediately and then every Interval until ctx
+// is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	w.Check(ctx)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// Check runs a single expiry cycle.
+func (w *Watcher) Check(ctx context.Context) {
+	now := w.clock()
+	for _, res := range w.Collect(ctx) {
+		if res.Err != nil {
+			w.log("%s: %v", res.Source, res.Err)
+			continue
+		}
+		w.evaluate(res.Cert, now)
+	}
+}
+
+// Collect reads every host and file concurrently, ordered by source.
+func (w *Watcher) Collect(ctx context.Context) []Result {
+	files, err := ExpandFiles(w.Files)
+	if err != nil {
+		w.log("expand files: %v", err)
+	}
+
+	results := make([]Result, 0, len(w.Hosts)+len(files))
+	for _, host := range w.Hosts {
+		results = append(results, Result{Source: host})
+	}
+	for _, file := range files {
+		results = append(results, Result{Source: file})
+	}
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := &results[i]
+			if i < len(w.Hosts) {
+				fetchCtx, cancel := system.WithTimeout(ctx, w.Timeout)
+				defer cancel()
+				res.Cert, res.Err = FetchHost(fetchCtx, res.Source, w.Roots)
+				return
+			}
+			res.Cert, res.Err = ReadFile(res.Source)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (w *Watcher) evaluate(c Cert, now time.Time) {
+	key := keyPrefix + c.Source
+	days := c.DaysLeft(now)
+	stage := stageFor(days)
+
+	w.mu.Lock()
+	if w.alerted == nil {
+		w.alerted = make(map[string]int)
+	}
+	last, announced := w.alerted[c.Source]
+	switch {
+	case stage < 0:
+		delete(w.alerted, c.Source)
+	case !announced || stage < last:
+		w.alerted[c.Source] = stage
+	}
+	w.mu.Unlock()
+
+	var err error
+	switch {
+	case stage < 0 && announced:
+		err = w.Notifier.Resolve(key, i18n.Nf("alert.cert_renewed", days,
+			c.Source, c.NotAfter.Format("2006-01-02"), days))
+	case stage == 0 && (!announced || last > 0):
+		err = w.Notifier.Fire(key, i18n.Tf("alert.cert_expired",
+			c.Source, c.NotAfter.Format("2006-01-02"), c.Issuer))
+	case stage > 0 && (!announced || stage < last):
+		err = w.Notifier.Fire(key, i18n.Nf("alert.cert_expiring", days,
+			c.Source, days, c.NotAfter.Format("2006-01-02"), c.Issuer))
+	}
+	if err != nil {
+		w.log("alert send error: %v", err)
+	}
+}
+
+// stageFor returns the smallest threshold days has reached, 0 when the
+// certificate has expired and -1 when no threshold applies.
+func stageFor(days int) int {
+	if days < 0 {
+		return 0
+	}
+	stage := -1
+	for _, t := range Thresholds {
+		if days <= t && (stage < 0 || t < stage) {
+			stage = t
+		}
+	}
+	return stage
+}
+
+// HandleCerts lists every certificate with issuer, SANs and days left,
+// soonest expiry first.
+func (w *Watcher) HandleCerts(ctx *commands.Context) error {
+	results := w.Collect(ctx.RequestContext)
+	if len(results) == 0 {
+		return ctx.Reply(ctx.T("certs.none"))
+	}
+	return ctx.ReplyHTML(FormatResults(ctx.Lang, results, w.clock()), false)
+}
+
+// FormatResults renders results as the /certs HTML message in lang.
+func FormatResults(lang string, results []Result, now time.Time) string {
+	sorted := append([]Result(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if (sorted[i].Err == nil) != (sorted[j].Err == nil) {
+			return sorted[i].Err != nil
+		}
+		return sorted[i].Cert.NotAfter.Before(sorted[j].Cert.NotAfter)
+	})
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "certs.title") + "\n")
+	for _, res := range sorted {
+		if res.Err != nil {
+			metrics.WriteSection(&b, "❌", res.Source, []string{"Error: " + res.Err.Error()})
+			continue
+		}
+
+		c := res.Cert
+		days := c.DaysLeft(now)
+		expiry := i18n.N(lang, "certs.expires", days, c.NotAfter.Format("2006-01-02"), days)
+		if days < 0 {
+			expiry = i18n.N(lang, "certs.expired", -days, c.NotAfter.Format("2006-01-02"), -days)
+		}
+		lines := []string{expiry, i18n.T(lang, "certs.issuer", c.Issuer)}
+		if len(c.SANs) > 0 {
+			lines = append(lines, "SAN: "+joinSANs(lang, c.SANs))
+		}
+		if c.VerifyErr != "" {
+			lines = append(lines, i18n.T(lang, "certs.invalid_chain", c.VerifyErr))
+		}
+		metrics.WriteSection(&b, expiryIcon(days), res.Source, lines)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func expiryIcon(days int) string {
+	switch {
+	case days <= 7:
+		return "🔴"
+	case days <= 21:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+func joinSANs(lang string, sans []string) string {
+	if len(sans) <= maxSANs {
+		return strings.Join(sans, ", ")
+	}
+	return i18n.T(lang, "certs.more_sans", strings.Join(sans[:maxSANs], ", "), len(sans)-maxSANs)
+}
+
+func (w *Watcher) clock() time.Time {
+	if w.now != nil {
+		return w.now()
+	}
+	return time.Now()
+}
+
+func (w *Watcher) log(format string, args ...any) {
+	if w.Logger != nil {
+		w.Logger.Printf("certs: "+format, args...)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"serverbot/internal/app"
+	"serverbot/internal/audit"
+	"serverbot/internal/i18n"
 	"serverbot/internal/system"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -18,12 +20,17 @@ type Context struct {
 	AppConfig app.Config
 	Runner    system.Runner
 	Logger    *log.Logger
+	// Host is the machine selected with "@name", or nil for this one. Runner
+	// already targets it.
+	Host Host
 
 	RequestContext context.Context
 	Bot            *tgbotapi.BotAPI
 	Update         tgbotapi.Update
 	Command        string
 	Arguments      string
+	// Lang is the language of the chat, used by T and N.
+	Lang string
 }
 
 // Args returns the raw arguments string.
@@ -43,6 +50,16 @@ func (c *Context) ArgsList() []string {
 	return fields
 }
 
+// T returns the message key in the language of the chat.
+func (c *Context) T(key string, args ...any) string {
+	return i18n.T(c.Lang, key, args...)
+}
+
+// N returns the plural message key for n in the language of the chat.
+func (c *Context) N(key string, n int, args ...any) string {
+	return i18n.N(c.Lang, key, n, args...)
+}
+
 // Reply sends a plain text message to the chat.
 func (c *Context) Reply(text string) error {
 	if c.Bot == nil || c.Update.Message == nil {
@@ -92,6 +109,20 @@ func (c *Context) ReplyPre(content string) error {
 	return c.ReplyHTML(body, false)
 }
 
+// ReplyDocument sends data as a file attachment named name.
+func (c *Context) ReplyDocument(name string, data []byte, caption string) error {
+	if c.Bot == nil || c.Update.Message == nil {
+		return fmt.Errorf("cannot reply without message context")
+	}
+
+	doc := tgbotapi.NewDocument(c.Update.Message.Chat.ID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = caption
+	if _, err := c.Bot.Send(doc); err != nil {
+		return fmt.Errorf("send document: %w", err)
+	}
+	return nil
+}
+
 // ReplyAndEdit posts a placeholder message and edits it with the final HTML.
 func (c *Context) ReplyAndEdit(initial string, finalHTML string) error {
 	if c.Bot == nil || c.Update.Message == nil {
@@ -151,3 +182,23 @@ func (c *Context) IsAdmin() bool {
 	}
 	return false
 }
+
+// Audit logs a privileged action and, when AUDIT_LOG_FILE is set, appends it
+// there. The requesting user and chat are filled in from the update.
+func (c *Context) Audit(entry audit.Entry) {
+	if msg := c.Update.Message; msg != nil {
+		entry.ChatID = msg.Chat.ID
+		if msg.From != nil {
+			entry.UserID = msg.From.ID
+		}
+	}
+	if c.Logger != nil {
+		c.Logger.Printf("audit: %s", entry)
+	}
+	if c.AppConfig.AuditLogFile == "" {
+		return
+	}
+	if err := audit.Append(c.AppConfig.AuditLogFile, entry); err != nil && c.Logger != nil {
+		c.Logger.Printf("audit: %v", err)
+	}
+}
@@ -14,11 +14,11 @@ func Docker(ctx *Context) error {
 
 	stdout, stderr, err := ctx.Runner.Run(runCtx, "docker", "ps", "--format", "table {{.Names}}\t{{.Status}}\t{{.Ports}}")
 	if err != nil {
-		return ctx.ReplyError("No se pudo consultar Docker.", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+		return ctx.ReplyError(ctx.T("docker.error"), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
 	}
 
 	if strings.TrimSpace(stdout) == "" {
-		return ctx.Reply("No hay contenedores activos.")
+		return ctx.Reply(ctx.T("docker.empty"))
 	}
 
 	return ctx.ReplyPre(stdout)
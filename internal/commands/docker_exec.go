@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"unicode"
@@ -12,15 +13,19 @@ import (
 func DockerExec(ctx *Context) error {
 	raw := strings.TrimSpace(ctx.Args())
 	if raw == "" {
-		return ctx.Reply("Uso: /docker_exec <nombre_contenedor> <comando>")
+		return ctx.Reply(ctx.T("docker_exec.usage"))
 	}
 
 	tokens, err := splitArgs(raw)
 	if err != nil {
-		return ctx.Reply(fmt.Sprintf("Argumentos invalidos: %v", err))
+		key := "args.quote"
+		if errors.Is(err, errIncompleteEscape) {
+			key = "args.escape"
+		}
+		return ctx.Reply(ctx.T("args.invalid", ctx.T(key)))
 	}
 	if len(tokens) < 2 {
-		return ctx.Reply("Uso: /docker_exec <nombre_contenedor> <comando>")
+		return ctx.Reply(ctx.T("docker_exec.usage"))
 	}
 
 	container := tokens[0]
@@ -30,7 +35,7 @@ func DockerExec(ctx *Context) error {
 	}
 	if !ctx.IsOwner() {
 		if _, ok := allowed[container]; !ok {
-			return ctx.Reply("Solo puedes ejecutar comando en el contenedor \"mc-server\" o \"mc-server-mod\".")
+			return ctx.Reply(ctx.T("docker_exec.mc_only"))
 		}
 	}
 	commandArgs := tokens[1:]
@@ -46,7 +51,7 @@ func DockerExec(ctx *Context) error {
 		if msg == "" {
 			msg = strings.TrimSpace(stdout)
 		}
-		return ctx.ReplyError("No se pudo ejecutar el comando en el contenedor.", fmt.Errorf("%w: %s", err, msg))
+		return ctx.ReplyError(ctx.T("docker_exec.error"), fmt.Errorf("%w: %s", err, msg))
 	}
 
 	out := strings.TrimSpace(stdout)
@@ -60,10 +65,15 @@ func DockerExec(ctx *Context) error {
 	case errOut != "":
 		return ctx.ReplyPre(errOut)
 	default:
-		return ctx.Reply("Comando ejecutado.")
+		return ctx.Reply(ctx.T("docker_exec.done"))
 	}
 }
 
+var (
+	errIncompleteEscape = errors.New("incomplete escape sequence")
+	errUnclosedQuote    = errors.New("unclosed quote")
+)
+
 func splitArgs(input string) ([]string, error) {
 	var args []string
 	var current strings.Builder
@@ -102,10 +112,10 @@ func splitArgs(input string) ([]string, error) {
 	}
 
 	if escaped {
-		return nil, fmt.Errorf("secuencia de escape incompleta")
+		return nil, errIncompleteEscape
 	}
 	if quote != 0 {
-		return nil, fmt.Errorf("comillas sin cerrar")
+		return nil, errUnclosedQuote
 	}
 
 	flush()
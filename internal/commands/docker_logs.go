@@ -11,7 +11,7 @@ import (
 func DockerLogs(ctx *Context) error {
 	args := ctx.ArgsList()
 	if len(args) == 0 {
-		return ctx.Reply("Uso: /docker_logs <nombre_contenedor>")
+		return ctx.Reply(ctx.T("docker_logs.usage"))
 	}
 
 	container := args[0]
@@ -25,7 +25,7 @@ func DockerLogs(ctx *Context) error {
 		if msg == "" {
 			msg = strings.TrimSpace(stdout)
 		}
-		return ctx.ReplyError("No se pudieron obtener los logs.", fmt.Errorf("%w: %s", err, msg))
+		return ctx.ReplyError(ctx.T("docker_logs.error"), fmt.Errorf("%w: %s", err, msg))
 	}
 
 	out := strings.TrimSpace(stdout)
@@ -39,6 +39,6 @@ func DockerLogs(ctx *Context) error {
 	case errOut != "":
 		return ctx.ReplyPre(errOut)
 	default:
-		return ctx.Reply("Sin logs recientes.")
+		return ctx.Reply(ctx.T("docker_logs.empty"))
 	}
 }
@@ -11,12 +11,12 @@ import (
 func DockerRestart(ctx *Context) error {
 	args := ctx.ArgsList()
 	if len(args) == 0 {
-		return ctx.Reply("Uso: /docker_restart <nombre_contenedor>")
+		return ctx.Reply(ctx.T("docker_restart.usage"))
 	}
 
 	container := args[0]
 	if !ctx.IsOwner() && container != "mc-server" {
-		return ctx.Reply("Solo puedes reiniciar el contenedor \"mc-server\".")
+		return ctx.Reply(ctx.T("docker_restart.mc_only"))
 	}
 
 	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
@@ -24,11 +24,11 @@ func DockerRestart(ctx *Context) error {
 
 	stdout, stderr, err := ctx.Runner.Run(runCtx, "docker", "restart", container)
 	if err != nil {
-		return ctx.ReplyError("No se pudo reiniciar el contenedor.", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+		return ctx.ReplyError(ctx.T("docker_restart.error"), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
 	}
 
 	if strings.TrimSpace(stdout) == "" {
-		return ctx.Reply("Contenedor reiniciado.")
+		return ctx.Reply(ctx.T("docker_restart.done"))
 	}
 
 	return ctx.ReplyPre(stdout)
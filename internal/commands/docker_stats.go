@@ -11,7 +11,7 @@ import (
 func DockerStats(ctx *Context) error {
 	args := ctx.ArgsList()
 	if len(args) == 0 {
-		return ctx.Reply("Uso: /docker_stats <nombre_contenedor>")
+		return ctx.Reply(ctx.T("docker_stats.usage"))
 	}
 
 	container := args[0]
@@ -26,12 +26,12 @@ func DockerStats(ctx *Context) error {
 		if msg == "" {
 			msg = strings.TrimSpace(stdout)
 		}
-		return ctx.ReplyError("No se pudieron obtener las estadisticas del contenedor.", fmt.Errorf("%w: %s", err, msg))
+		return ctx.ReplyError(ctx.T("docker_stats.error"), fmt.Errorf("%w: %s", err, msg))
 	}
 
 	line := strings.TrimSpace(stdout)
 	if line == "" {
-		return ctx.Reply("No se encontraron datos para el contenedor solicitado.")
+		return ctx.Reply(ctx.T("docker_stats.empty"))
 	}
 
 	fields := strings.Split(line, "\t")
@@ -39,10 +39,7 @@ func DockerStats(ctx *Context) error {
 		return ctx.ReplyPre(line)
 	}
 
-	body := fmt.Sprintf(
-		"Contenedor: %s\nCPU: %s\nMemoria: %s (%s)\nRed: %s\nDisco: %s",
-		fields[0], fields[1], fields[2], fields[3], fields[4], fields[5],
-	)
+	body := ctx.T("docker_stats.body", fields[0], fields[1], fields[2], fields[3], fields[4], fields[5])
 
 	return ctx.ReplyPre(body)
 }
@@ -3,6 +3,8 @@ package commands
 import (
 	"sort"
 	"strings"
+
+	"serverbot/internal/i18n"
 )
 
 // NewHelpHandler builds a handler that renders the command catalog.
@@ -13,32 +15,35 @@ func NewHelpHandler(registry *Registry) Handler {
 		owner := registry.List(ScopeOwner)
 
 		var builder strings.Builder
-		builder.WriteString("<b>Comandos disponibles</b>\n\n")
+		builder.WriteString(ctx.T("help.title") + "\n\n")
 
 		if len(public) > 0 {
-			builder.WriteString("<b>Publicos</b>\n")
-			appendCommands(&builder, public)
+			builder.WriteString(ctx.T("help.public") + "\n")
+			appendCommands(&builder, ctx.Lang, public)
 			builder.WriteByte('\n')
 		}
 
 		if len(admin) > 0 {
-			builder.WriteString("<b>Solo administrador</b>\n")
-			appendCommands(&builder, admin)
+			builder.WriteString(ctx.T("help.admin") + "\n")
+			appendCommands(&builder, ctx.Lang, admin)
 			builder.WriteByte('\n')
 		}
 
 		if len(owner) > 0 {
-			builder.WriteString("<b>Solo propietario</b>\n")
-			appendCommands(&builder, owner)
+			builder.WriteString(ctx.T("help.owner") + "\n")
+			appendCommands(&builder, ctx.Lang, owner)
 			builder.WriteByte('\n')
 		}
 
-		builder.WriteString("<i>Desarrollado por DaniMarqz - Go + Telegram API</i>")
+		builder.WriteString(ctx.T("help.footer"))
 		return ctx.ReplyHTML(builder.String(), false)
 	}
 }
 
-func appendCommands(builder *strings.Builder, commands map[string]string) {
+// appendCommands lists commands with their descriptions translated; a
+// description that is not a catalog key, such as that of a custom command, is
+// shown as written.
+func appendCommands(builder *strings.Builder, lang string, commands map[string]string) {
 	if len(commands) == 0 {
 		return
 	}
@@ -53,7 +58,7 @@ func appendCommands(builder *strings.Builder, commands map[string]string) {
 		builder.WriteString("- <b>/")
 		builder.WriteString(name)
 		builder.WriteString("</b> - ")
-		builder.WriteString(commands[name])
+		builder.WriteString(i18n.T(lang, commands[name]))
 		builder.WriteByte('\n')
 	}
 }
@@ -11,7 +11,7 @@ import (
 
 func TestAppendCommandsKeepsSortedOrder(t *testing.T) {
 	builder := &strings.Builder{}
-	appendCommands(builder, map[string]string{
+	appendCommands(builder, "", map[string]string{
 		"zeta":  "last",
 		"alpha": "first",
 		"beta":  "second",
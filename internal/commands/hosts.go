@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+// Host is another machine that commands can target with "@name".
+type Host interface {
+	Name() string
+	// Runner executes commands on the host.
+	Runner() system.Runner
+}
+
+// StatsHost is a Host that also reports metrics snapshots.
+type StatsHost interface {
+	Host
+	// Stats returns the host's latest snapshot, or a new one when fresh.
+	Stats(ctx context.Context, fresh bool) (metrics.Snapshot, error)
+}
+
+// runnerHost is a Host reached only through a Runner, such as SSH.
+type runnerHost struct {
+	name   string
+	runner system.Runner
+}
+
+// NewRunnerHost returns a Host called name whose commands go to runner.
+func NewRunnerHost(name string, runner system.Runner) Host {
+	return runnerHost{name: name, runner: runner}
+}
+
+func (h runnerHost) Name() string          { return h.name }
+func (h runnerHost) Runner() system.Runner { return h.runner }
+
+// SetHosts replaces the hosts that "@name" selectors resolve to.
+func (r *Registry) SetHosts(hosts ...Host) {
+	r.hosts = make(map[string]Host, len(hosts))
+	for _, h := range hosts {
+		r.hosts[strings.ToLower(h.Name())] = h
+	}
+}
+
+// AllowRemote lets the named commands run on a host selected with "@name".
+// Any other command refuses a known host selector instead of running locally.
+func (r *Registry) AllowRemote(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if entry, ok := r.commands[name]; ok {
+			entry.Remote = true
+			r.commands[name] = entry
+		}
+	}
+}
+
+// hostNames returns the configured host names, sorted.
+func (r *Registry) hostNames() []string {
+	names := make([]string, 0, len(r.hosts))
+	for name := range r.hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withHost resolves an "@name" argument before calling the command handler.
+// It runs inside the command's middlewares, so authorization comes first.
+func (r *Registry) withHost(entry registeredCommand) Handler {
+	return func(ctx *Context) error {
+		rest, name, ok := cutHostSelector(ctx.Arguments)
+		if !ok {
+			return entry.Handler(ctx)
+		}
+
+		host, known := r.hosts[name]
+		if !entry.Remote {
+			if !known {
+				// Not a host: leave arguments such as "/dns x @1.1.1.1" alone.
+				return entry.Handler(ctx)
+			}
+			return ctx.Reply(ctx.T("hosts.local_only", ctx.Command))
+		}
+		if !known {
+			if len(r.hosts) == 0 {
+				return ctx.Reply(ctx.T("hosts.none"))
+			}
+			return ctx.Reply(ctx.T("hosts.unknown", name, strings.Join(r.hostNames(), ", ")))
+		}
+
+		ctx.Host = host
+		ctx.Runner = host.Runner()
+		ctx.Arguments = rest
+		return entry.Handler(ctx)
+	}
+}
+
+// cutHostSelector removes the first "@name" word from args.
+func cutHostSelector(args string) (rest, name string, ok bool) {
+	fields := strings.Fields(args)
+	for i, field := range fields {
+		if len(field) > 1 && field[0] == '@' {
+			name = strings.ToLower(field[1:])
+			fields = append(fields[:i], fields[i+1:]...)
+			return strings.Join(fields, " "), name, true
+		}
+	}
+	return args, "", false
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"serverbot/internal/app"
+	"serverbot/internal/system"
+	"serverbot/internal/testutil"
+)
+
+type fakeHost struct {
+	name   string
+	runner system.Runner
+}
+
+func (h fakeHost) Name() string          { return h.name }
+func (h fakeHost) Runner() system.Runner { return h.runner }
+
+func TestRegistryHostSelector(t *testing.T) {
+	local := &fakeRunner{t: t}
+	remote := &fakeRunner{t: t, wantName: "docker", stdout: "web Up 2 hours\n"}
+	reg := NewRegistry(Dependencies{Config: app.Config{OwnerID: 1}, Runner: local})
+	reg.SetHosts(fakeHost{name: "nas", runner: remote})
+
+	var gotArgs string
+	var gotHost Host
+	reg.Handle("docker_logs", "", ScopeOwner, func(ctx *Context) error {
+		gotArgs, gotHost = ctx.Args(), ctx.Host
+		_, _, err := ctx.Runner.Run(ctx.RequestContext, "docker", "logs")
+		return err
+	})
+	reg.Handle("reboot", "", ScopeOwner, func(ctx *Context) error {
+		t.Fatal("reboot ran despite a host selector")
+		return nil
+	})
+	reg.Handle("dns", "", ScopeOwner, func(ctx *Context) error {
+		gotArgs = ctx.Args()
+		return nil
+	})
+	reg.AllowRemote("docker_logs")
+
+	dispatch := func(command, args string) string {
+		bot, client := testutil.NewFakeBot()
+		if err := reg.Dispatch(context.Background(), bot, SyntheticUpdate(1, command, args)); err != nil {
+			t.Fatalf("Dispatch(/%s %s) error = %v", command, args, err)
+		}
+		if reqs := client.Requests(); len(reqs) > 0 {
+			return reqs[0].Values.Get("text")
+		}
+		return ""
+	}
+
+	dispatch("docker_logs", "web @NAS")
+	if gotArgs != "web" || gotHost == nil || gotHost.Name() != "nas" {
+		t.Fatalf("handler saw args %q host %v, want web on nas", gotArgs, gotHost)
+	}
+	if !remote.called || local.called {
+		t.Fatalf("remote called = %v, local called = %v", remote.called, local.called)
+	}
+
+	if reply := dispatch("docker_logs", "web @pi"); !strings.Contains(reply, "Host desconocido: @pi") || !strings.Contains(reply, "nas") {
+		t.Fatalf("unknown host reply = %q", reply)
+	}
+	if reply := dispatch("reboot", "@nas"); !strings.Contains(reply, "solo se ejecuta en el servidor local") {
+		t.Fatalf("reboot @nas reply = %q", reply)
+	}
+
+	dispatch("dns", "example.com MX @1.1.1.1")
+	if gotArgs != "example.com MX @1.1.1.1" {
+		t.Fatalf("dns args = %q, want the resolver selector untouched", gotArgs)
+	}
+}
+
+func TestCutHostSelector(t *testing.T) {
+	rest, name, ok := cutHostSelector("  web   @Nas --tail ")
+	if !ok || name != "nas" || rest != "web --tail" {
+		t.Fatalf("cutHostSelector = %q, %q, %v", rest, name, ok)
+	}
+	if _, _, ok := cutHostSelector("web @"); ok {
+		t.Fatal("a bare @ was taken as a host selector")
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// langAuto clears the choice of a chat, which then follows the Telegram
+// language of each user.
+const langAuto = "auto"
+
+// NewLangHandler builds /lang: without arguments it shows the language of the
+// chat, "/lang <code>" chooses one and "/lang auto" clears the choice.
+func NewLangHandler(store *i18n.Store) Handler {
+	return func(ctx *Context) error {
+		chatID := ctx.Update.Message.Chat.ID
+		arg := strings.ToLower(strings.TrimSpace(ctx.Args()))
+		if arg == "" {
+			return ctx.ReplyHTML(langStatus(ctx, store, chatID), false)
+		}
+
+		if arg == langAuto {
+			if err := store.Set(chatID, ""); err != nil {
+				return ctx.ReplyError(ctx.T("lang.save_failed"), err)
+			}
+			return ctx.Reply(i18n.T(store.Resolve(chatID, senderLanguage(ctx)), "lang.auto"))
+		}
+
+		lang, ok := i18n.Match(arg)
+		if !ok {
+			return ctx.Reply(ctx.T("lang.unknown", arg, strings.Join(i18n.Languages(), ", ")))
+		}
+		if err := store.Set(chatID, lang); err != nil {
+			return ctx.ReplyError(ctx.T("lang.save_failed"), err)
+		}
+		return ctx.Reply(i18n.T(lang, "lang.set", languageLabel(lang)))
+	}
+}
+
+func langStatus(ctx *Context, store *i18n.Store, chatID int64) string {
+	source := ctx.T("lang.source_telegram")
+	if _, ok := store.Get(chatID); ok {
+		source = ctx.T("lang.source_chat")
+	}
+
+	options := make([]string, 0, len(i18n.Languages()))
+	for _, lang := range i18n.Languages() {
+		options = append(options, html.EscapeString(languageLabel(lang)))
+	}
+	return ctx.T("lang.status", html.EscapeString(languageLabel(ctx.Lang)), source, strings.Join(options, ", "))
+}
+
+// languageLabel names lang in itself, e.g. "en (English)".
+func languageLabel(lang string) string {
+	return fmt.Sprintf("%s (%s)", lang, i18n.T(lang, "lang.name"))
+}
+
+func senderLanguage(ctx *Context) string {
+	if from := ctx.Update.Message.From; from != nil {
+		return from.LanguageCode
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"serverbot/internal/i18n"
+	"serverbot/internal/testutil"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestLangChoosesChatLanguage(t *testing.T) {
+	store, err := i18n.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := NewRegistry(Dependencies{Languages: store})
+	reg.Handle("lang", "cmd.lang", ScopePublic, NewLangHandler(store))
+	reg.Handle("secret", "secret", ScopeAdmin, func(ctx *Context) error { return nil }, AdminOnly())
+
+	bot, client := testutil.NewFakeBot()
+	dispatch := func(cmd, args, code string) string {
+		t.Helper()
+		update := SyntheticUpdate(7, cmd, args)
+		if code != "" {
+			update.Message.From = &tgbotapi.User{ID: 7, LanguageCode: code}
+		}
+		before := len(client.Requests())
+		if err := reg.Dispatch(context.Background(), bot, update); err != nil {
+			t.Fatalf("Dispatch(/%s %s) error = %v", cmd, args, err)
+		}
+		reqs := client.Requests()
+		if len(reqs) != before+1 {
+			t.Fatalf("/%s %s sent %d messages", cmd, args, len(reqs)-before)
+		}
+		return reqs[len(reqs)-1].Values.Get("text")
+	}
+
+	if got := dispatch("secret", "", "en-US"); got != "Not authorized." {
+		t.Fatalf("Telegram language not used: %q", got)
+	}
+	if got := dispatch("lang", "xx", ""); !strings.Contains(got, "Idioma desconocido") {
+		t.Fatalf("/lang xx = %q", got)
+	}
+	if got := dispatch("lang", "en", ""); got != "Language set to en (English)." {
+		t.Fatalf("/lang en = %q", got)
+	}
+	if got := dispatch("secret", "", "es"); got != "Not authorized." {
+		t.Fatalf("chat choice not preferred over the Telegram language: %q", got)
+	}
+	if got := dispatch("lang", "", ""); !strings.Contains(got, "chosen for this chat") {
+		t.Fatalf("/lang = %q", got)
+	}
+	if got := dispatch("lang", "auto", "es"); !strings.Contains(got, "idioma de Telegram") {
+		t.Fatalf("/lang auto = %q", got)
+	}
+}
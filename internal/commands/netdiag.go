@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/netdiag"
+	"serverbot/internal/system"
+)
+
+// tracerouteTimeout covers 20 hops with a one second wait each plus overhead.
+const tracerouteTimeout = 45 * time.Second
+
+// Traceroute shows the route to a host.
+// Usage: /traceroute <host>
+func Traceroute(ctx *Context) error {
+	args := ctx.ArgsList()
+	if len(args) != 1 {
+		return ctx.Reply(ctx.T("traceroute.usage"))
+	}
+	target, err := netdiag.ValidateHost(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("netdiag.invalid_target"))
+	}
+
+	sent, err := ctx.ReplyMessage(ctx.T("traceroute.running", target))
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, max(ctx.AppConfig.CommandTimeout, tracerouteTimeout))
+	defer cancel()
+
+	stdout, stderr, err := ctx.Runner.Run(runCtx, "traceroute", "-n", "-q", "1", "-w", "1", "-m", "20", target)
+	hops := netdiag.ParseTraceroute(stdout)
+	if err != nil && len(hops) == 0 {
+		if ctx.Logger != nil {
+			ctx.Logger.Printf("command %s failed: %v: %s", ctx.Command, err, strings.TrimSpace(stderr))
+		}
+		return ctx.EditHTML(sent.MessageID, ctx.T("traceroute.error"))
+	}
+
+	lines := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		if hop.Addr == "" {
+			lines = append(lines, fmt.Sprintf("%d *", hop.TTL))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d %s (%.1f ms)", hop.TTL, hop.Addr, hop.RTT))
+	}
+	if len(lines) == 0 {
+		lines = []string{ctx.T("traceroute.no_hops")}
+	}
+
+	var b strings.Builder
+	b.WriteString(ctx.T("traceroute.title", html.EscapeString(target)) + "\n")
+	metrics.WriteSection(&b, "▫️", ctx.T("traceroute.hops"), lines)
+	return ctx.EditHTML(sent.MessageID, strings.TrimSpace(b.String()))
+}
+
+// DNS resolves a name, optionally against a given server.
+// Usage: /dns <name> [type] [@server]
+func DNS(ctx *Context) error {
+	usage := ctx.T("dns.usage")
+
+	args := ctx.ArgsList()
+	if len(args) == 0 || len(args) > 3 {
+		return ctx.Reply(usage)
+	}
+	name, err := netdiag.ValidateHost(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("dns.invalid_name"))
+	}
+
+	recordType, server := "", ""
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "@") {
+			server, err = dnsServer(strings.TrimPrefix(arg, "@"))
+			if err != nil {
+				return ctx.Reply(ctx.T("dns.invalid_server"))
+			}
+			continue
+		}
+		recordType = strings.ToUpper(arg)
+		if !validDNSType(recordType) {
+			return ctx.Reply(usage)
+		}
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	res, err := netdiag.Lookup(runCtx, name, recordType, server)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		return ctx.ReplyError(ctx.T("dns.error"), err)
+	}
+
+	serverLabel := res.Server
+	if serverLabel == "" {
+		serverLabel = ctx.T("dns.system")
+	}
+	records := res.Records
+	if len(records) == 0 {
+		records = []string{ctx.T("dns.no_records")}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("<b>🔎 DNS %s %s</b>\n", html.EscapeString(res.Type), html.EscapeString(name)))
+	metrics.WriteSection(&b, "▫️", ctx.T("netdiag.response"), records)
+	metrics.WriteSection(&b, "⏱️", ctx.T("dns.query"), []string{
+		ctx.T("dns.query_line", serverLabel, res.Latency.Round(time.Millisecond)),
+	})
+	return ctx.ReplyHTML(strings.TrimSpace(b.String()), false)
+}
+
+// HTTPCheck fetches a URL and reports status, latency, redirects and TLS expiry.
+// Usage: /http_check <url>
+func HTTPCheck(ctx *Context) error {
+	args := ctx.ArgsList()
+	if len(args) != 1 {
+		return ctx.Reply(ctx.T("http_check.usage"))
+	}
+	target, err := netdiag.ValidateURL(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("http_check.invalid_url"))
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	res, err := netdiag.HTTPCheck(runCtx, target, &http.Client{})
+	if err != nil {
+		reason := ctx.T("http_check.connection_error")
+		if netdiag.IsTLSError(err) {
+			reason = ctx.T("http_check.tls_error")
+		}
+		if ctx.Logger != nil {
+			ctx.Logger.Printf("command %s failed: %v", ctx.Command, err)
+		}
+		return ctx.Reply(ctx.T("http_check.down", target, reason, res.Latency.Round(time.Millisecond)))
+	}
+
+	return ctx.ReplyHTML(formatHTTPCheck(ctx.Lang, res, time.Now()), false)
+}
+
+func formatHTTPCheck(lang string, res netdiag.HTTPResult, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("<b>🌐 HTTP %s</b>\n", html.EscapeString(res.URL)))
+
+	metrics.WriteSection(&b, "▫️", i18n.T(lang, "netdiag.response"), []string{
+		i18n.T(lang, "http_check.status", res.Status, http.StatusText(res.Status)),
+		i18n.T(lang, "http_check.latency", res.Latency.Round(time.Millisecond)),
+	})
+
+	if len(res.Redirects) > 0 {
+		chain := make([]string, 0, len(res.Redirects)+1)
+		for _, r := range res.Redirects {
+			chain = append(chain, fmt.Sprintf("%d %s", r.Status, r.URL))
+		}
+		chain = append(chain, fmt.Sprintf("%d %s", res.Status, res.FinalURL))
+		metrics.WriteSection(&b, "↪️", i18n.T(lang, "http_check.redirects"), chain)
+	}
+
+	if res.TLS != nil {
+		metrics.WriteSection(&b, "🔒", "TLS", []string{
+			i18n.T(lang, "http_check.expires", res.TLS.NotAfter.Format("2006-01-02"), i18n.N(lang, "days", res.TLS.DaysLeft(now))),
+			i18n.T(lang, "http_check.issuer", res.TLS.Issuer),
+		})
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// PortCheck tests whether a TCP port accepts connections.
+// Usage: /port_check <host:port>
+func PortCheck(ctx *Context) error {
+	args := ctx.ArgsList()
+	if len(args) != 1 {
+		return ctx.Reply(ctx.T("port_check.usage"))
+	}
+	target, err := netdiag.ValidateHostPort(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("port_check.invalid_target"))
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	elapsed, err := netdiag.PortCheck(runCtx, target)
+	if err != nil {
+		if ctx.Logger != nil {
+			ctx.Logger.Printf("command %s failed: %v", ctx.Command, err)
+		}
+		reason := ctx.T("port_check.closed")
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = ctx.T("port_check.no_response")
+		}
+		return ctx.Reply(fmt.Sprintf("❌ %s %s (%s).", target, reason, elapsed.Round(time.Millisecond)))
+	}
+	return ctx.Reply(ctx.T("port_check.open", target, elapsed.Round(time.Millisecond)))
+}
+
+func dnsServer(raw string) (string, error) {
+	if hostport, err := netdiag.ValidateHostPort(raw); err == nil {
+		return hostport, nil
+	}
+	return netdiag.ValidateHost(raw)
+}
+
+func validDNSType(recordType string) bool {
+	for _, t := range netdiag.DNSTypes {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
@@ -2,34 +2,49 @@ package commands
 
 import (
 	"fmt"
+	"html"
 	"runtime"
 	"strings"
 
+	"serverbot/internal/metrics"
+	"serverbot/internal/netdiag"
 	"serverbot/internal/system"
 )
 
-// Ping runs a connectivity test against the given host.
+// Ping runs a connectivity test against the given host and summarizes packet
+// loss and round-trip times when the output can be parsed.
 func Ping(ctx *Context) error {
-	target := ctx.Args()
+	target := strings.TrimSpace(ctx.Args())
 	if target == "" {
 		target = "8.8.8.8"
 	}
+	target, err := netdiag.ValidateHost(target)
+	if err != nil {
+		return ctx.Reply(ctx.T("netdiag.invalid_target"))
+	}
 
 	command, args, ok := pingCommandForOS(target)
 	if !ok {
-		return ctx.Reply("El comando /ping no está disponible en este sistema operativo.")
+		return ctx.Reply(ctx.T("ping.unsupported"))
 	}
 
 	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
 	defer cancel()
 
 	stdout, stderr, err := ctx.Runner.Run(runCtx, command, args...)
+	// ping exits non-zero when every packet is lost; the summary still applies.
+	if summary, ok := netdiag.ParsePing(stdout); ok {
+		var b strings.Builder
+		b.WriteString(ctx.T("ping.title", html.EscapeString(target)) + "\n")
+		metrics.WriteSection(&b, "▫️", ctx.T("ping.result"), summary.Lines(ctx.Lang))
+		return ctx.ReplyHTML(strings.TrimSpace(b.String()), false)
+	}
 	if err != nil {
-		return ctx.ReplyError("No se pudo ejecutar ping.", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+		return ctx.ReplyError(ctx.T("ping.error"), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
 	}
 
 	if strings.TrimSpace(stdout) == "" {
-		return ctx.Reply("No se obtuvo respuesta de ping.")
+		return ctx.Reply(ctx.T("ping.empty"))
 	}
 
 	return ctx.ReplyPre(stdout)
@@ -171,3 +171,50 @@ func TestPingRunnerError(t *testing.T) {
 func testLogger() *log.Logger {
 	return log.New(&bytes.Buffer{}, "", 0)
 }
+
+func TestPingSummarizesStatistics(t *testing.T) {
+	command, args, ok := pingCommandForOS("1.1.1.1")
+	if !ok {
+		t.Skip("ping not supported on this OS")
+	}
+
+	runner := &fakeRunner{
+		t:        t,
+		wantName: command,
+		wantArgs: args,
+		stdout:   "4 packets transmitted, 0 received, 100% packet loss, time 3060ms\n",
+		err:      errors.New("exit status 1"),
+	}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Runner = runner
+	ctx.Arguments = "1.1.1.1"
+
+	if err := Ping(ctx); err != nil {
+		t.Fatalf("Ping() returned error: %v", err)
+	}
+	got := client.Requests()[0].Values.Get("text")
+	if !strings.Contains(got, "Ping a 1.1.1.1") || !strings.Contains(got, "Enviados 4 - Recibidos 0 - Perdida 100%") {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestPingRejectsOptionInjection(t *testing.T) {
+	runner := &fakeRunner{t: t}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Runner = runner
+	ctx.Arguments = "-f 8.8.8.8"
+
+	if err := Ping(ctx); err != nil {
+		t.Fatalf("Ping() returned error: %v", err)
+	}
+	if runner.called {
+		t.Fatalf("ping ran with an injected option")
+	}
+	if got := client.Requests()[0].Values.Get("text"); got != "Destino invalido." {
+		t.Fatalf("reply = %q", got)
+	}
+}
@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"serverbot/internal/audit"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/procs"
+	"serverbot/internal/system"
+)
+
+// describeProcess is replaced in tests to avoid inspecting the real host.
+var describeProcess = procs.Describe
+
+// killSignals are the signals /kill accepts, by name without the SIG prefix.
+var killSignals = map[string]bool{
+	"TERM": true, "KILL": true, "INT": true, "HUP": true, "QUIT": true,
+	"USR1": true, "USR2": true, "STOP": true, "CONT": true,
+}
+
+// Proc shows the details and children tree of a process.
+// Usage: /proc <pid>
+func Proc(ctx *Context) error {
+	args := ctx.ArgsList()
+	if len(args) != 1 {
+		return ctx.Reply(ctx.T("proc.usage"))
+	}
+	pid, ok := parsePID(args[0])
+	if !ok {
+		return ctx.Reply(ctx.T("proc.invalid_pid"))
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	detail, err := describeProcess(runCtx, pid)
+	if errors.Is(err, procs.ErrNotFound) {
+		return ctx.Reply(ctx.T("proc.not_found", pid))
+	}
+	if err != nil {
+		return ctx.ReplyError(ctx.T("proc.error"), err)
+	}
+
+	return ctx.ReplyHTML(formatProc(ctx.Lang, detail, containerNames(runCtx, ctx, []procs.Info{detail.Info})), false)
+}
+
+// Kill sends a signal to a process after confirmation.
+// Usage: /kill <pid> [signal] confirmar
+func Kill(ctx *Context) error {
+	args, confirmed := splitConfirm(ctx.ArgsList())
+	if len(args) < 1 || len(args) > 2 {
+		return ctx.Reply(ctx.T("kill.usage"))
+	}
+	pid, ok := parsePID(args[0])
+	if !ok {
+		return ctx.Reply(ctx.T("proc.invalid_pid"))
+	}
+	signal := "TERM"
+	if len(args) == 2 {
+		signal = strings.TrimPrefix(strings.ToUpper(args[1]), "SIG")
+		if !killSignals[signal] {
+			return ctx.Reply(ctx.T("kill.bad_signal"))
+		}
+	}
+
+	return controlProcess(ctx, pid, confirmed, processAction{
+		name:    "kill",
+		detail:  "SIG" + signal,
+		prompt:  ctx.T("kill.prompt", signal),
+		confirm: fmt.Sprintf("/kill %d %s %s", pid, signal, ctx.T("confirm.word")),
+		done:    ctx.T("kill.done", signal),
+		command: []string{"kill", "-s", signal, strconv.Itoa(int(pid))},
+	})
+}
+
+// Renice changes the scheduling priority of a process after confirmation.
+// Usage: /renice <pid> <n> confirmar
+func Renice(ctx *Context) error {
+	args, confirmed := splitConfirm(ctx.ArgsList())
+	if len(args) != 2 {
+		return ctx.Reply(ctx.T("renice.usage"))
+	}
+	pid, ok := parsePID(args[0])
+	if !ok {
+		return ctx.Reply(ctx.T("proc.invalid_pid"))
+	}
+	nice, err := strconv.Atoi(args[1])
+	if err != nil || nice < -20 || nice > 19 {
+		return ctx.Reply(ctx.T("renice.range"))
+	}
+
+	return controlProcess(ctx, pid, confirmed, processAction{
+		name:    "renice",
+		detail:  fmt.Sprintf("nice=%d", nice),
+		prompt:  ctx.T("renice.prompt", nice),
+		confirm: fmt.Sprintf("/renice %d %d %s", pid, nice, ctx.T("confirm.word")),
+		done:    ctx.T("renice.done", nice),
+		command: []string{"renice", "-n", strconv.Itoa(nice), "-p", strconv.Itoa(int(pid))},
+	})
+}
+
+type processAction struct {
+	name    string
+	detail  string
+	prompt  string
+	confirm string
+	done    string
+	command []string
+}
+
+// controlProcess applies the shared checks of /kill and /renice: the process
+// must exist, must not be protected and the request must be confirmed.
+func controlProcess(ctx *Context, pid int32, confirmed bool, action processAction) error {
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	detail, err := describeProcess(runCtx, pid)
+	if errors.Is(err, procs.ErrNotFound) {
+		return ctx.Reply(ctx.T("proc.not_found", pid))
+	}
+	if err != nil {
+		return ctx.ReplyError(ctx.T("proc.error"), err)
+	}
+
+	target := fmt.Sprintf("%d (%s)", pid, detail.Name)
+	entry := audit.Entry{Action: action.name, Target: target, Detail: action.detail}
+
+	if procs.Protected(pid, detail.Name, ctx.AppConfig.ProtectedProcesses) {
+		entry.Outcome = audit.OutcomeDenied
+		ctx.Audit(entry)
+		return ctx.Reply(ctx.T("proc.protected", target))
+	}
+
+	if !confirmed {
+		return ctx.Reply(ctx.T("proc.confirm", action.prompt, target, action.confirm))
+	}
+
+	args := append([]string{}, action.command...)
+	_, stderr, err := ctx.Runner.Run(runCtx, "sudo", args...)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+		entry.Outcome, entry.Error = audit.OutcomeFailed, err.Error()
+		ctx.Audit(entry)
+		return ctx.ReplyError(ctx.T("proc.action_error", action.name, target), err)
+	}
+
+	entry.Outcome = audit.OutcomeOK
+	ctx.Audit(entry)
+	return ctx.Reply(ctx.T("proc.action_done", action.done, target))
+}
+
+func formatProc(lang string, d procs.Detail, containers map[string]string) string {
+	var buf strings.Builder
+	buf.WriteString(i18n.T(lang, "proc.title", d.PID) + "\n")
+
+	general := []string{
+		i18n.T(lang, "proc.name", valueOr(d.Name, "?")),
+		i18n.T(lang, "proc.ppid_user", d.PPID, valueOr(d.User, "?")),
+		i18n.T(lang, "proc.state_nice", valueOr(d.Status, "?"), d.Nice),
+	}
+	if !d.Started.IsZero() {
+		general = append(general, i18n.T(lang, "proc.started", d.Started.Format("2006-01-02 15:04:05")))
+	}
+	metrics.WriteSection(&buf, "⚙️", i18n.T(lang, "proc.general"), general)
+
+	openFiles := "?"
+	if d.OpenFiles >= 0 {
+		openFiles = strconv.Itoa(int(d.OpenFiles))
+	}
+	metrics.WriteSection(&buf, "🧮", i18n.T(lang, "proc.resources"), []string{
+		fmt.Sprintf("RAM: %s (%.1f%%)", metrics.HumanBytes(d.RSS), d.MemPercent),
+		i18n.T(lang, "proc.threads_files", d.Threads, openFiles),
+	})
+
+	if d.ContainerID != "" {
+		container := containers[d.ContainerID]
+		if container == "" {
+			container = d.ContainerID[:12]
+		}
+		metrics.WriteSection(&buf, "🐳", i18n.T(lang, "proc.container"), []string{container})
+	}
+
+	if cmdline := truncateRunes(d.Cmdline, 300); cmdline != "" {
+		metrics.WriteSection(&buf, "💬", i18n.T(lang, "proc.command"), []string{cmdline})
+	}
+
+	if len(d.Children) > 0 {
+		tree := make([]string, 0, len(d.Children)+1)
+		for _, child := range d.Children {
+			tree = append(tree, fmt.Sprintf("%s%d %s", strings.Repeat("  ", child.Depth), child.PID, child.Name))
+		}
+		if d.Truncated {
+			tree = append(tree, "...")
+		}
+		metrics.WriteSection(&buf, "🌳", i18n.T(lang, "proc.children"), tree)
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+func parsePID(raw string) (int32, bool) {
+	pid, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return int32(pid), true
+}
+
+// splitConfirm strips a trailing confirmation word from args.
+func splitConfirm(args []string) ([]string, bool) {
+	if n := len(args); n > 0 && isConfirm(args[n-1]) {
+		return args[:n-1], true
+	}
+	return args, false
+}
+
+// isConfirm reports whether word confirms a destructive command. The word of
+// every language is accepted, whatever the language of the chat.
+func isConfirm(word string) bool {
+	for _, lang := range i18n.Languages() {
+		if strings.EqualFold(word, i18n.T(lang, "confirm.word")) {
+			return true
+		}
+	}
+	return false
+}
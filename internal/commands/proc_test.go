@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/audit"
+	"serverbot/internal/procs"
+	"serverbot/internal/testutil"
+)
+
+func stubDescribe(t *testing.T, detail procs.Detail) {
+	t.Helper()
+	old := describeProcess
+	describeProcess = func(ctx context.Context, pid int32) (procs.Detail, error) {
+		if pid != detail.PID {
+			return procs.Detail{}, procs.ErrNotFound
+		}
+		return detail, nil
+	}
+	t.Cleanup(func() { describeProcess = old })
+}
+
+func TestKillAsksForConfirmation(t *testing.T) {
+	stubDescribe(t, procs.Detail{Info: procs.Info{PID: 4321, Name: "stress"}})
+	runner := &fakeRunner{t: t}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Runner = runner
+	ctx.Arguments = "4321 kill"
+
+	if err := Kill(ctx); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if runner.called {
+		t.Fatalf("kill ran without confirmation")
+	}
+	if got := client.Requests()[0].Values.Get("text"); !strings.Contains(got, "/kill 4321 KILL confirmar") {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestKillConfirmedRunsAndAudits(t *testing.T) {
+	stubDescribe(t, procs.Detail{Info: procs.Info{PID: 4321, Name: "stress"}})
+	runner := &fakeRunner{t: t, wantName: "sudo", wantArgs: []string{"kill", "-s", "KILL", "4321"}}
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.AppConfig.CommandTimeout = time.Second
+	ctx.AppConfig.AuditLogFile = auditFile
+	ctx.Runner = runner
+	ctx.Arguments = "4321 SIGKILL confirmar"
+
+	if err := Kill(ctx); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if !runner.called {
+		t.Fatalf("kill was not executed")
+	}
+	if got := client.Requests()[0].Values.Get("text"); got != "SIGKILL enviada al proceso 4321 (stress)." {
+		t.Fatalf("reply = %q", got)
+	}
+
+	data, err := os.ReadFile(auditFile)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	var entry audit.Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("decode audit entry: %v", err)
+	}
+	if entry.Action != "kill" || entry.Outcome != audit.OutcomeOK || entry.UserID != 321 || entry.Detail != "SIGKILL" {
+		t.Fatalf("audit entry = %+v", entry)
+	}
+}
+
+func TestKillRefusesProtectedProcess(t *testing.T) {
+	stubDescribe(t, procs.Detail{Info: procs.Info{PID: 800, Name: "sshd"}})
+	runner := &fakeRunner{t: t}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Runner = runner
+	ctx.Arguments = "800 confirmar"
+
+	if err := Kill(ctx); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if runner.called {
+		t.Fatalf("protected process was signalled")
+	}
+	if got := client.Requests()[0].Values.Get("text"); !strings.Contains(got, "protegido") {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestReniceValidatesPriority(t *testing.T) {
+	stubDescribe(t, procs.Detail{Info: procs.Info{PID: 10, Name: "job"}})
+	runner := &fakeRunner{t: t}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Runner = runner
+	ctx.Arguments = "10 25 confirmar"
+
+	if err := Renice(ctx); err != nil {
+		t.Fatalf("Renice() error = %v", err)
+	}
+	if runner.called {
+		t.Fatalf("renice ran with an invalid priority")
+	}
+	if got := client.Requests()[0].Values.Get("text"); !strings.Contains(got, "-20 y 19") {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestProcRendersTree(t *testing.T) {
+	stubDescribe(t, procs.Detail{
+		Info:      procs.Info{PID: 50, PPID: 1, Name: "nginx", User: "root", Cmdline: "nginx: master"},
+		Status:    "sleep",
+		Threads:   2,
+		OpenFiles: 12,
+		Children: []procs.TreeNode{
+			{PID: 51, Name: "nginx", Depth: 0},
+			{PID: 60, Name: "helper", Depth: 1},
+		},
+	})
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Arguments = "50"
+
+	if err := Proc(ctx); err != nil {
+		t.Fatalf("Proc() error = %v", err)
+	}
+	text := client.Requests()[0].Values.Get("text")
+	for _, needle := range []string{"Proceso 50", "Ficheros abiertos: 12", "• 51 nginx", "•   60 helper"} {
+		if !strings.Contains(text, needle) {
+			t.Fatalf("reply missing %q:\n%s", needle, text)
+		}
+	}
+}
@@ -10,11 +10,11 @@ import (
 // Reboot triggers a server reboot.
 func Reboot(ctx *Context) error {
 	args := ctx.ArgsList()
-	if len(args) == 0 || strings.ToLower(args[0]) != "confirmar" {
-		return ctx.Reply("[ALERTA] Este comando reiniciara el servidor. Ejecuta /reboot confirmar para continuar.")
+	if len(args) == 0 || !isConfirm(args[0]) {
+		return ctx.Reply(ctx.T("reboot.confirm", ctx.T("confirm.word")))
 	}
 
-	if err := ctx.Reply("Reiniciando servidor..."); err != nil {
+	if err := ctx.Reply(ctx.T("reboot.running")); err != nil {
 		return err
 	}
 
@@ -23,7 +23,7 @@ func Reboot(ctx *Context) error {
 
 	_, stderr, err := ctx.Runner.Run(runCtx, "sudo", "reboot")
 	if err != nil {
-		return ctx.ReplyError("No se pudo iniciar el reinicio.", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+		return ctx.ReplyError(ctx.T("reboot.error"), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
 	}
 
 	return nil
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"time"
 
 	"serverbot/internal/app"
+	"serverbot/internal/i18n"
 	"serverbot/internal/system"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -26,6 +29,8 @@ type registeredCommand struct {
 	Middlewares  []Middleware
 	Scope        CommandScope
 	HideFromHelp bool
+	// Remote commands accept an "@host" selector (see AllowRemote).
+	Remote bool
 }
 
 // CommandScope describes the visibility of a command.
@@ -42,13 +47,20 @@ type Dependencies struct {
 	Config app.Config
 	Runner system.Runner
 	Logger *log.Logger
+	// Languages holds the language chosen with /lang in each chat; nil
+	// follows the Telegram language of each user.
+	Languages *i18n.Store
 }
 
 type Registry struct {
-	deps       Dependencies
+	deps Dependencies
+	// mu guards commands, which can change at runtime when custom commands
+	// are reloaded.
+	mu         sync.RWMutex
 	commands   map[string]registeredCommand
 	notFound   Handler
 	middleware []Middleware
+	hosts      map[string]Host
 }
 
 // NewRegistry creates a registry with the supplied dependencies.
@@ -59,6 +71,14 @@ func NewRegistry(deps Dependencies) *Registry {
 	}
 }
 
+// SetConfig replaces the configuration handed to subsequent commands, so
+// reloaded roles apply without restarting.
+func (r *Registry) SetConfig(cfg app.Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deps.Config = cfg
+}
+
 // Use appends global middleware applied to every handler.
 func (r *Registry) Use(mw ...Middleware) {
 	r.middleware = append(r.middleware, mw...)
@@ -71,6 +91,8 @@ func (r *Registry) Handle(name string, description string, scope CommandScope, h
 		return
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.commands[name] = registeredCommand{
 		Handler:     handler,
 		Description: description,
@@ -86,6 +108,8 @@ func (r *Registry) HandleHidden(name string, handler Handler, middlewares ...Mid
 		return
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.commands[name] = registeredCommand{
 		Handler:      handler,
 		Middlewares:  middlewares,
@@ -93,6 +117,23 @@ func (r *Registry) HandleHidden(name string, handler Handler, middlewares ...Mid
 	}
 }
 
+// Remove unregisters the named commands; unknown names are ignored.
+func (r *Registry) Remove(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		delete(r.commands, strings.TrimSpace(strings.ToLower(name)))
+	}
+}
+
+// Has reports whether a command with the given name is registered.
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.commands[strings.TrimSpace(strings.ToLower(name))]
+	return ok
+}
+
 // SetNotFound sets the fallback handler for unknown commands.
 func (r *Registry) SetNotFound(handler Handler) {
 	r.notFound = handler
@@ -108,7 +149,9 @@ func (r *Registry) Dispatch(ctx context.Context, bot *tgbotapi.BotAPI, update tg
 	}
 
 	name := strings.ToLower(update.Message.Command())
+	r.mu.RLock()
 	entry, ok := r.commands[name]
+	r.mu.RUnlock()
 	if !ok {
 		if r.notFound != nil {
 			return r.notFound(r.buildContext(ctx, bot, update, name, update.Message.CommandArguments()))
@@ -116,7 +159,7 @@ func (r *Registry) Dispatch(ctx context.Context, bot *tgbotapi.BotAPI, update tg
 		return fmt.Errorf("command %q not found", name)
 	}
 
-	handler := entry.Handler
+	handler := r.withHost(entry)
 	for i := len(entry.Middlewares) - 1; i >= 0; i-- {
 		handler = entry.Middlewares[i](handler)
 	}
@@ -129,6 +172,8 @@ func (r *Registry) Dispatch(ctx context.Context, bot *tgbotapi.BotAPI, update tg
 
 // List returns visible commands for the given scope.
 func (r *Registry) List(scope CommandScope) map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	result := make(map[string]string)
 	for name, cmd := range r.commands {
 		if cmd.HideFromHelp {
@@ -144,9 +189,37 @@ func (r *Registry) List(scope CommandScope) map[string]string {
 	return result
 }
 
+// SyntheticUpdate builds an update that looks like the given command being
+// typed in chatID, so it can be routed through Dispatch by internal callers.
+func SyntheticUpdate(chatID int64, command, args string) tgbotapi.Update {
+	text := "/" + command
+	if args = strings.TrimSpace(args); args != "" {
+		text += " " + args
+	}
+
+	return tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			Date: int(time.Now().Unix()),
+			Chat: &tgbotapi.Chat{ID: chatID},
+			Text: text,
+			Entities: []tgbotapi.MessageEntity{
+				{Type: "bot_command", Offset: 0, Length: len(command) + 1},
+			},
+		},
+	}
+}
+
 func (r *Registry) buildContext(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update, command, args string) *Context {
+	r.mu.RLock()
+	cfg := r.deps.Config
+	r.mu.RUnlock()
+	var code string
+	if update.Message.From != nil {
+		code = update.Message.From.LanguageCode
+	}
 	return &Context{
-		AppConfig:      r.deps.Config,
+		AppConfig:      cfg,
+		Lang:           r.deps.Languages.Resolve(update.Message.Chat.ID, code),
 		Runner:         r.deps.Runner,
 		Logger:         r.deps.Logger,
 		RequestContext: ctx,
@@ -162,7 +235,7 @@ func AdminOnly() Middleware {
 	return func(next Handler) Handler {
 		return func(ctx *Context) error {
 			if !ctx.IsAdmin() {
-				return ctx.Reply("No autorizado.")
+				return ctx.Reply(ctx.T("auth.denied"))
 			}
 			return next(ctx)
 		}
@@ -174,7 +247,7 @@ func OwnerOnly() Middleware {
 	return func(next Handler) Handler {
 		return func(ctx *Context) error {
 			if !ctx.IsOwner() {
-				return ctx.Reply("No autorizado.")
+				return ctx.Reply(ctx.T("auth.denied"))
 			}
 			return next(ctx)
 		}
@@ -11,7 +11,7 @@ import (
 func ServiceStatus(ctx *Context) error {
 	args := ctx.ArgsList()
 	if len(args) == 0 {
-		return ctx.Reply("Uso: /service_status <servicio>")
+		return ctx.Reply(ctx.T("service_status.usage"))
 	}
 
 	service := args[0]
@@ -25,12 +25,12 @@ func ServiceStatus(ctx *Context) error {
 		if msg == "" {
 			msg = strings.TrimSpace(stdout)
 		}
-		return ctx.ReplyError("No se pudo consultar el estado del servicio.", fmt.Errorf("%w: %s", err, msg))
+		return ctx.ReplyError(ctx.T("service_status.error"), fmt.Errorf("%w: %s", err, msg))
 	}
 
 	body := strings.TrimSpace(stdout)
 	if body == "" {
-		return ctx.Reply("Sin informacion disponible para el servicio.")
+		return ctx.Reply(ctx.T("service_status.empty"))
 	}
 
 	return ctx.ReplyPre(body)
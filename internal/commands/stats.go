@@ -3,39 +3,159 @@ package commands
 import (
 	"context"
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
+	"serverbot/internal/i18n"
 	"serverbot/internal/metrics"
 )
 
-// NewStatsHandler builds the handler that gathers and renders server metrics.
-func NewStatsHandler(collector *metrics.Collector) Handler {
+const (
+	// statsInlineLimit is the longest JSON body sent inline; longer
+	// documents are attached as a file.
+	statsInlineLimit = 3500
+)
+
+// statsRequest is a parsed /stats invocation.
+type statsRequest struct {
+	sections metrics.Section
+	format   string // html, json or compact
+	fresh    bool
+}
+
+func parseStatsArgs(args []string) (statsRequest, bool) {
+	req := statsRequest{format: "html"}
+	for _, arg := range args {
+		arg = strings.ToLower(arg)
+		switch arg {
+		case "fresh":
+			req.fresh = true
+		case "--json", "--compact":
+			if req.format != "html" {
+				return statsRequest{}, false
+			}
+			req.format = strings.TrimPrefix(arg, "--")
+		default:
+			section, ok := metrics.ParseSection(arg)
+			if !ok {
+				return statsRequest{}, false
+			}
+			req.sections |= section
+		}
+	}
+	if req.sections == 0 {
+		req.sections = metrics.AllSections
+	}
+	return req, true
+}
+
+func (r statsRequest) renderer(lang string) metrics.Renderer {
+	switch r.format {
+	case "json":
+		return metrics.JSONRenderer{Indent: true}
+	case "compact":
+		return metrics.CompactRenderer{}
+	default:
+		return metrics.HTMLRenderer{Lang: lang}
+	}
+}
+
+// NewStatsHandler builds the handler that renders server metrics. It answers
+// with the sampler's latest snapshot; "/stats fresh" takes a new sample.
+// "/stats @host" asks that host's agent instead.
+func NewStatsHandler(sampler *metrics.Sampler) Handler {
 	return func(ctx *Context) error {
-		sent, err := ctx.ReplyMessage("Recopilando metricas...")
+		req, ok := parseStatsArgs(ctx.ArgsList())
+		if !ok {
+			return ctx.Reply(ctx.T("stats.usage"))
+		}
+		if ctx.Host != nil {
+			return remoteStats(ctx, req)
+		}
+
+		if snap, ok := sampler.Latest(); ok && !req.fresh {
+			return sendStats(ctx, 0, req, snap, true)
+		}
+
+		sent, err := ctx.ReplyMessage(ctx.T("stats.collecting"))
 		if err != nil {
 			return err
 		}
 
-		timeout := ctx.AppConfig.CommandTimeout + 2*collector.SampleInterval()
+		timeout := ctx.AppConfig.CommandTimeout + 2*sampler.Collector.SampleInterval()
 		gatherCtx, cancel := context.WithTimeout(ctx.RequestContext, timeout)
 		defer cancel()
 
-		stats, collectErr := collector.Collect(gatherCtx)
+		snap, collectErr := sampler.Sample(gatherCtx)
 		if collectErr != nil {
-			if err := ctx.EditHTML(sent.MessageID, fmt.Sprintf("<b>Error al obtener metricas:</b>\n%s", collectErr.Error())); err != nil {
-				return err
-			}
-			return nil
+			return ctx.EditHTML(sent.MessageID, ctx.T("stats.error", html.EscapeString(collectErr.Error())))
 		}
+		return sendStats(ctx, sent.MessageID, req, snap, false)
+	}
+}
 
-		if gatherCtx.Err() == context.DeadlineExceeded {
-			stats.Warnings = append(stats.Warnings, "La recopilacion excedio el tiempo limite configurado.")
-		}
+// remoteStats answers /stats for a host selected with "@name".
+func remoteStats(ctx *Context, req statsRequest) error {
+	host, ok := ctx.Host.(StatsHost)
+	if !ok {
+		return ctx.Reply(ctx.T("stats.no_agent", ctx.Host.Name()))
+	}
 
-		report := metrics.FormatHTML(stats)
-		if err := ctx.EditHTML(sent.MessageID, report); err != nil {
-			return err
+	sent, err := ctx.ReplyMessage(ctx.T("stats.querying", host.Name()))
+	if err != nil {
+		return err
+	}
+
+	gatherCtx, cancel := context.WithTimeout(ctx.RequestContext, 2*ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	snap, err := host.Stats(gatherCtx, req.fresh)
+	if err != nil {
+		return ctx.EditHTML(sent.MessageID, ctx.T("stats.host_error",
+			html.EscapeString(host.Name()), html.EscapeString(err.Error())))
+	}
+	return sendStats(ctx, sent.MessageID, req, snap, !req.fresh)
+}
+
+// sendStats renders snap and delivers it, editing placeholder when non-zero.
+// cached snapshots rendered as HTML note their age.
+func sendStats(ctx *Context, placeholder int, req statsRequest, snap metrics.Snapshot, cached bool) error {
+	body, err := req.renderer(ctx.Lang).Render(snap, req.sections)
+	if err != nil {
+		return ctx.ReplyError(ctx.T("stats.format_error"), err)
+	}
+
+	var htmlBody string
+	switch req.format {
+	case "json":
+		if len(body) > statsInlineLimit {
+			if placeholder != 0 {
+				if err := ctx.EditHTML(placeholder, ctx.T("stats.attached")); err != nil {
+					return err
+				}
+			}
+			name := fmt.Sprintf("stats-%s.json", snap.At.Format("20060102-150405"))
+			return ctx.ReplyDocument(name, []byte(body+"\n"), ctx.T("stats.json_caption"))
 		}
+		htmlBody = "<pre>" + html.EscapeString(body) + "</pre>"
+	case "compact":
+		htmlBody = html.EscapeString(body)
+	default:
+		htmlBody = body
+		if cached {
+			htmlBody += snapshotFooter(ctx.Lang, snap, time.Now())
+		}
+	}
 
-		return nil
+	if placeholder != 0 {
+		return ctx.EditHTML(placeholder, htmlBody)
 	}
+	return ctx.ReplyHTML(htmlBody, false)
+}
+
+// snapshotFooter tells the user how old a cached snapshot is.
+func snapshotFooter(lang string, snap metrics.Snapshot, now time.Time) string {
+	age := now.Sub(snap.At).Round(time.Second)
+	return "\n\n" + i18n.T(lang, "stats.footer", snap.At.Format("15:04:05"), age)
 }
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+
+	"serverbot/internal/metrics"
+)
+
+func TestParseStatsArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want statsRequest
+		ok   bool
+	}{
+		{nil, statsRequest{sections: metrics.AllSections, format: "html"}, true},
+		{[]string{"cpu", "MEM"}, statsRequest{sections: metrics.SectionCPU | metrics.SectionMemory, format: "html"}, true},
+		{[]string{"gpu", "--json"}, statsRequest{sections: metrics.SectionGPU, format: "json"}, true},
+		{[]string{"--compact", "fresh"}, statsRequest{sections: metrics.AllSections, format: "compact", fresh: true}, true},
+		{[]string{"--json", "--compact"}, statsRequest{}, false},
+		{[]string{"temps"}, statsRequest{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseStatsArgs(tt.args)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseStatsArgs(%q) = %+v, %v, want %+v, %v", tt.args, got, ok, tt.want, tt.ok)
+		}
+	}
+}
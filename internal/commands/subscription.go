@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"serverbot/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	logSubscriptionPollInterval = 10 * time.Second
+	// subscriptionMessageLimit keeps each message under Telegram's 4096
+	// character limit once escaped; larger batches are split.
+	subscriptionMessageLimit = 3500
+)
+
+// LineSource returns the log lines produced since its previous call. The first
+// call returns the recent history.
+type LineSource func(ctx context.Context) ([]string, error)
+
+// Subscribe polls source every logSubscriptionPollInterval for duration and
+// forwards new lines to the chat. It returns once the subscription is started.
+func (c *Context) Subscribe(label string, duration time.Duration, source LineSource) error {
+	chatID := c.Update.Message.Chat.ID
+	bot := c.Bot
+	logger := c.Logger
+	lang := c.Lang
+
+	subscriptionCtx, cancel := context.WithTimeout(c.RequestContext, duration)
+
+	go func() {
+		defer cancel()
+		streamErr := StreamLines(subscriptionCtx, bot, chatID, lang, label, source)
+		if streamErr != nil {
+			if logger != nil {
+				logger.Printf("logs subscription error: %v", streamErr)
+			}
+			errNotify := notifyText(bot, chatID, i18n.T(lang, "subscription.cancelled", streamErr))
+			if errNotify != nil && logger != nil {
+				logger.Printf("failed to send error notification: %v", errNotify)
+			}
+		}
+	}()
+
+	return c.Reply(c.T("subscription.started", label, duration))
+}
+
+// StreamLines sends the initial lines of source and then every new batch
+// until ctx ends, with headers in lang.
+func StreamLines(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, lang, label string, source LineSource) error {
+	initial, err := source(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(initial) == 0 {
+		if err := notifyInfo(bot, chatID, i18n.T(lang, "subscription.empty", label)); err != nil {
+			return err
+		}
+	} else {
+		if err := sendLines(bot, chatID, i18n.T(lang, "subscription.initial", label), initial); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(logSubscriptionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return notifyInfo(bot, chatID, i18n.T(lang, "subscription.ended", label))
+		case <-ticker.C:
+			lines, err := source(ctx)
+			if err != nil {
+				return err
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			if err := sendLines(bot, chatID, i18n.T(lang, "subscription.update", label), lines); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendLines sends lines under header, split over as many messages as
+// subscriptionMessageLimit needs. A line too long for a message is cut.
+func sendLines(bot *tgbotapi.BotAPI, chatID int64, header string, lines []string) error {
+	limit := subscriptionMessageLimit - len(html.EscapeString(header))
+	var chunk []string
+	size := 0
+	for _, line := range lines {
+		n := len(html.EscapeString(line)) + 1
+		if n > limit {
+			line = cutEscaped(line, limit-1)
+			n = limit
+		}
+		if size+n > limit && len(chunk) > 0 {
+			if err := notifyPre(bot, chatID, header+"\n"+strings.Join(chunk, "\n")); err != nil {
+				return err
+			}
+			chunk, size = nil, 0
+		}
+		chunk = append(chunk, line)
+		size += n
+	}
+	return notifyPre(bot, chatID, header+"\n"+strings.Join(chunk, "\n"))
+}
+
+// cutEscaped shortens s so that its HTML-escaped form, ellipsis included,
+// takes at most max bytes.
+func cutEscaped(s string, max int) string {
+	size := len("…")
+	for i, r := range s {
+		size += len(html.EscapeString(string(r)))
+		if size > max {
+			return s[:i] + "…"
+		}
+	}
+	return s
+}
+
+func notifyInfo(bot *tgbotapi.BotAPI, chatID int64, message string) error {
+	return notifyText(bot, chatID, message)
+}
+
+func notifyText(bot *tgbotapi.BotAPI, chatID int64, message string) error {
+	if bot == nil || message == "" {
+		return nil
+	}
+	msg := tgbotapi.NewMessage(chatID, message)
+	_, err := bot.Send(msg)
+	return err
+}
+
+func notifyPre(bot *tgbotapi.BotAPI, chatID int64, content string) error {
+	if bot == nil || content == "" {
+		return nil
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("<pre>%s</pre>", html.EscapeString(strings.TrimSpace(content))))
+	msg.ParseMode = "HTML"
+	_, err := bot.Send(msg)
+	return err
+}
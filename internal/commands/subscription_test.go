@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/testutil"
+)
+
+func TestDockerLogSourceReturnsOnlyNewLines(t *testing.T) {
+	runner := &fakeRunner{t: t, wantName: "docker", stdout: "a\nb\n"}
+	source := dockerLogSource(runner, "web", time.Second)
+
+	first, err := source(context.Background())
+	if err != nil || strings.Join(first, ",") != "a,b" {
+		t.Fatalf("first poll = %v, %v", first, err)
+	}
+
+	runner.stdout = "a\nb\nc\n"
+	second, err := source(context.Background())
+	if err != nil || strings.Join(second, ",") != "c" {
+		t.Fatalf("second poll = %v, %v", second, err)
+	}
+}
+
+func TestStreamLinesSendsInitialAndEnd(t *testing.T) {
+	bot, client := testutil.NewFakeBot()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	source := func(ctx context.Context) ([]string, error) { return []string{"hola"}, nil }
+	if err := StreamLines(ctx, bot, 1, "", "nginx.service", source); err != nil {
+		t.Fatalf("StreamLines() error = %v", err)
+	}
+
+	reqs := client.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %d, want 2", len(reqs))
+	}
+	if got := reqs[0].Values.Get("text"); got != "<pre>Logs iniciales de nginx.service:\nhola</pre>" {
+		t.Fatalf("initial = %q", got)
+	}
+	if got := reqs[1].Values.Get("text"); got != "Fin de la suscripcion a logs de nginx.service." {
+		t.Fatalf("end = %q", got)
+	}
+}
+
+func TestStreamLinesSplitsLargeBatches(t *testing.T) {
+	bot, client := testutil.NewFakeBot()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	lines := make([]string, 400)
+	for i := range lines {
+		lines[i] = strings.Repeat("<x>", 10)
+	}
+	lines = append(lines, strings.Repeat("&", 5000))
+	source := func(ctx context.Context) ([]string, error) { return lines, nil }
+	if err := StreamLines(ctx, bot, 1, "", "nginx.service", source); err != nil {
+		t.Fatalf("StreamLines() error = %v", err)
+	}
+
+	reqs := client.Requests()
+	if len(reqs) < 4 {
+		t.Fatalf("requests = %d, want the batch split over several messages", len(reqs))
+	}
+	sent := 0
+	for _, req := range reqs[:len(reqs)-1] {
+		text := req.Values.Get("text")
+		if len(text) > 4096 {
+			t.Fatalf("message of %d bytes exceeds the Telegram limit", len(text))
+		}
+		if !strings.HasPrefix(text, "<pre>Logs iniciales de nginx.service:\n") {
+			t.Fatalf("message without header: %q", text[:40])
+		}
+		sent += strings.Count(text, "&lt;x&gt;&lt;x&gt;")
+	}
+	if sent != 400*5 {
+		t.Fatalf("sent %d line fragments, want every line", sent)
+	}
+}
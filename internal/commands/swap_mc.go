@@ -21,15 +21,15 @@ func SwapMC(ctx *Context) error {
 
 	running, err := detectRunningMC(ctx, runCtx)
 	if err != nil {
-		return ctx.ReplyError("No se pudo leer el estado de los contenedores.", err)
+		return ctx.ReplyError(ctx.T("swap_mc.state_error"), err)
 	}
 	if running == "" {
-		return ctx.Reply("No se detecta mc-server ni mc-server-mod en ejecución.")
+		return ctx.Reply(ctx.T("swap_mc.none"))
 	}
 
 	target := oppositeContainer(running)
 	if target == "" {
-		return ctx.Reply("El contenedor activo no es compatible con esta operación.")
+		return ctx.Reply(ctx.T("swap_mc.unsupported"))
 	}
 
 	if _, stderr, err := ctx.Runner.Run(runCtx, "docker", "stop", running); err != nil {
@@ -37,18 +37,18 @@ func SwapMC(ctx *Context) error {
 		if msg == "" {
 			msg = err.Error()
 		}
-		return ctx.ReplyError("No se pudo detener el servidor actual.", fmt.Errorf("%w: %s", err, msg))
+		return ctx.ReplyError(ctx.T("swap_mc.stop_error"), fmt.Errorf("%w: %s", err, msg))
 	}
 
 	if err := waitForContainerStop(ctx, runCtx, running); err != nil {
-		return ctx.ReplyError("No se detuvo el contenedor anterior.", err)
+		return ctx.ReplyError(ctx.T("swap_mc.still_running"), err)
 	}
 
 	if err := startContainer(ctx, runCtx, target); err != nil {
-		return ctx.ReplyError("No se pudo iniciar el nuevo servidor.", err)
+		return ctx.ReplyError(ctx.T("swap_mc.start_error"), err)
 	}
 
-	return ctx.Reply(fmt.Sprintf("Se detuvo %s y se inició %s.", running, target))
+	return ctx.Reply(ctx.T("swap_mc.done", running, target))
 }
 
 func detectRunningMC(ctx *Context, runCtx context.Context) (string, error) {
@@ -1,46 +1,228 @@
 package commands
 
 import (
+	"context"
 	"fmt"
-	"runtime"
+	"html"
+	"strconv"
 	"strings"
+	"time"
 
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/procs"
 	"serverbot/internal/system"
 )
 
-// Top displays the processes with the highest CPU and memory usage.
+const (
+	topDefaultLimit   = 10
+	topMaxLimit       = 30
+	topSampleInterval = time.Second
+	topCmdlineMax     = 120
+	// topTextLimit keeps the reply under Telegram's 4096 character limit;
+	// processes past it are summarised in a count.
+	topTextLimit = 3500
+)
+
+// sampleProcesses is replaced in tests to avoid sampling the real host.
+var sampleProcesses = procs.Top
+
+// Top displays the heaviest processes by CPU, memory or disk IO.
+// Usage: /top [cpu|mem|io] [N]
 func Top(ctx *Context) error {
-	command, args, ok := topCommandForOS()
-	if !ok {
-		return ctx.Reply("El comando /top no está disponible en este sistema operativo.")
+	sortBy := procs.SortCPU
+	limit := topDefaultLimit
+	for _, arg := range ctx.ArgsList() {
+		if key, ok := procs.ParseSortKey(arg); ok {
+			sortBy = key
+			continue
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return ctx.Reply(ctx.T("top.usage"))
+		}
+		limit = min(n, topMaxLimit)
 	}
 
 	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
 	defer cancel()
 
-	stdout, stderr, err := ctx.Runner.Run(runCtx, command, args...)
+	if ctx.Host != nil {
+		return remoteTop(runCtx, ctx, sortBy, limit)
+	}
+
+	infos, err := sampleProcesses(runCtx, procs.TopOptions{
+		Interval: topSampleInterval,
+		SortBy:   sortBy,
+		Limit:    limit,
+	})
+	if err != nil {
+		return ctx.ReplyError(ctx.T("top.error"), err)
+	}
+	if len(infos) == 0 {
+		return ctx.Reply(ctx.T("top.empty"))
+	}
+
+	return ctx.ReplyHTML(formatTop(ctx.Lang, infos, sortBy, containerNames(runCtx, ctx, infos), ""), false)
+}
+
+// remoteTop ranks the processes of ctx.Host with ps through its Runner.
+// ps reports CPU averaged over each process lifetime and no IO rates.
+func remoteTop(runCtx context.Context, ctx *Context, sortBy procs.SortKey, limit int) error {
+	if sortBy == procs.SortIO {
+		return ctx.Reply(ctx.T("top.io_remote"))
+	}
+	order := "-pcpu"
+	if sortBy == procs.SortMem {
+		order = "-rss"
+	}
+
+	stdout, stderr, err := ctx.Runner.Run(runCtx, "ps", "-eo", "pid=,user=,pcpu=,pmem=,rss=,args=", "--sort="+order)
+	if err != nil {
+		return ctx.ReplyError(ctx.T("top.error"), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+	}
+	infos := parsePS(stdout)
+	if len(infos) == 0 {
+		return ctx.Reply(ctx.T("top.empty"))
+	}
+	return ctx.ReplyHTML(formatTop(ctx.Lang, infos[:min(limit, len(infos))], sortBy, nil, ctx.Host.Name()), false)
+}
+
+// parsePS reads "ps -eo pid=,user=,pcpu=,pmem=,rss=,args=" output.
+func parsePS(out string) []procs.Info {
+	var infos []procs.Info
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		pid, err := strconv.ParseInt(fields[0], 10, 32)
+		if err != nil {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(fields[2], 64)
+		mem, _ := strconv.ParseFloat(fields[3], 64)
+		rssKB, _ := strconv.ParseUint(fields[4], 10, 64)
+		name := fields[5]
+		if i := strings.LastIndexByte(name, '/'); i >= 0 && i < len(name)-1 {
+			name = name[i+1:]
+		}
+		infos = append(infos, procs.Info{
+			PID:        int32(pid),
+			Name:       name,
+			User:       fields[1],
+			CPUPercent: cpu,
+			MemPercent: mem,
+			RSS:        rssKB << 10,
+			Cmdline:    strings.Join(fields[5:], " "),
+		})
+	}
+	return infos
+}
+
+// containerNames maps full container IDs to names. Docker being absent or
+// unreachable is not an error: processes then show the short ID.
+func containerNames(runCtx context.Context, ctx *Context, infos []procs.Info) map[string]string {
+	needed := false
+	for _, info := range infos {
+		if info.ContainerID != "" {
+			needed = true
+			break
+		}
+	}
+	if !needed || ctx.Runner == nil {
+		return nil
+	}
+
+	stdout, _, err := ctx.Runner.Run(runCtx, "docker", "ps", "--no-trunc", "--format", "{{.ID}} {{.Names}}")
 	if err != nil {
-		return ctx.ReplyError("No se pudo obtener la información de procesos.", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))
+		if ctx.Logger != nil {
+			ctx.Logger.Printf("top: docker ps failed: %v", err)
+		}
+		return nil
 	}
 
-	if stdout == "" {
-		return ctx.Reply("No se obtuvo información de procesos.")
+	names := make(map[string]string)
+	for _, line := range strings.Split(stdout, "\n") {
+		id, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			names[id] = name
+		}
 	}
+	return names
+}
 
-	lines := strings.Split(strings.TrimSpace(stdout), "\n")
-	if len(lines) > 10 {
-		lines = lines[:10]
+// formatTop renders infos; host names the remote machine they come from,
+// which has no IO rates to show.
+func formatTop(lang string, infos []procs.Info, sortBy procs.SortKey, containers map[string]string, host string) string {
+	var buf strings.Builder
+	if host != "" {
+		buf.WriteString(i18n.T(lang, "top.title_host", html.EscapeString(host), topSortLabel(lang, sortBy)) + "\n")
+	} else {
+		buf.WriteString(i18n.T(lang, "top.title", topSortLabel(lang, sortBy)) + "\n")
 	}
 
-	body := "PID   COMMAND         %CPU  %MEM\n" + strings.Join(lines, "\n")
-	return ctx.ReplyPre(body)
+	for i, info := range infos {
+		name := info.Name
+		if name == "" {
+			name = "?"
+		}
+
+		lines := []string{
+			fmt.Sprintf("CPU %.1f%% - RAM %s (%.1f%%)", info.CPUPercent, metrics.HumanBytes(info.RSS), info.MemPercent),
+		}
+		if host == "" {
+			lines = append(lines, i18n.T(lang, "top.io", metrics.HumanBytes(info.ReadPerSec), metrics.HumanBytes(info.WritePerSec)))
+		}
+
+		owner := i18n.T(lang, "top.user", valueOr(info.User, "?"))
+		if info.ContainerID != "" {
+			container := containers[info.ContainerID]
+			if container == "" {
+				container = info.ContainerID[:12]
+			}
+			owner += i18n.T(lang, "top.container", container)
+		}
+		lines = append(lines, owner)
+
+		if cmdline := truncateRunes(info.Cmdline, topCmdlineMax); cmdline != "" {
+			lines = append(lines, cmdline)
+		}
+
+		var section strings.Builder
+		metrics.WriteSection(&section, "▫️", fmt.Sprintf("%d %s", info.PID, name), lines)
+		if buf.Len()+section.Len() > topTextLimit {
+			buf.WriteString("\n" + i18n.N(lang, "top.more", len(infos)-i))
+			break
+		}
+		buf.WriteString(section.String())
+	}
+
+	return strings.TrimSpace(buf.String())
 }
 
-func topCommandForOS() (string, []string, bool) {
-	switch runtime.GOOS {
-	case "linux", "darwin":
-		return "ps", []string{"-eo", "pid,comm,%cpu,%mem", "--sort=-%cpu", "--no-headers"}, true
+func topSortLabel(lang string, key procs.SortKey) string {
+	switch key {
+	case procs.SortMem:
+		return i18n.T(lang, "top.by_memory")
+	case procs.SortIO:
+		return "IO"
 	default:
-		return "", nil, false
+		return "CPU"
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
 	}
+	return string(runes[:max-1]) + "…"
 }
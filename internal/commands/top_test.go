@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"serverbot/internal/procs"
+	"serverbot/internal/testutil"
+)
+
+func TestTopRendersSampledProcesses(t *testing.T) {
+	containerID := strings.Repeat("ab", 32)
+	var gotOpts procs.TopOptions
+	old := sampleProcesses
+	sampleProcesses = func(ctx context.Context, opts procs.TopOptions) ([]procs.Info, error) {
+		gotOpts = opts
+		return []procs.Info{
+			{PID: 10, Name: "nginx", User: "www-data", Cmdline: "nginx -g daemon off;", RSS: 2048, MemPercent: 1.5, ContainerID: containerID},
+			{PID: 1, Name: "systemd", User: "root"},
+		}, nil
+	}
+	defer func() { sampleProcesses = old }()
+
+	runner := &fakeRunner{
+		t:        t,
+		wantName: "docker",
+		wantArgs: []string{"ps", "--no-trunc", "--format", "{{.ID}} {{.Names}}"},
+		stdout:   containerID + " web\n",
+	}
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.AppConfig.CommandTimeout = time.Second
+	ctx.Runner = runner
+	ctx.Arguments = "mem 5"
+
+	if err := Top(ctx); err != nil {
+		t.Fatalf("Top() error = %v", err)
+	}
+	if gotOpts.SortBy != procs.SortMem || gotOpts.Limit != 5 {
+		t.Fatalf("options = %+v", gotOpts)
+	}
+
+	text := client.Requests()[0].Values.Get("text")
+	for _, needle := range []string{
+		"Top procesos por memoria",
+		"<b>10 nginx</b>",
+		"RAM 2.0KB (1.5%)",
+		"Usuario: www-data - Contenedor: web",
+		"nginx -g daemon off;",
+		"<b>1 systemd</b>",
+	} {
+		if !strings.Contains(text, needle) {
+			t.Fatalf("reply missing %q:\n%s", needle, text)
+		}
+	}
+}
+
+func TestFormatTopFitsInOneMessage(t *testing.T) {
+	infos := make([]procs.Info, topMaxLimit)
+	for i := range infos {
+		infos[i] = procs.Info{
+			PID:         int32(100000 + i),
+			Name:        strings.Repeat("n", 15),
+			User:        strings.Repeat("u", 32),
+			Cmdline:     strings.Repeat("&", topCmdlineMax*2),
+			ContainerID: strings.Repeat("ab", 32),
+		}
+	}
+	got := formatTop("", infos, procs.SortCPU, nil, "")
+	if n := utf8.RuneCountInString(got); n > 4096 {
+		t.Fatalf("formatTop() is %d characters long", n)
+	}
+	if !strings.HasSuffix(got, "procesos mas") {
+		t.Fatalf("formatTop() does not summarise the dropped processes:\n%s", got)
+	}
+}
+
+func TestTopRejectsInvalidArguments(t *testing.T) {
+	old := sampleProcesses
+	sampleProcesses = func(ctx context.Context, opts procs.TopOptions) ([]procs.Info, error) {
+		t.Fatalf("sampler should not run")
+		return nil, nil
+	}
+	defer func() { sampleProcesses = old }()
+
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.Arguments = "disk"
+
+	if err := Top(ctx); err != nil {
+		t.Fatalf("Top() error = %v", err)
+	}
+	if got := client.Requests()[0].Values.Get("text"); !strings.HasPrefix(got, "Uso: /top") {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestTopOnRemoteHostUsesPS(t *testing.T) {
+	old := sampleProcesses
+	sampleProcesses = func(context.Context, procs.TopOptions) ([]procs.Info, error) {
+		t.Fatal("remote /top sampled the local host")
+		return nil, nil
+	}
+	defer func() { sampleProcesses = old }()
+
+	runner := &fakeRunner{
+		t:        t,
+		wantName: "ps",
+		wantArgs: []string{"-eo", "pid=,user=,pcpu=,pmem=,rss=,args=", "--sort=-rss"},
+		stdout:   "  812 postgres  3.5 12.0 524288 /usr/lib/postgresql/16/bin/postgres -D /var/lib/pg\n    1 root      0.0  0.1  12000 /sbin/init\n",
+	}
+	bot, client := testutil.NewFakeBot()
+	ctx := newContext(bot)
+	ctx.AppConfig.CommandTimeout = time.Second
+	ctx.Host = fakeHost{name: "nas", runner: runner}
+	ctx.Runner = runner
+	ctx.Arguments = "mem 1"
+
+	if err := Top(ctx); err != nil {
+		t.Fatalf("Top() error = %v", err)
+	}
+	text := client.Requests()[0].Values.Get("text")
+	for _, needle := range []string{"Top procesos de nas por memoria", "<b>812 postgres</b>", "RAM 512.0MB (12.0%)", "-D /var/lib/pg"} {
+		if !strings.Contains(text, needle) {
+			t.Fatalf("reply missing %q:\n%s", needle, text)
+		}
+	}
+	if strings.Contains(text, "init") || strings.Contains(text, "IO:") {
+		t.Fatalf("reply ignores the limit or shows IO:\n%s", text)
+	}
+}
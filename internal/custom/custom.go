@@ -0,0 +1,383 @@
+// Package custom turns command definitions from COMMANDS_FILE into bot
+// commands. A definition runs a fixed argv template; user input only fills
+// typed, validated parameters and never reaches a shell.
+package custom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxTimeout = time.Hour
+	maxParams  = 8
+)
+
+var (
+	// Telegram command names: lowercase letters, digits and underscores.
+	namePattern      = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	paramNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	placeholder      = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+	// defaultPattern is what a string parameter accepts when it sets none.
+	defaultPattern = `[A-Za-z0-9_][A-Za-z0-9_.:@-]*`
+	// shells would interpret parameter values, defeating the argv template.
+	shells = []string{"sh", "bash", "dash", "zsh", "ksh", "ash", "fish", "csh", "tcsh"}
+)
+
+// Output modes.
+const (
+	OutputPre    = "pre"
+	OutputFile   = "file"
+	OutputSilent = "silent"
+)
+
+// Parameter types.
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeEnum   = "enum"
+)
+
+// File is the layout of COMMANDS_FILE.
+type File struct {
+	Commands []Command `yaml:"commands"`
+}
+
+// Command is one custom command definition.
+type Command struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Scope is public, admin or owner (the default).
+	Scope string `yaml:"scope"`
+	// Command is the argv to run. "{param}" placeholders are replaced by
+	// validated arguments; the program itself cannot be a placeholder.
+	Command []string `yaml:"command"`
+	Params  []Param  `yaml:"params"`
+	// Timeout defaults to the bot-wide command timeout.
+	Timeout time.Duration `yaml:"timeout"`
+	// Output is pre (the default), file or silent.
+	Output string `yaml:"output"`
+	// Remote lets the command run on a host selected with "@name".
+	Remote bool `yaml:"remote"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+// Param is a positional argument of a custom command.
+type Param struct {
+	Name string `yaml:"name"`
+	// Type is string (the default), int or enum.
+	Type string `yaml:"type"`
+	// Pattern is a regular expression the whole value must match (string).
+	Pattern string `yaml:"pattern"`
+	// Values lists the accepted values (enum).
+	Values []string `yaml:"values"`
+	// Min and Max bound the value (int).
+	Min *int `yaml:"min"`
+	Max *int `yaml:"max"`
+	// Default makes the parameter optional. Only trailing parameters may
+	// have one.
+	Default *string `yaml:"default"`
+}
+
+// Load reads and validates path. Every problem found is reported.
+func Load(path string) ([]Command, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read commands file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a commands file, rejecting unknown keys, and validates every
+// command.
+func Parse(data []byte) ([]Command, error) {
+	var file File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse commands file: %w", err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(file.Commands))
+	for i := range file.Commands {
+		cmd := &file.Commands[i]
+		cmd.normalize()
+		if seen[cmd.Name] {
+			errs = append(errs, fmt.Errorf("command %q is defined twice", cmd.Name))
+		}
+		seen[cmd.Name] = true
+		label := cmd.Name
+		if label == "" {
+			label = "#" + strconv.Itoa(i+1)
+		}
+		for _, err := range cmd.validate() {
+			errs = append(errs, fmt.Errorf("command %s: %w", label, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return file.Commands, nil
+}
+
+func (c *Command) normalize() {
+	c.Name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(c.Name)), "/")
+	c.Scope = strings.ToLower(strings.TrimSpace(c.Scope))
+	if c.Scope == "" {
+		c.Scope = "owner"
+	}
+	c.Output = strings.ToLower(strings.TrimSpace(c.Output))
+	if c.Output == "" {
+		c.Output = OutputPre
+	}
+	for i := range c.Params {
+		p := &c.Params[i]
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+		if p.Type == "" {
+			p.Type = TypeString
+		}
+	}
+}
+
+// validate checks the definition and compiles its parameter patterns.
+func (c *Command) validate() []error {
+	var errs []error
+	fail := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+	if !namePattern.MatchString(c.Name) {
+		fail("name must be 1-32 characters of a-z, 0-9 and _")
+	}
+	if strings.TrimSpace(c.Description) == "" {
+		fail("description is required")
+	}
+	switch c.Scope {
+	case "public", "admin", "owner":
+	default:
+		fail("scope %q: want public, admin or owner", c.Scope)
+	}
+	switch c.Output {
+	case OutputPre, OutputFile, OutputSilent:
+	default:
+		fail("output %q: want pre, file or silent", c.Output)
+	}
+	if c.Timeout < 0 || c.Timeout > maxTimeout {
+		fail("timeout %s: want up to %s", c.Timeout, maxTimeout)
+	}
+
+	params := make(map[string]bool, len(c.Params))
+	if len(c.Params) > maxParams {
+		fail("at most %d params are allowed", maxParams)
+	}
+	c.patterns = make(map[string]*regexp.Regexp)
+	optional := false
+	for _, p := range c.Params {
+		if !paramNamePattern.MatchString(p.Name) {
+			fail("param %q: name must start with a-z and contain only a-z, 0-9 and _", p.Name)
+			continue
+		}
+		if params[p.Name] {
+			fail("param %q is defined twice", p.Name)
+		}
+		params[p.Name] = true
+		if p.Default == nil && optional {
+			fail("param %q: required params cannot follow one with a default", p.Name)
+		}
+		optional = optional || p.Default != nil
+		if err := c.compileParam(p); err != nil {
+			fail("param %q: %v", p.Name, err)
+		}
+	}
+
+	if len(c.Command) == 0 || strings.TrimSpace(c.Command[0]) == "" {
+		fail("command is required")
+	} else {
+		program := c.Command[0]
+		if strings.ContainsAny(program, "{}") {
+			fail("the program %q cannot be a placeholder", program)
+		}
+		if !filepath.IsAbs(program) && strings.Contains(program, "/") {
+			fail("program %q: use an absolute path or a name looked up in PATH", program)
+		}
+		shell := slices.Contains(shells, filepath.Base(program))
+		used := make(map[string]bool)
+		for _, arg := range c.Command[1:] {
+			for _, m := range placeholder.FindAllStringSubmatch(arg, -1) {
+				used[m[1]] = true
+				if !params[m[1]] {
+					fail("placeholder {%s} has no matching param", m[1])
+				}
+				if shell {
+					fail("placeholder {%s} would be interpreted by %s; run a script with arguments instead", m[1], filepath.Base(program))
+				}
+			}
+			if strings.ContainsAny(placeholder.ReplaceAllString(arg, ""), "{}") {
+				fail("argument %q has an unbalanced brace", arg)
+			}
+		}
+		for _, p := range c.Params {
+			if params[p.Name] && !used[p.Name] {
+				fail("param %q is not used in command", p.Name)
+			}
+		}
+	}
+	return errs
+}
+
+// compileParam checks the type-specific settings of p, including that its
+// default is itself a valid value.
+func (c *Command) compileParam(p Param) error {
+	switch p.Type {
+	case TypeString:
+		if len(p.Values) > 0 || p.Min != nil || p.Max != nil {
+			return errors.New("string params only accept pattern")
+		}
+		pattern := p.Pattern
+		if pattern == "" {
+			pattern = defaultPattern
+		}
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		c.patterns[p.Name] = re
+	case TypeInt:
+		if p.Pattern != "" || len(p.Values) > 0 {
+			return errors.New("int params only accept min and max")
+		}
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			return errors.New("min is greater than max")
+		}
+	case TypeEnum:
+		if p.Pattern != "" || p.Min != nil || p.Max != nil {
+			return errors.New("enum params only accept values")
+		}
+		if len(p.Values) == 0 {
+			return errors.New("enum params need values")
+		}
+	default:
+		return fmt.Errorf("type %q: want string, int or enum", p.Type)
+	}
+	if p.Default != nil {
+		if _, err := c.check(p, *p.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+// check validates one argument value for p and returns it normalized.
+func (c *Command) check(p Param, value string) (string, error) {
+	switch p.Type {
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		if (p.Min != nil && n < *p.Min) || (p.Max != nil && n > *p.Max) {
+			return "", fmt.Errorf("%d is out of range%s", n, p.rangeText())
+		}
+		return strconv.Itoa(n), nil
+	case TypeEnum:
+		if !slices.Contains(p.Values, value) {
+			return "", fmt.Errorf("%q is not one of %s", value, strings.Join(p.Values, ", "))
+		}
+		return value, nil
+	default:
+		// A leading dash would turn a value into an option of the program.
+		if strings.HasPrefix(value, "-") || !c.patterns[p.Name].MatchString(value) {
+			return "", fmt.Errorf("%q is not allowed", value)
+		}
+		return value, nil
+	}
+}
+
+func (p Param) rangeText() string {
+	switch {
+	case p.Min != nil && p.Max != nil:
+		return fmt.Sprintf(" %d-%d", *p.Min, *p.Max)
+	case p.Min != nil:
+		return fmt.Sprintf(" (min %d)", *p.Min)
+	case p.Max != nil:
+		return fmt.Sprintf(" (max %d)", *p.Max)
+	}
+	return ""
+}
+
+// ArgError reports arguments that do not fit a command's params. Param is
+// empty when the count is wrong.
+type ArgError struct {
+	Param string
+	Err   error
+}
+
+func (e *ArgError) Error() string {
+	if e.Param == "" {
+		return e.Err.Error()
+	}
+	return e.Param + ": " + e.Err.Error()
+}
+
+func (e *ArgError) Unwrap() error { return e.Err }
+
+// Argv validates args against the params and expands the command template.
+func (c *Command) Argv(args []string) ([]string, error) {
+	if len(args) > len(c.Params) {
+		return nil, &ArgError{Err: errors.New("too many arguments")}
+	}
+	values := make(map[string]string, len(c.Params))
+	for i, p := range c.Params {
+		if i >= len(args) {
+			if p.Default == nil {
+				return nil, &ArgError{Err: fmt.Errorf("missing %s", p.Name)}
+			}
+			values[p.Name] = *p.Default
+			continue
+		}
+		v, err := c.check(p, args[i])
+		if err != nil {
+			return nil, &ArgError{Param: p.Name, Err: err}
+		}
+		values[p.Name] = v
+	}
+
+	argv := make([]string, len(c.Command))
+	argv[0] = c.Command[0]
+	for i, arg := range c.Command[1:] {
+		// A single pass: values are never expanded again.
+		argv[i+1] = placeholder.ReplaceAllStringFunc(arg, func(m string) string {
+			return values[m[1:len(m)-1]]
+		})
+	}
+	return argv, nil
+}
+
+// Usage returns the command line syntax, e.g. "/backup_db <db> [days]".
+func (c *Command) Usage() string {
+	var b strings.Builder
+	b.WriteString("/" + c.Name)
+	for _, p := range c.Params {
+		name := p.Name
+		if p.Type == TypeEnum {
+			name = strings.Join(p.Values, "|")
+		}
+		if p.Default != nil {
+			fmt.Fprintf(&b, " [%s]", name)
+		} else {
+			fmt.Fprintf(&b, " <%s>", name)
+		}
+	}
+	return b.String()
+}
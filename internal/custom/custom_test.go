@@ -0,0 +1,186 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/system"
+	"serverbot/internal/testutil"
+)
+
+const sampleFile = `
+commands:
+  - name: restart_nginx
+    description: Reinicia nginx
+    scope: admin
+    command: [systemctl, restart, nginx]
+    output: silent
+  - name: backup_db
+    description: Copia de seguridad de una base de datos
+    timeout: 10m
+    output: file
+    params:
+      - {name: db, type: enum, values: [app, metrics]}
+      - {name: keep, type: int, min: 1, max: 30, default: "7"}
+    command: [/usr/local/bin/backup, --db, "{db}", "--keep={keep}"]
+  - name: greet
+    description: Saluda
+    scope: public
+    params:
+      - {name: who}
+    command: [echo, hola, "{who}"]
+`
+
+func TestParseValidFile(t *testing.T) {
+	cmds, err := Parse([]byte(sampleFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(cmds) != 3 || cmds[1].Timeout != 10*time.Minute || cmds[1].Scope != "owner" || cmds[2].Output != OutputPre {
+		t.Fatalf("Parse() = %+v", cmds)
+	}
+	if got := cmds[1].Usage(); got != "/backup_db <app|metrics> [keep]" {
+		t.Fatalf("Usage() = %q", got)
+	}
+}
+
+func TestParseReportsEveryError(t *testing.T) {
+	_, err := Parse([]byte(`
+commands:
+  - name: Bad-Name
+    command: [echo]
+  - name: shell
+    description: x
+    params: [{name: arg}]
+    command: [sh, -c, "echo {arg}"]
+  - name: loose
+    description: x
+    params: [{name: n, type: int, default: "x"}, {name: other}]
+    command: ["{n}", "{missing}", "{other"]
+  - name: shell
+    description: dup
+    command: [true]
+`))
+	if err == nil {
+		t.Fatal("Parse() accepted an invalid file")
+	}
+	for _, want := range []string{
+		"name must be",
+		"description is required",
+		"interpreted by sh",
+		`loose: param "n": default`,
+		"required params cannot follow",
+		"cannot be a placeholder",
+		"{missing} has no matching param",
+		"unbalanced brace",
+		`"shell" is defined twice`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+
+	if _, err := Parse([]byte("commands:\n  - name: x\n    descripton: typo\n")); err == nil || !strings.Contains(err.Error(), "descripton") {
+		t.Fatalf("unknown key error = %v", err)
+	}
+}
+
+func TestArgvRejectsInjection(t *testing.T) {
+	cmds, err := Parse([]byte(sampleFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, greet := cmds[1], cmds[2]
+
+	argv, err := backup.Argv([]string{"metrics"})
+	if err != nil || strings.Join(argv, " ") != "/usr/local/bin/backup --db metrics --keep=7" {
+		t.Fatalf("Argv() = %v, %v", argv, err)
+	}
+	if argv, _ := greet.Argv([]string{"{who}"}); argv != nil {
+		t.Fatalf("Argv() expanded a placeholder in a value: %v", argv)
+	}
+	for _, args := range [][]string{
+		{"app; rm -rf /"},
+		{"app", "31"},
+		{"app", "0x1"},
+		{},
+		{"app", "7", "extra"},
+	} {
+		if _, err := backup.Argv(args); err == nil {
+			t.Errorf("backup Argv(%q) accepted", args)
+		}
+	}
+	for _, who := range []string{"--help", "$(id)", "a/../b", "`id`"} {
+		if _, err := greet.Argv([]string{who}); err == nil {
+			t.Errorf("greet Argv(%q) accepted", who)
+		}
+	}
+}
+
+type recordingRunner struct {
+	argv []string
+	out  string
+	err  error
+}
+
+func (r *recordingRunner) Run(_ context.Context, name string, args ...string) (string, string, error) {
+	r.argv = append([]string{name}, args...)
+	return r.out, "", r.err
+}
+
+func TestSetApplyAndReload(t *testing.T) {
+	cmds, err := Parse([]byte(sampleFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &recordingRunner{out: "hola mundo\n"}
+	registry := commands.NewRegistry(commands.Dependencies{
+		Config: app.Config{OwnerID: 1, CommandTimeout: time.Second},
+		Runner: runner,
+	})
+	registry.Handle("help", "Ayuda", commands.ScopePublic, func(*commands.Context) error { return nil })
+	set := &Set{Registry: registry}
+
+	if err := set.Apply(cmds); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if registry.List(commands.ScopeAdmin)["restart_nginx"] != "Reinicia nginx" {
+		t.Fatalf("restart_nginx not listed for admins: %v", registry.List(commands.ScopeAdmin))
+	}
+
+	bot, client := testutil.NewFakeBot()
+	if err := registry.Dispatch(context.Background(), bot, commands.SyntheticUpdate(42, "greet", "mundo")); err != nil {
+		t.Fatal(err)
+	}
+	reqs := client.Requests()
+	if strings.Join(runner.argv, " ") != "echo hola mundo" || len(reqs) != 1 || !strings.Contains(reqs[0].Values.Get("text"), "<pre>hola mundo</pre>") {
+		t.Fatalf("argv %v, replies %+v", runner.argv, reqs)
+	}
+
+	runner.err = fmt.Errorf("command failed: %w", &system.ExitError{Code: 2})
+	if err := registry.Dispatch(context.Background(), bot, commands.SyntheticUpdate(1, "restart_nginx", "")); err != nil {
+		t.Fatal(err)
+	}
+	if text := client.Requests()[1].Values.Get("text"); text != "/restart_nginx termino con codigo 2." {
+		t.Fatalf("reply = %q", text)
+	}
+
+	// A reload drops removed commands; built-in names stay protected.
+	if err := set.Apply(cmds[2:]); err != nil {
+		t.Fatalf("reload error = %v", err)
+	}
+	if registry.Has("restart_nginx") || !registry.Has("greet") || !slices.Equal(set.Names(), []string{"greet"}) {
+		t.Fatalf("after reload names = %v", set.Names())
+	}
+	clash := cmds[2]
+	clash.Name = "help"
+	if err := set.Apply([]Command{clash}); err == nil || !registry.Has("greet") {
+		t.Fatalf("Apply() over a built-in = %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"serverbot/internal/audit"
+	"serverbot/internal/commands"
+	"serverbot/internal/system"
+)
+
+// inlineLimit is the longest output sent as a message in pre mode; longer
+// output is attached as a file.
+const inlineLimit = 3500
+
+// Set registers custom commands in a Registry and replaces them on reload.
+type Set struct {
+	Registry *commands.Registry
+	Logger   *log.Logger
+
+	mu    sync.Mutex
+	names []string
+}
+
+// Apply replaces the commands registered by a previous Apply with cmds. A
+// name already taken by a built-in command is an error, and then nothing
+// changes.
+func (s *Set) Apply(cmds []Command) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, c := range cmds {
+		if s.Registry.Has(c.Name) && !slices.Contains(s.names, c.Name) {
+			errs = append(errs, fmt.Errorf("command %q: name is taken by a built-in command", c.Name))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	s.Registry.Remove(s.names...)
+	s.names = s.names[:0]
+	for _, c := range cmds {
+		scope, mws := c.scope()
+		s.Registry.Handle(c.Name, c.Description, scope, c.handler(), mws...)
+		if c.Remote {
+			s.Registry.AllowRemote(c.Name)
+		}
+		s.names = append(s.names, c.Name)
+	}
+	s.log("registered %d custom commands", len(cmds))
+	return nil
+}
+
+// Names returns the names of the commands currently registered.
+func (s *Set) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func (s *Set) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("custom: "+format, args...)
+	}
+}
+
+func (c Command) scope() (commands.CommandScope, []commands.Middleware) {
+	switch c.Scope {
+	case "public":
+		return commands.ScopePublic, nil
+	case "admin":
+		return commands.ScopeAdmin, []commands.Middleware{commands.AdminOnly()}
+	default:
+		return commands.ScopeOwner, []commands.Middleware{commands.OwnerOnly()}
+	}
+}
+
+// handler runs the command with the arguments of the request.
+func (c Command) handler() commands.Handler {
+	return func(ctx *commands.Context) error {
+		argv, err := c.Argv(ctx.ArgsList())
+		if err != nil {
+			var argErr *ArgError
+			if errors.As(err, &argErr) && argErr.Param != "" {
+				return ctx.Reply(ctx.T("custom.invalid_value", argErr.Param, c.Usage()))
+			}
+			return ctx.Reply(ctx.T("custom.usage", c.Usage()))
+		}
+
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = ctx.AppConfig.CommandTimeout
+		}
+		runCtx, cancel := system.WithTimeout(ctx.RequestContext, timeout)
+		defer cancel()
+
+		stdout, stderr, err := ctx.Runner.Run(runCtx, argv[0], argv[1:]...)
+		output := strings.TrimSpace(strings.TrimSpace(stdout) + "\n" + strings.TrimSpace(stderr))
+		c.audit(ctx, argv, err)
+
+		var exitErr *system.ExitError
+		switch {
+		case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+			return ctx.ReplyError(ctx.T("custom.timeout", c.Name, timeout), err)
+		case errors.As(err, &exitErr):
+			return c.sendOutput(ctx, ctx.T("custom.exit", c.Name, exitErr.Code), output)
+		case err != nil:
+			return ctx.ReplyError(ctx.T("custom.failed", c.Name), err)
+		}
+		return c.sendOutput(ctx, ctx.T("custom.done", c.Name), output)
+	}
+}
+
+// sendOutput replies according to the output mode. status is sent alone
+// when there is nothing else to show, and as the caption of a file.
+func (c Command) sendOutput(ctx *commands.Context, status, output string) error {
+	if c.Output == OutputSilent || output == "" {
+		return ctx.Reply(status)
+	}
+	if c.Output == OutputFile || len(output) > inlineLimit {
+		return ctx.ReplyDocument(c.Name+".txt", []byte(output+"\n"), status)
+	}
+	return ctx.ReplyHTML(fmt.Sprintf("%s\n<pre>%s</pre>", html.EscapeString(status), html.EscapeString(output)), false)
+}
+
+// audit records runs of admin and owner commands.
+func (c Command) audit(ctx *commands.Context, argv []string, err error) {
+	if c.Scope == "public" {
+		return
+	}
+	entry := audit.Entry{
+		Action:  "custom",
+		Target:  c.Name,
+		Detail:  strings.Join(argv, " "),
+		Outcome: audit.OutcomeOK,
+	}
+	if ctx.Host != nil {
+		entry.Detail = "@" + ctx.Host.Name() + " " + entry.Detail
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailed
+		entry.Error = err.Error()
+	}
+	ctx.Audit(entry)
+}
@@ -0,0 +1,168 @@
+package digest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"serverbot/internal/metrics"
+)
+
+// retention bounds how many hourly buckets are kept; a bit more than a week
+// so the weekly digest always has full coverage.
+const retention = 8 * 24 * time.Hour
+
+// Series summarises a metric over a period.
+type Series struct {
+	Min   float64
+	Avg   float64
+	Max   float64
+	Count int
+}
+
+type accumulator struct {
+	min, max, sum float64
+	count         int
+}
+
+func (a *accumulator) add(v float64) {
+	if a.count == 0 || v < a.min {
+		a.min = v
+	}
+	if a.count == 0 || v > a.max {
+		a.max = v
+	}
+	a.sum += v
+	a.count++
+}
+
+func (a *accumulator) merge(o accumulator) {
+	if o.count == 0 {
+		return
+	}
+	if a.count == 0 || o.min < a.min {
+		a.min = o.min
+	}
+	if a.count == 0 || o.max > a.max {
+		a.max = o.max
+	}
+	a.sum += o.sum
+	a.count += o.count
+}
+
+func (a accumulator) series() Series {
+	if a.count == 0 {
+		return Series{}
+	}
+	return Series{Min: a.min, Avg: a.sum / float64(a.count), Max: a.max, Count: a.count}
+}
+
+type bucket struct {
+	start  time.Time
+	cpu    accumulator
+	memory accumulator
+	disks  map[string]*accumulator
+}
+
+// Summary aggregates every sample recorded within a period.
+type Summary struct {
+	Samples int
+	CPU     Series
+	Memory  Series
+	Disks   map[string]Series
+}
+
+// Mounts returns the disk mounts of the summary in sorted order.
+func (s Summary) Mounts() []string {
+	mounts := make([]string, 0, len(s.Disks))
+	for mount := range s.Disks {
+		mounts = append(mounts, mount)
+	}
+	sort.Strings(mounts)
+	return mounts
+}
+
+// Aggregator keeps hourly min/avg/max buckets of CPU, memory and disk usage.
+type Aggregator struct {
+	mu      sync.Mutex
+	buckets []*bucket
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Add records a metrics sample taken at the given instant.
+func (a *Aggregator) Add(at time.Time, stats metrics.Stats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := at.Truncate(time.Hour)
+	var b *bucket
+	if n := len(a.buckets); n > 0 && a.buckets[n-1].start.Equal(start) {
+		b = a.buckets[n-1]
+	} else {
+		b = &bucket{start: start, disks: make(map[string]*accumulator)}
+		a.buckets = append(a.buckets, b)
+	}
+
+	if stats.CPU.Cores > 0 {
+		b.cpu.add(stats.CPU.Usage)
+	}
+	if stats.Memory.Total > 0 {
+		b.memory.add(stats.Memory.UsedPercent)
+	}
+	for _, disk := range stats.Disks {
+		acc, ok := b.disks[disk.Mount]
+		if !ok {
+			acc = &accumulator{}
+			b.disks[disk.Mount] = acc
+		}
+		acc.add(disk.UsedPercent)
+	}
+
+	cutoff := at.Add(-retention)
+	drop := 0
+	for drop < len(a.buckets) && a.buckets[drop].start.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		a.buckets = append([]*bucket(nil), a.buckets[drop:]...)
+	}
+}
+
+// Summary merges the buckets that start within [from, to).
+func (a *Aggregator) Summary(from, to time.Time) Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var cpu, memory accumulator
+	disks := make(map[string]*accumulator)
+	for _, b := range a.buckets {
+		if b.start.Before(from.Truncate(time.Hour)) || !b.start.Before(to) {
+			continue
+		}
+		cpu.merge(b.cpu)
+		memory.merge(b.memory)
+		for mount, acc := range b.disks {
+			total, ok := disks[mount]
+			if !ok {
+				total = &accumulator{}
+				disks[mount] = total
+			}
+			total.merge(*acc)
+		}
+	}
+
+	summary := Summary{
+		Samples: max(cpu.count, memory.count),
+		CPU:     cpu.series(),
+		Memory:  memory.series(),
+		Disks:   make(map[string]Series, len(disks)),
+	}
+	for mount, acc := range disks {
+		summary.Disks[mount] = acc.series()
+	}
+	return summary
+}
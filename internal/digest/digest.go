@@ -0,0 +1,441 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/revanced"
+	"serverbot/internal/scheduler"
+	"serverbot/internal/system"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// topContainers is how many containers are listed per ranking.
+const topContainers = 3
+
+// Period selects the time window covered by a digest.
+type Period int
+
+const (
+	Daily Period = iota
+	Weekly
+)
+
+func (p Period) window() time.Duration {
+	if p == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+func (p Period) title(lang string) string {
+	if p == Weekly {
+		return i18n.T(lang, "digest.weekly")
+	}
+	return i18n.T(lang, "digest.daily")
+}
+
+// ContainerUsage is a point-in-time resource reading of a container.
+type ContainerUsage struct {
+	Name       string
+	CPUPercent float64
+	MemPercent float64
+	MemUsage   string
+}
+
+// Report holds everything rendered in a digest message.
+type Report struct {
+	Period     Period
+	From       time.Time
+	To         time.Time
+	Summary    Summary
+	Containers []ContainerUsage
+	Restarts   map[string]int
+	Alerts     []alerts.Event
+	Uptime     time.Duration
+	LastBuild  *revanced.BuildResult
+	Warnings   []string
+}
+
+// Service samples metrics in the background and sends periodic digests.
+type Service struct {
+	Config         app.DigestConfig
+	CommandTimeout time.Duration
+	Sampler        *metrics.Sampler
+	Runner         system.Runner
+	Notifier       *alerts.Notifier
+	Revanced       *revanced.Service
+	Logger         *log.Logger
+	Aggregator     *Aggregator
+	// Languages picks the language of each chat's digest; nil uses the
+	// default language.
+	Languages *i18n.Store
+
+	mu   sync.Mutex
+	last metrics.Stats
+}
+
+// NewService wires a digest Service. notifier and revSvc may be nil.
+func NewService(cfg app.Config, sampler *metrics.Sampler, runner system.Runner, notifier *alerts.Notifier, revSvc *revanced.Service, logger *log.Logger) *Service {
+	return &Service{
+		Config:         cfg.Digest,
+		CommandTimeout: cfg.CommandTimeout,
+		Sampler:        sampler,
+		Runner:         runner,
+		Notifier:       notifier,
+		Revanced:       revSvc,
+		Logger:         logger,
+		Aggregator:     NewAggregator(),
+	}
+}
+
+// Run samples metrics every SampleInterval and sends the daily digest (and
+// the weekly one on the configured weekday) until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, bot *tgbotapi.BotAPI) {
+	sched, err := scheduler.Parse(fmt.Sprintf("%d %d * * *", s.Config.Minute, s.Config.Hour))
+	if err != nil {
+		s.log("invalid send time: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(s.Config.SampleInterval)
+	defer ticker.Stop()
+	s.sample(ctx)
+
+	for {
+		next := sched.Next(time.Now().In(s.location()))
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-ticker.C:
+			timer.Stop()
+			s.sample(ctx)
+		case <-timer.C:
+			s.send(ctx, bot, Daily, next)
+			if s.Config.Weekly && next.Weekday() == s.Config.WeeklyDay {
+				s.send(ctx, bot, Weekly, next)
+			}
+		}
+	}
+}
+
+// HandleDigest is the handler for /digest [semanal].
+func (s *Service) HandleDigest(ctx *commands.Context) error {
+	period := Daily
+	if args := ctx.ArgsList(); len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "semanal", "weekly", "week":
+			period = Weekly
+		case "diario", "daily", "day":
+		default:
+			return ctx.Reply(ctx.T("digest.usage"))
+		}
+	}
+
+	sent, err := ctx.ReplyMessage(ctx.T("digest.generating"))
+	if err != nil {
+		return err
+	}
+
+	report := s.Build(ctx.RequestContext, period, time.Now())
+	return ctx.EditHTML(sent.MessageID, FormatHTML(ctx.Lang, report, s.location()))
+}
+
+// Build assembles a report for the period ending at now.
+func (s *Service) Build(ctx context.Context, period Period, now time.Time) Report {
+	report := Report{
+		Period: period,
+		From:   now.Add(-period.window()),
+		To:     now,
+	}
+	report.Summary = s.Aggregator.Summary(report.From, report.To)
+
+	if s.Notifier != nil {
+		report.Alerts = s.Notifier.Events(report.From)
+	}
+
+	s.mu.Lock()
+	report.Uptime = s.last.Host.Uptime
+	s.mu.Unlock()
+
+	if s.Runner != nil {
+		containers, err := s.containerUsage(ctx)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Docker stats: %v", err))
+		}
+		report.Containers = containers
+
+		restarts, err := s.containerRestarts(ctx, report.From, report.To)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("Docker events: %v", err))
+		}
+		report.Restarts = restarts
+	}
+
+	if s.Revanced != nil {
+		st, err := s.Revanced.Store.Load()
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("ReVanced: %v", err))
+		} else {
+			report.LastBuild = st.LastBuild
+		}
+	}
+
+	return report
+}
+
+func (s *Service) sample(ctx context.Context) {
+	if s.Sampler == nil {
+		return
+	}
+	sampleCtx, cancel := context.WithTimeout(ctx, s.CommandTimeout+2*s.Sampler.Collector.SampleInterval())
+	defer cancel()
+
+	// The background sampler usually has a recent snapshot; only sample when
+	// it is older than our own interval.
+	snap, err := s.Sampler.Get(sampleCtx, s.Config.SampleInterval)
+	if err != nil {
+		s.log("collect error: %v", err)
+		return
+	}
+
+	s.Aggregator.Add(snap.At, snap.Stats)
+	s.mu.Lock()
+	s.last = snap.Stats
+	s.mu.Unlock()
+}
+
+func (s *Service) send(ctx context.Context, bot *tgbotapi.BotAPI, period Period, now time.Time) {
+	report := s.Build(ctx, period, now)
+	bodies := make(map[string]string)
+	for _, chatID := range s.Config.ChatIDs {
+		lang := s.Languages.Resolve(chatID, "")
+		body, ok := bodies[lang]
+		if !ok {
+			body = FormatHTML(lang, report, s.location())
+			bodies[lang] = body
+		}
+		msg := tgbotapi.NewMessage(chatID, body)
+		msg.ParseMode = "HTML"
+		if _, err := bot.Send(msg); err != nil {
+			s.log("send to %d failed: %v", chatID, err)
+		}
+	}
+}
+
+func (s *Service) containerUsage(ctx context.Context) ([]ContainerUsage, error) {
+	runCtx, cancel := system.WithTimeout(ctx, s.CommandTimeout)
+	defer cancel()
+
+	stdout, stderr, err := s.Runner.Run(runCtx, "docker", "stats", "--no-stream", "--format",
+		"{{.Name}}\t{{.CPUPerc}}\t{{.MemPerc}}\t{{.MemUsage}}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return parseContainerUsage(stdout), nil
+}
+
+func (s *Service) containerRestarts(ctx context.Context, from, to time.Time) (map[string]int, error) {
+	runCtx, cancel := system.WithTimeout(ctx, s.CommandTimeout)
+	defer cancel()
+
+	stdout, stderr, err := s.Runner.Run(runCtx, "docker", "events",
+		"--since", strconv.FormatInt(from.Unix(), 10),
+		"--until", strconv.FormatInt(to.Unix(), 10),
+		"--filter", "event=restart",
+		"--format", "{{.Actor.Attributes.name}}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	restarts := make(map[string]int)
+	for _, line := range strings.Split(stdout, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			restarts[name]++
+		}
+	}
+	return restarts, nil
+}
+
+func (s *Service) location() *time.Location {
+	if s.Config.Location != nil {
+		return s.Config.Location
+	}
+	return time.Local
+}
+
+func (s *Service) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("digest: "+format, args...)
+	}
+}
+
+// parseContainerUsage parses `docker stats` lines of name, CPU%, mem% and
+// mem usage separated by tabs.
+func parseContainerUsage(out string) []ContainerUsage {
+	var usage []ContainerUsage
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 4 || fields[0] == "" {
+			continue
+		}
+		usage = append(usage, ContainerUsage{
+			Name:       fields[0],
+			CPUPercent: parsePercent(fields[1]),
+			MemPercent: parsePercent(fields[2]),
+			MemUsage:   strings.TrimSpace(fields[3]),
+		})
+	}
+	return usage
+}
+
+func parsePercent(raw string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(raw), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// FormatHTML renders the report with the section layout of metrics.FormatHTML,
+// with labels in lang.
+func FormatHTML(lang string, report Report, loc *time.Location) string {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "<b>📰 %s</b>\n<i>%s - %s (%s)</i>\n", report.Period.title(lang),
+		report.From.In(loc).Format("02/01 15:04"), report.To.In(loc).Format("02/01 15:04"), loc.String())
+
+	sum := report.Summary
+	if sum.Samples == 0 {
+		metrics.WriteSection(&buf, "📈", i18n.T(lang, "digest.metrics"), []string{i18n.T(lang, "digest.no_samples")})
+	} else {
+		if sum.CPU.Count > 0 {
+			metrics.WriteSection(&buf, "⚙️", "CPU", []string{formatSeries(lang, sum.CPU)})
+		}
+		if sum.Memory.Count > 0 {
+			metrics.WriteSection(&buf, "🧠", i18n.T(lang, "stats.memory"), []string{formatSeries(lang, sum.Memory)})
+		}
+		if len(sum.Disks) > 0 {
+			lines := make([]string, 0, len(sum.Disks))
+			for _, mount := range sum.Mounts() {
+				lines = append(lines, fmt.Sprintf("%s %s", mount, formatSeries(lang, sum.Disks[mount])))
+			}
+			metrics.WriteSection(&buf, "💾", i18n.T(lang, "stats.storage"), lines)
+		}
+	}
+
+	metrics.WriteSection(&buf, "🐳", i18n.T(lang, "digest.containers"), containerLines(lang, report))
+	metrics.WriteSection(&buf, "🚨", i18n.T(lang, "digest.alerts"), alertLines(lang, report.Alerts, loc))
+
+	if report.Uptime > 0 {
+		metrics.WriteSection(&buf, "⏱️", "Uptime", []string{i18n.T(lang, "stats.uptime_line", metrics.FormatUptime(report.Uptime))})
+	}
+
+	if lb := report.LastBuild; lb != nil {
+		line := i18n.N(lang, "digest.build_ok", len(lb.Published), lb.FinishedAt.In(loc).Format("02/01 15:04"), len(lb.Published))
+		if !lb.OK {
+			line = i18n.T(lang, "digest.build_failed", lb.FinishedAt.In(loc).Format("02/01 15:04"), truncate(lb.Error, 200))
+		}
+		metrics.WriteSection(&buf, "🛠️", "ReVanced", []string{line})
+	}
+
+	if len(report.Warnings) > 0 {
+		metrics.WriteSection(&buf, "⚠️", i18n.T(lang, "stats.warnings"), report.Warnings)
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+func formatSeries(lang string, s Series) string {
+	return i18n.T(lang, "digest.series", s.Min, s.Avg, s.Max)
+}
+
+func containerLines(lang string, report Report) []string {
+	var lines []string
+	if len(report.Containers) > 0 {
+		byCPU := append([]ContainerUsage(nil), report.Containers...)
+		sort.SliceStable(byCPU, func(i, j int) bool { return byCPU[i].CPUPercent > byCPU[j].CPUPercent })
+		byMem := append([]ContainerUsage(nil), report.Containers...)
+		sort.SliceStable(byMem, func(i, j int) bool { return byMem[i].MemPercent > byMem[j].MemPercent })
+
+		cpu := make([]string, 0, topContainers)
+		for _, c := range byCPU[:min(topContainers, len(byCPU))] {
+			cpu = append(cpu, fmt.Sprintf("%s %.1f%%", c.Name, c.CPUPercent))
+		}
+		mem := make([]string, 0, topContainers)
+		for _, c := range byMem[:min(topContainers, len(byMem))] {
+			mem = append(mem, fmt.Sprintf("%s %.1f%% (%s)", c.Name, c.MemPercent, c.MemUsage))
+		}
+		lines = append(lines,
+			i18n.T(lang, "digest.top_cpu", strings.Join(cpu, ", ")),
+			i18n.T(lang, "digest.top_ram", strings.Join(mem, ", ")))
+	}
+
+	if report.Restarts != nil {
+		if len(report.Restarts) == 0 {
+			lines = append(lines, i18n.T(lang, "digest.no_restarts"))
+		} else {
+			names := make([]string, 0, len(report.Restarts))
+			for name := range report.Restarts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			parts := make([]string, 0, len(names))
+			for _, name := range names {
+				parts = append(parts, fmt.Sprintf("%s x%d", name, report.Restarts[name]))
+			}
+			lines = append(lines, i18n.T(lang, "digest.restarts", strings.Join(parts, ", ")))
+		}
+	}
+	return lines
+}
+
+// maxAlertLines limits how many individual alert events are listed.
+const maxAlertLines = 10
+
+func alertLines(lang string, events []alerts.Event, loc *time.Location) []string {
+	fired, resolved := 0, 0
+	for _, ev := range events {
+		if ev.Resolved {
+			resolved++
+		} else {
+			fired++
+		}
+	}
+
+	lines := []string{i18n.T(lang, "digest.alert_counts", fired, resolved)}
+	start := max(0, len(events)-maxAlertLines)
+	for _, ev := range events[start:] {
+		state := i18n.T(lang, "digest.fired")
+		if ev.Resolved {
+			state = i18n.T(lang, "digest.resolved")
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s", ev.At.In(loc).Format("02/01 15:04"), ev.Key, state))
+	}
+	return lines
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,140 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/app"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/revanced"
+	"serverbot/internal/testutil"
+)
+
+type scriptedRunner map[string]string
+
+func (r scriptedRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	if len(args) == 0 {
+		return "", "", nil
+	}
+	return r[args[0]], "", nil
+}
+
+func sample(cpu, mem, root float64) metrics.Stats {
+	return metrics.Stats{
+		CPU:    metrics.CPUStats{Usage: cpu, Cores: 4},
+		Memory: metrics.MemoryStats{UsedPercent: mem, Total: 1},
+		Disks:  []metrics.DiskUsage{{Mount: "/", UsedPercent: root}},
+	}
+}
+
+func TestAggregatorSummary(t *testing.T) {
+	agg := NewAggregator()
+	base := time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC)
+
+	agg.Add(base.Add(-48*time.Hour), sample(99, 99, 99)) // outside the window
+	agg.Add(base.Add(-2*time.Hour), sample(10, 40, 50))
+	agg.Add(base.Add(-90*time.Minute), sample(30, 60, 52))
+	agg.Add(base.Add(-10*time.Minute), sample(50, 50, 54))
+
+	sum := agg.Summary(base.Add(-24*time.Hour), base)
+	if sum.Samples != 3 {
+		t.Fatalf("Samples = %d, want 3", sum.Samples)
+	}
+	if sum.CPU.Min != 10 || sum.CPU.Max != 50 || sum.CPU.Avg != 30 {
+		t.Fatalf("CPU = %+v, want min 10 avg 30 max 50", sum.CPU)
+	}
+	if sum.Memory.Min != 40 || sum.Memory.Max != 60 {
+		t.Fatalf("Memory = %+v", sum.Memory)
+	}
+	if root := sum.Disks["/"]; root.Min != 50 || root.Max != 54 || root.Avg != 52 {
+		t.Fatalf("Disk / = %+v", root)
+	}
+}
+
+func TestBuildAndFormatReport(t *testing.T) {
+	now := time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC)
+	notifier := alerts.NewNotifier(time.Minute, nil)
+	_ = notifier.Fire("cpu", i18n.Raw("cpu high"))
+	_ = notifier.Resolve("cpu", nil)
+
+	svc := &Service{
+		CommandTimeout: time.Second,
+		Runner: scriptedRunner{
+			"stats":  "web\t12.5%\t3.0%\t100MiB / 2GiB\nmc-server\t80.1%\t45.0%\t4GiB / 8GiB\ndb\t1.0%\t20.0%\t900MiB / 2GiB\nidle\t0.0%\t0.1%\t1MiB / 2GiB\n",
+			"events": "mc-server\nmc-server\nweb\n",
+		},
+		Notifier:   notifier,
+		Aggregator: NewAggregator(),
+	}
+	svc.Aggregator.Add(now.Add(-time.Hour), sample(20, 30, 40))
+	svc.last = metrics.Stats{Host: metrics.HostStats{Uptime: 26 * time.Hour}}
+
+	report := svc.Build(context.Background(), Daily, now)
+	report.Alerts = notifier.Events(time.Time{})
+	report.LastBuild = &revanced.BuildResult{FinishedAt: now.Add(-time.Hour), OK: true, Published: []string{"a.apk", "b.apk"}}
+
+	if report.Restarts["mc-server"] != 2 || report.Restarts["web"] != 1 {
+		t.Fatalf("Restarts = %v", report.Restarts)
+	}
+
+	out := FormatHTML("", report, time.UTC)
+	for _, needle := range []string{
+		"<b>📰 Resumen diario</b>",
+		"13/03 08:00 - 14/03 08:00 (UTC)",
+		"⚙️ <b>CPU</b>\n• min 20.0% / media 20.0% / max 20.0%",
+		"• / min 40.0%",
+		"• Top CPU: mc-server 80.1%, web 12.5%, db 1.0%",
+		"• Top RAM: mc-server 45.0% (4GiB / 8GiB)",
+		"• Reinicios: mc-server x2, web x1",
+		"• Disparadas: 1 - Resueltas: 1",
+		"cpu resuelta",
+		"• En marcha: 26h 0m 0s",
+		"Ultimo build OK (14/03 07:00) - 2 APKs",
+	} {
+		if !strings.Contains(out, needle) {
+			t.Fatalf("digest missing %q:\n%s", needle, out)
+		}
+	}
+	if strings.Contains(out, "idle") {
+		t.Fatalf("digest should only list the top %d containers:\n%s", topContainers, out)
+	}
+}
+
+func TestFormatReportWithoutSamples(t *testing.T) {
+	out := FormatHTML("", Report{Period: Weekly}, time.UTC)
+	if !strings.Contains(out, "Resumen semanal") || !strings.Contains(out, "Sin muestras en el periodo.") {
+		t.Fatalf("unexpected empty digest:\n%s", out)
+	}
+}
+
+func TestSendUsesEachChatLanguage(t *testing.T) {
+	languages, err := i18n.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := languages.Set(2, "en"); err != nil {
+		t.Fatal(err)
+	}
+	svc := &Service{
+		Config:     app.DigestConfig{ChatIDs: []int64{1, 2}},
+		Aggregator: NewAggregator(),
+		Languages:  languages,
+	}
+	bot, client := testutil.NewFakeBot()
+	svc.send(context.Background(), bot, Daily, time.Date(2025, 3, 17, 8, 0, 0, 0, time.UTC))
+
+	texts := make(map[string]string)
+	for _, req := range client.Requests() {
+		texts[req.Values.Get("chat_id")] = req.Values.Get("text")
+	}
+	if !strings.Contains(texts["1"], "Resumen diario") {
+		t.Fatalf("chat 1 digest:\n%s", texts["1"])
+	}
+	if !strings.Contains(texts["2"], "Daily summary") {
+		t.Fatalf("chat 2 digest:\n%s", texts["2"])
+	}
+}
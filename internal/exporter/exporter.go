@@ -0,0 +1,296 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/commands"
+	"serverbot/internal/metrics"
+	"serverbot/internal/revanced"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// scrapeTimeout bounds how long a scrape waits for a metrics sample when
+// the sampler has none yet.
+const scrapeTimeout = 8 * time.Second
+
+var revancedPhases = []revanced.Phase{
+	revanced.PhaseIdle,
+	revanced.PhaseResolving,
+	revanced.PhaseAwaitingAPK,
+	revanced.PhaseBuilding,
+}
+
+// Exporter serves host metrics and bot-internal counters in the Prometheus
+// text format.
+type Exporter struct {
+	Sampler  *metrics.Sampler
+	Revanced *revanced.Service
+	Logger   *log.Logger
+
+	CommandsTotal      *CounterVec
+	CommandDuration    *HistogramVec
+	TelegramSendErrors *CounterVec
+	AlertsTotal        *CounterVec
+}
+
+// New builds an Exporter. revSvc may be nil when the pipeline is disabled.
+func New(sampler *metrics.Sampler, revSvc *revanced.Service, logger *log.Logger) *Exporter {
+	return &Exporter{
+		Sampler:  sampler,
+		Revanced: revSvc,
+		Logger:   logger,
+		CommandsTotal: NewCounterVec("serverbot_commands_total",
+			"Commands dispatched by name and outcome.", "command", "outcome"),
+		CommandDuration: NewHistogramVec("serverbot_command_duration_seconds",
+			"Handler latency by command.", DefaultBuckets, "command"),
+		TelegramSendErrors: NewCounterVec("serverbot_telegram_send_errors_total",
+			"Failed Telegram Bot API requests by method.", "method"),
+		AlertsTotal: NewCounterVec("serverbot_alerts_total",
+			"Alert state changes by kind and state.", "kind", "state"),
+	}
+}
+
+// Middleware records the outcome and latency of every dispatched command.
+func (e *Exporter) Middleware() commands.Middleware {
+	return func(next commands.Handler) commands.Handler {
+		return func(ctx *commands.Context) error {
+			start := time.Now()
+			err := next(ctx)
+
+			outcome := "ok"
+			if err != nil {
+				outcome = "error"
+			}
+			e.CommandsTotal.Inc(ctx.Command, outcome)
+			e.CommandDuration.Observe(time.Since(start).Seconds(), ctx.Command)
+			return err
+		}
+	}
+}
+
+// ObserveAlert counts alert events; register it with Notifier.OnEvent. Keys
+// of agent alerts ("cpu@nas") count under their kind.
+func (e *Exporter) ObserveAlert(ev alerts.Event) {
+	kind, _, _ := strings.Cut(ev.Key, ":")
+	kind, _, _ = strings.Cut(kind, "@")
+	state := "fired"
+	if ev.Resolved {
+		state = "resolved"
+	}
+	e.AlertsTotal.Inc(kind, state)
+}
+
+// WrapClient instruments the Telegram HTTP client so failed API calls are
+// counted regardless of which package sent them.
+func (e *Exporter) WrapClient(client tgbotapi.HTTPClient) tgbotapi.HTTPClient {
+	return &countingClient{next: client, errors: e.TelegramSendErrors}
+}
+
+type countingClient struct {
+	next   tgbotapi.HTTPClient
+	errors *CounterVec
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.next.Do(req)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		c.errors.Inc(path.Base(req.URL.Path))
+	}
+	return resp, err
+}
+
+// ListenAndServe exposes /metrics on addr until ctx is cancelled.
+func (e *Exporter) ListenAndServe(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP renders every metric family.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	tw := &textWriter{w: w}
+	if e.Sampler != nil {
+		snap, ok := e.Sampler.Latest()
+		if !ok {
+			ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout)
+			var err error
+			snap, err = e.Sampler.Sample(ctx)
+			cancel()
+			if err != nil {
+				e.log("collect error: %v", err)
+			}
+		}
+		if !snap.At.IsZero() {
+			writeStats(tw, snap.Stats)
+			tw.gauge("serverbot_metrics_sample_timestamp_seconds", "Unix time of the metrics sample being served.", float64(snap.At.Unix()))
+		}
+	}
+
+	e.CommandsTotal.write(tw)
+	e.CommandDuration.write(tw)
+	e.TelegramSendErrors.write(tw)
+	e.AlertsTotal.write(tw)
+	e.writeRevanced(tw)
+
+	if tw.err != nil {
+		e.log("write error: %v", tw.err)
+	}
+}
+
+func (e *Exporter) writeRevanced(tw *textWriter) {
+	if e.Revanced == nil {
+		return
+	}
+	st, err := e.Revanced.Store.Load()
+	if err != nil {
+		e.log("revanced state error: %v", err)
+		return
+	}
+
+	tw.header("serverbot_revanced_phase", "Current ReVanced pipeline phase (1 for the active phase).", "gauge")
+	for _, phase := range revancedPhases {
+		value := 0.0
+		if st.Phase == phase {
+			value = 1
+		}
+		tw.sample("serverbot_revanced_phase", [][2]string{{"phase", string(phase)}}, value)
+	}
+}
+
+func (e *Exporter) log(format string, args ...any) {
+	if e.Logger != nil {
+		e.Logger.Printf("exporter: "+format, args...)
+	}
+}
+
+// writeStats renders a metrics snapshot as gauges.
+func writeStats(tw *textWriter, stats metrics.Stats) {
+	if stats.CPU.Cores > 0 {
+		tw.gauge("serverbot_cpu_usage_percent", "CPU usage percentage.", stats.CPU.Usage)
+		tw.gauge("serverbot_cpu_cores", "Logical CPU cores.", float64(stats.CPU.Cores))
+		tw.header("serverbot_load_average", "System load average.", "gauge")
+		tw.sample("serverbot_load_average", [][2]string{{"period", "1m"}}, stats.CPU.Load1)
+		tw.sample("serverbot_load_average", [][2]string{{"period", "5m"}}, stats.CPU.Load5)
+		tw.sample("serverbot_load_average", [][2]string{{"period", "15m"}}, stats.CPU.Load15)
+	}
+
+	if stats.Memory.Total > 0 {
+		tw.gauge("serverbot_memory_used_bytes", "Used memory in bytes.", float64(stats.Memory.Used))
+		tw.gauge("serverbot_memory_total_bytes", "Total memory in bytes.", float64(stats.Memory.Total))
+		tw.gauge("serverbot_swap_used_bytes", "Used swap in bytes.", float64(stats.Memory.SwapUsed))
+		tw.gauge("serverbot_swap_total_bytes", "Total swap in bytes.", float64(stats.Memory.SwapTotal))
+	}
+
+	if len(stats.Disks) > 0 {
+		tw.header("serverbot_disk_used_bytes", "Used bytes per monitored mount.", "gauge")
+		for _, d := range stats.Disks {
+			tw.sample("serverbot_disk_used_bytes", [][2]string{{"mount", d.Mount}}, float64(d.Used))
+		}
+		tw.header("serverbot_disk_total_bytes", "Total bytes per monitored mount.", "gauge")
+		for _, d := range stats.Disks {
+			tw.sample("serverbot_disk_total_bytes", [][2]string{{"mount", d.Mount}}, float64(d.Total))
+		}
+	}
+
+	tw.gauge("serverbot_network_transmit_bytes_per_second", "Network transmit rate.", float64(stats.Network.SentPerSec))
+	tw.gauge("serverbot_network_receive_bytes_per_second", "Network receive rate.", float64(stats.Network.ReceivedPerSec))
+	if len(stats.Network.Interfaces) > 0 {
+		tw.header("serverbot_interface_transmit_bytes_per_second", "Transmit rate per network interface.", "gauge")
+		for _, iface := range stats.Network.Interfaces {
+			tw.sample("serverbot_interface_transmit_bytes_per_second", [][2]string{{"interface", iface.Name}}, float64(iface.SentPerSec))
+		}
+		tw.header("serverbot_interface_receive_bytes_per_second", "Receive rate per network interface.", "gauge")
+		for _, iface := range stats.Network.Interfaces {
+			tw.sample("serverbot_interface_receive_bytes_per_second", [][2]string{{"interface", iface.Name}}, float64(iface.ReceivedPerSec))
+		}
+	}
+	tw.gauge("serverbot_disk_read_bytes_per_second", "Disk read rate.", float64(stats.IO.ReadPerSec))
+	tw.gauge("serverbot_disk_write_bytes_per_second", "Disk write rate.", float64(stats.IO.WritePerSec))
+
+	if len(stats.Sensors.Temperatures) > 0 {
+		tw.header("serverbot_sensor_temperature_celsius", "Hardware sensor temperature.", "gauge")
+		for _, t := range stats.Sensors.Temperatures {
+			tw.sample("serverbot_sensor_temperature_celsius", [][2]string{{"chip", t.Chip}, {"label", t.Label}}, t.Celsius)
+		}
+	}
+	if len(stats.Sensors.Fans) > 0 {
+		tw.header("serverbot_sensor_fan_rpm", "Fan speed.", "gauge")
+		for _, f := range stats.Sensors.Fans {
+			tw.sample("serverbot_sensor_fan_rpm", [][2]string{{"chip", f.Chip}, {"label", f.Label}}, f.RPM)
+		}
+	}
+	if len(stats.Sensors.Power) > 0 {
+		tw.header("serverbot_sensor_power_watts", "Hardware sensor power draw.", "gauge")
+		for _, p := range stats.Sensors.Power {
+			tw.sample("serverbot_sensor_power_watts", [][2]string{{"chip", p.Chip}, {"label", p.Label}}, p.Watts)
+		}
+	}
+
+	if len(stats.GPU) > 0 {
+		gpuGauge := func(name, help string, value func(metrics.GPUStats) (float64, bool)) {
+			tw.header(name, help, "gauge")
+			for _, gpu := range stats.GPU {
+				if v, ok := value(gpu); ok {
+					tw.sample(name, [][2]string{{"gpu", strconv.Itoa(gpu.Index)}, {"name", gpu.Name}, {"vendor", gpu.Vendor}}, v)
+				}
+			}
+		}
+		known := func(v float64) (float64, bool) { return v, v >= 0 }
+		memory := func(g metrics.GPUStats, v uint64) (float64, bool) {
+			return float64(v) / (1024 * 1024), g.MemoryTotal > 0
+		}
+		gpuGauge("serverbot_gpu_utilization_percent", "GPU utilization percentage.", func(g metrics.GPUStats) (float64, bool) { return known(g.UtilPercent) })
+		gpuGauge("serverbot_gpu_memory_used_mebibytes", "GPU memory used in MiB.", func(g metrics.GPUStats) (float64, bool) { return memory(g, g.MemoryUsed) })
+		gpuGauge("serverbot_gpu_memory_total_mebibytes", "GPU memory total in MiB.", func(g metrics.GPUStats) (float64, bool) { return memory(g, g.MemoryTotal) })
+		gpuGauge("serverbot_gpu_temperature_celsius", "GPU temperature.", func(g metrics.GPUStats) (float64, bool) { return known(g.Temperature) })
+		gpuGauge("serverbot_gpu_power_watts", "GPU power draw.", func(g metrics.GPUStats) (float64, bool) { return known(g.PowerWatts) })
+		gpuGauge("serverbot_gpu_clock_megahertz", "GPU graphics clock.", func(g metrics.GPUStats) (float64, bool) { return known(g.ClockMHz) })
+	}
+
+	if stats.Host.Uptime > 0 {
+		tw.gauge("serverbot_host_uptime_seconds", "Host uptime in seconds.", stats.Host.Uptime.Seconds())
+	}
+	tw.gauge("serverbot_collect_warnings", "Warnings raised by the last metrics collection.", float64(len(stats.Warnings)))
+	if len(stats.Sources) > 0 {
+		tw.header("serverbot_collect_source_duration_seconds", "Time each metrics source took in the last collection.", "gauge")
+		for _, src := range stats.Sources {
+			tw.sample("serverbot_collect_source_duration_seconds", [][2]string{{"source", src.Name}}, src.Duration.Seconds())
+		}
+		tw.header("serverbot_collect_source_success", "Whether each metrics source succeeded in the last collection.", "gauge")
+		for _, src := range stats.Sources {
+			value := 0.0
+			if src.Err == nil {
+				value = 1
+			}
+			tw.sample("serverbot_collect_source_success", [][2]string{{"source", src.Name}}, value)
+		}
+	}
+}
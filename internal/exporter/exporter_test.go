@@ -0,0 +1,166 @@
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/revanced"
+	"serverbot/internal/testutil"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestCounterAndHistogramText(t *testing.T) {
+	var buf bytes.Buffer
+	tw := &textWriter{w: &buf}
+
+	c := NewCounterVec("test_total", "Test counter.", "name")
+	c.Inc("a\"b")
+	c.Add(2, "a\"b")
+	c.write(tw)
+
+	h := NewHistogramVec("test_seconds", "Test histogram.", []float64{1, 0.5}, "name")
+	h.Observe(0.3, "x")
+	h.Observe(0.7, "x")
+	h.Observe(5, "x")
+	h.write(tw)
+
+	want := `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{name="a\"b"} 3
+# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{name="x",le="0.5"} 1
+test_seconds_bucket{name="x",le="1"} 2
+test_seconds_bucket{name="x",le="+Inf"} 3
+test_seconds_sum{name="x"} 6
+test_seconds_count{name="x"} 3
+`
+	if buf.String() != want {
+		t.Fatalf("text output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestMiddlewareCountsOutcomes(t *testing.T) {
+	exp := New(nil, nil, nil)
+	mw := exp.Middleware()
+
+	ok := mw(func(ctx *commands.Context) error { return nil })
+	fail := mw(func(ctx *commands.Context) error { return errors.New("boom") })
+
+	_ = ok(&commands.Context{Command: "stats"})
+	_ = ok(&commands.Context{Command: "stats"})
+	_ = fail(&commands.Context{Command: "docker"})
+
+	if got := exp.CommandsTotal.Value("stats", "ok"); got != 2 {
+		t.Fatalf("stats ok = %v, want 2", got)
+	}
+	if got := exp.CommandsTotal.Value("docker", "error"); got != 1 {
+		t.Fatalf("docker error = %v, want 1", got)
+	}
+}
+
+func TestWrapClientCountsFailedRequests(t *testing.T) {
+	exp := New(nil, nil, nil)
+	bot, _ := testutil.NewFakeBot(
+		testutil.FakeResponse{StatusCode: http.StatusOK, Body: `{"ok":true,"result":{"message_id":1}}`},
+		testutil.FakeResponse{StatusCode: http.StatusBadRequest, Body: `{"ok":false,"error_code":400,"description":"Bad Request"}`},
+	)
+	bot.Client = exp.WrapClient(bot.Client)
+
+	_, _ = bot.Send(tgbotapi.NewMessage(1, "first"))
+	_, _ = bot.Send(tgbotapi.NewMessage(1, "second"))
+
+	if got := exp.TelegramSendErrors.Value("sendMessage"); got != 1 {
+		t.Fatalf("send errors = %v, want 1", got)
+	}
+}
+
+func TestServeHTTPExposesInternalMetrics(t *testing.T) {
+	store := filepath.Join(t.TempDir(), "state.json")
+	revSvc := revanced.NewService(store, t.TempDir(), "", "", nil)
+	if err := revSvc.Store.Save(revanced.State{Phase: revanced.PhaseBuilding}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	exp := New(nil, revSvc, nil)
+	notifier := alerts.NewNotifier(time.Minute, nil)
+	notifier.OnEvent(exp.ObserveAlert)
+	_ = notifier.Fire("disk:/var", i18n.Raw("disk"))
+	_ = notifier.Resolve("disk:/var", nil)
+	exp.CommandsTotal.Inc("help", "ok")
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, needle := range []string{
+		`serverbot_commands_total{command="help",outcome="ok"} 1`,
+		`serverbot_alerts_total{kind="disk",state="fired"} 1`,
+		`serverbot_alerts_total{kind="disk",state="resolved"} 1`,
+		`serverbot_revanced_phase{phase="building"} 1`,
+		`serverbot_revanced_phase{phase="idle"} 0`,
+	} {
+		if !strings.Contains(body, needle) {
+			t.Fatalf("metrics missing %q:\n%s", needle, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	var buf bytes.Buffer
+	writeStats(&textWriter{w: &buf}, metrics.Stats{
+		CPU:    metrics.CPUStats{Usage: 12.5, Cores: 4, Load1: 0.5},
+		Memory: metrics.MemoryStats{Used: 1024, Total: 4096},
+		Disks:  []metrics.DiskUsage{{Mount: "/", Used: 10, Total: 20}},
+		Network: metrics.NetworkStats{SentPerSec: 7, Interfaces: []metrics.InterfaceStats{
+			{Name: "eth0", SentPerSec: 7, ReceivedPerSec: 3},
+		}},
+		Sensors: metrics.SensorStats{Temperatures: []metrics.TempReading{{Chip: "coretemp", Label: "Core 0", Celsius: 51}}},
+		GPU: []metrics.GPUStats{{
+			Vendor: "nvidia", Index: 0, Name: "RTX", UtilPercent: 85, MemoryUsed: 1 << 30, MemoryTotal: 2 << 30,
+			Temperature: 60, PowerWatts: -1, ClockMHz: -1,
+		}},
+		Host: metrics.HostStats{Uptime: time.Minute},
+		Sources: []metrics.SourceReport{
+			{Name: "CPU", Duration: 1500 * time.Millisecond},
+			{Name: "GPU", Duration: 6 * time.Second, Err: metrics.ErrSourceTimeout},
+		},
+	})
+
+	body := buf.String()
+	for _, needle := range []string{
+		"serverbot_cpu_usage_percent 12.5",
+		`serverbot_load_average{period="1m"} 0.5`,
+		"serverbot_memory_total_bytes 4096",
+		`serverbot_disk_used_bytes{mount="/"} 10`,
+		`serverbot_interface_receive_bytes_per_second{interface="eth0"} 3`,
+		`serverbot_sensor_temperature_celsius{chip="coretemp",label="Core 0"} 51`,
+		`serverbot_gpu_utilization_percent{gpu="0",name="RTX",vendor="nvidia"} 85`,
+		`serverbot_gpu_memory_total_mebibytes{gpu="0",name="RTX",vendor="nvidia"} 2048`,
+		"serverbot_host_uptime_seconds 60",
+		`serverbot_collect_source_duration_seconds{source="CPU"} 1.5`,
+		`serverbot_collect_source_success{source="CPU"} 1`,
+		`serverbot_collect_source_success{source="GPU"} 0`,
+	} {
+		if !strings.Contains(body, needle) {
+			t.Fatalf("stats missing %q:\n%s", needle, body)
+		}
+	}
+	if strings.Contains(body, `serverbot_gpu_power_watts{`) {
+		t.Fatalf("unknown GPU power should be skipped:\n%s", body)
+	}
+}
@@ -0,0 +1,214 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the latency buckets (seconds) used for handler timings.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// CounterVec is a monotonically increasing counter partitioned by labels.
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec declares a counter family.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*counterSeries)}
+}
+
+// Inc adds one to the series identified by labelValues.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v to the series identified by labelValues.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, ok := c.values[key]
+	if !ok {
+		series = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = series
+	}
+	series.value += v
+}
+
+// Value returns the current value of a series, mainly for tests.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if series, ok := c.values[strings.Join(labelValues, "\xff")]; ok {
+		return series.value
+	}
+	return 0
+}
+
+func (c *CounterVec) write(w *textWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.header(c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		series := c.values[key]
+		w.sample(c.name, pairs(c.labels, series.labelValues), series.value)
+	}
+}
+
+// HistogramVec tracks value distributions partitioned by labels.
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	values map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec declares a histogram family with the given upper bounds.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &HistogramVec{name: name, help: help, labels: labels, buckets: sorted, values: make(map[string]*histogramSeries)}
+}
+
+// Observe records v in the series identified by labelValues.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.values[key]
+	if !ok {
+		series = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = series
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += v
+}
+
+func (h *HistogramVec) write(w *textWriter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w.header(h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		series := h.values[key]
+		base := pairs(h.labels, series.labelValues)
+		for i, upper := range h.buckets {
+			w.sample(h.name+"_bucket", append(base, [2]string{"le", formatFloat(upper)}), float64(series.counts[i]))
+		}
+		w.sample(h.name+"_bucket", append(base, [2]string{"le", "+Inf"}), float64(series.count))
+		w.sample(h.name+"_sum", base, series.sum)
+		w.sample(h.name+"_count", base, float64(series.count))
+	}
+}
+
+// textWriter renders the Prometheus text exposition format (version 0.0.4).
+type textWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (t *textWriter) printf(format string, args ...any) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, format, args...)
+}
+
+func (t *textWriter) header(name, help, kind string) {
+	t.printf("# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, kind)
+}
+
+func (t *textWriter) sample(name string, labels [][2]string, value float64) {
+	if len(labels) == 0 {
+		t.printf("%s %s\n", name, formatFloat(value))
+		return
+	}
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", l[0], escapeLabel(l[1])))
+	}
+	t.printf("%s{%s} %s\n", name, strings.Join(parts, ","), formatFloat(value))
+}
+
+// gauge writes a single-series gauge family.
+func (t *textWriter) gauge(name, help string, value float64) {
+	t.header(name, help, "gauge")
+	t.sample(name, nil, value)
+}
+
+func pairs(names, values []string) [][2]string {
+	out := make([][2]string, 0, len(names))
+	for i, name := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		out = append(out, [2]string{name, v})
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
+
+func escapeHelp(v string) string { return helpEscaper.Replace(v) }
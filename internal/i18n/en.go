@@ -0,0 +1,451 @@
+package i18n
+
+// enMessages is the English catalog.
+var enMessages = map[string]string{
+	// Docker
+	"args.invalid":           "Invalid arguments: %s",
+	"args.escape":            "incomplete escape sequence",
+	"args.quote":             "unclosed quote",
+	"docker.error":           "Could not query Docker.",
+	"docker.empty":           "No running containers.",
+	"docker_exec.usage":      "Usage: /docker_exec <container_name> <command>",
+	"docker_exec.mc_only":    "You can only run commands in the \"mc-server\" or \"mc-server-mod\" container.",
+	"docker_exec.error":      "Could not run the command in the container.",
+	"docker_exec.done":       "Command executed.",
+	"docker_logs.usage":      "Usage: /docker_logs <container_name>",
+	"docker_logs.error":      "Could not fetch the logs.",
+	"docker_logs.empty":      "No recent logs.",
+	"logs_subscribe.usage":   "Usage: /logs_suscripcion <container_name> [duration]",
+	"docker_restart.usage":   "Usage: /docker_restart <container_name>",
+	"docker_restart.mc_only": "You can only restart the \"mc-server\" container.",
+	"docker_restart.error":   "Could not restart the container.",
+	"docker_restart.done":    "Container restarted.",
+	"docker_stats.usage":     "Usage: /docker_stats <container_name>",
+	"docker_stats.error":     "Could not fetch the container statistics.",
+	"docker_stats.empty":     "No data found for the requested container.",
+	"docker_stats.body":      "Container: %s\nCPU: %s\nMemory: %s (%s)\nNetwork: %s\nDisk: %s",
+
+	// Hosts and network diagnostics
+	"hosts.local_only":            "/%s only runs on the local server.",
+	"hosts.none":                  "No remote hosts are configured.",
+	"hosts.unknown":               "Unknown host: @%s. Available: %s",
+	"netdiag.invalid_target":      "Invalid target.",
+	"netdiag.response":            "Response",
+	"ping.unsupported":            "/ping is not available on this operating system.",
+	"ping.title":                  "<b>📶 Ping to %s</b>",
+	"ping.result":                 "Result",
+	"ping.summary":                "Sent %d - Received %d - Loss %s%%",
+	"ping.error":                  "Could not run ping.",
+	"ping.empty":                  "No response from ping.",
+	"traceroute.usage":            "Usage: /traceroute <host>",
+	"traceroute.running":          "Tracing route to %s...",
+	"traceroute.error":            "Could not run traceroute.",
+	"traceroute.no_hops":          "No hops",
+	"traceroute.title":            "<b>🛰️ Traceroute to %s</b>",
+	"traceroute.hops":             "Hops",
+	"dns.usage":                   "Usage: /dns <name> [A|AAAA|CNAME|MX|NS|TXT|PTR] [@server]",
+	"dns.invalid_name":            "Invalid name.",
+	"dns.invalid_server":          "Invalid DNS server.",
+	"dns.error":                   "Could not resolve the name.",
+	"dns.system":                  "system",
+	"dns.no_records":              "No records",
+	"dns.query":                   "Query",
+	"dns.query_line":              "Server: %s - Time: %s",
+	"http_check.usage":            "Usage: /http_check <url>",
+	"http_check.invalid_url":      "Invalid URL.",
+	"http_check.connection_error": "connection error",
+	"http_check.tls_error":        "invalid TLS certificate",
+	"http_check.down":             "[ALERT] %s is not responding (%s, %s).",
+	"http_check.status":           "Status: %d %s",
+	"http_check.latency":          "Latency: %s",
+	"http_check.redirects":        "Redirects",
+	"http_check.expires":          "Expires: %s (%s)",
+	"http_check.issuer":           "Issuer: %s",
+	"port_check.usage":            "Usage: /port_check <host:port>",
+	"port_check.invalid_target":   "Invalid target. Use host:port.",
+	"port_check.closed":           "closed or filtered",
+	"port_check.no_response":      "no response",
+	"port_check.open":             "✅ %s open (%s).",
+
+	// Processes
+	"confirm.word":       "confirm",
+	"proc.usage":         "Usage: /proc <pid>",
+	"proc.invalid_pid":   "Invalid PID.",
+	"proc.not_found":     "Process %d does not exist.",
+	"proc.error":         "Could not get the process information.",
+	"proc.protected":     "Process %s is protected.",
+	"proc.confirm":       "[ALERT] %s to process %s. Run %s to continue.",
+	"proc.action_error":  "Could not apply %s to process %s.",
+	"proc.action_done":   "%s to process %s.",
+	"proc.title":         "<b>🔎 Process %d</b>",
+	"proc.name":          "Name: %s",
+	"proc.ppid_user":     "PPID: %d - User: %s",
+	"proc.state_nice":    "State: %s - Nice: %d",
+	"proc.started":       "Started: %s",
+	"proc.general":       "General",
+	"proc.resources":     "Resources",
+	"proc.threads_files": "Threads: %d - Open files: %s",
+	"proc.container":     "Container",
+	"proc.command":       "Command",
+	"proc.children":      "Children",
+	"kill.usage":         "Usage: /kill <pid> [signal] confirm",
+	"kill.bad_signal":    "Signal not allowed. Use TERM, KILL, INT, HUP, QUIT, USR1, USR2, STOP or CONT.",
+	"kill.prompt":        "SIG%s will be sent",
+	"kill.done":          "SIG%s sent",
+	"renice.usage":       "Usage: /renice <pid> <n> confirm",
+	"renice.range":       "The priority must be between -20 and 19.",
+	"renice.prompt":      "The priority will change to %d",
+	"renice.done":        "Priority changed to %d",
+	"reboot.confirm":     "[ALERT] This command will reboot the server. Run /reboot %s to continue.",
+	"reboot.running":     "Rebooting the server...",
+	"reboot.error":       "Could not start the reboot.",
+
+	// Services and Minecraft
+	"service_status.usage":  "Usage: /service_status <service>",
+	"service_status.error":  "Could not query the service status.",
+	"service_status.empty":  "No information available for the service.",
+	"swap_mc.state_error":   "Could not read the container state.",
+	"swap_mc.none":          "Neither mc-server nor mc-server-mod is running.",
+	"swap_mc.unsupported":   "The running container does not support this operation.",
+	"swap_mc.stop_error":    "Could not stop the current server.",
+	"swap_mc.still_running": "The previous container did not stop.",
+	"swap_mc.start_error":   "Could not start the new server.",
+	"swap_mc.done":          "Stopped %s and started %s.",
+
+	// Stats
+	"stats.usage":        "Usage: /stats [cpu|mem|disk|net|gpu|all] [--json|--compact] [fresh]",
+	"stats.collecting":   "Collecting metrics...",
+	"stats.error":        "<b>Error collecting metrics:</b>\n%s",
+	"stats.no_agent":     "Host %s has no agent reporting metrics.",
+	"stats.querying":     "Querying %s...",
+	"stats.host_error":   "<b>Error collecting metrics from %s:</b>\n%s",
+	"stats.format_error": "Could not format the metrics.",
+	"stats.attached":     "Metrics attached.",
+	"stats.json_caption": "Metrics as JSON",
+	"stats.footer":       "<i>Sample taken at %s (%s ago). Use /stats fresh to refresh.</i>",
+
+	// Log subscriptions
+	"subscription.cancelled": "[ALERT] Subscription cancelled: %v",
+	"subscription.started":   "Subscribed to the logs of %s for %s.",
+	"subscription.empty":     "No recent logs for %s.",
+	"subscription.initial":   "Initial logs of %s:",
+	"subscription.ended":     "Log subscription to %s ended.",
+	"subscription.update":    "Update from %s:",
+
+	// Top
+	"top.usage":      "Usage: /top [cpu|mem|io] [N]",
+	"top.error":      "Could not get the process information.",
+	"top.empty":      "No process information was returned.",
+	"top.io_remote":  "/top io is not available on remote hosts.",
+	"top.title_host": "<b>📋 Top processes of %s by %s</b>",
+	"top.title":      "<b>📋 Top processes by %s</b>",
+	"top.io":         "IO: R %s/s - W %s/s",
+	"top.user":       "User: %s",
+	"top.container":  " - Container: %s",
+	"top.by_memory":  "memory",
+
+	// Metrics
+	"stats.title_host":          "<b>📊 %s status</b>",
+	"stats.title":               "<b>📊 Server status</b>",
+	"stats.cpu_usage":           "Usage: %.1f%%",
+	"stats.cpu_load":            "Load: %.2f / %.2f / %.2f",
+	"stats.cpu_load_ratio":      "Overall load: %.0f%%",
+	"stats.memory":              "Memory",
+	"stats.net_line":            "Network: ↑ %s/s <=> ↓ %s/s",
+	"stats.disk_io_line":        "Disk: R %s/s <=> W %s/s",
+	"stats.net_io":              "Network & IO",
+	"stats.net":                 "Network",
+	"stats.disk_io":             "Disk IO",
+	"stats.inodes":              " - inodes %.1f%%",
+	"stats.storage":             "Storage",
+	"stats.sensors":             "Sensors",
+	"stats.gpu_none":            "GPU: not available",
+	"stats.uptime_line":         "Up for: %s",
+	"stats.warnings":            "Warnings",
+	"stats.smart_nodata":        "%s: no data (%s)",
+	"stats.smart_wear":          "wear %d%%",
+	"stats.smart_failed":        "FAILED",
+	"stats.smart_reallocated":   "reallocated %d",
+	"stats.smart_pending":       "pending %d",
+	"stats.smart_uncorrectable": "uncorrectable %d",
+	"stats.smart_media_errors":  "media errors %d",
+	"stats.fans":                "Fans %s: %s",
+	"stats.power":               "Power %s: %s",
+	"stats.gpu_power":           "Power %.0fW",
+	"stats.gpu_clock":           "Clock %.0f MHz",
+
+	// Help
+	"auth.denied":          "Not authorized.",
+	"help.title":           "<b>Available commands</b>",
+	"help.public":          "<b>Public</b>",
+	"help.admin":           "<b>Admin only</b>",
+	"help.owner":           "<b>Owner only</b>",
+	"help.footer":          "<i>Developed by DaniMarqz - Go + Telegram API</i>",
+	"lang.name":            "English",
+	"lang.status":          "Language: <b>%s</b> (%s)\nAvailable: %s\nUsage: /lang &lt;code&gt; or /lang auto",
+	"lang.source_chat":     "chosen for this chat",
+	"lang.source_telegram": "from your Telegram language",
+	"lang.set":             "Language set to %s.",
+	"lang.auto":            "Each user's Telegram language will be used.",
+	"lang.unknown":         "Unknown language: %s. Available: %s.",
+	"lang.save_failed":     "Could not save the language.",
+
+	// Command descriptions
+	"cmd.help":             "Shows this help",
+	"cmd.stats":            "CPU, RAM, network, disk and GPU usage",
+	"cmd.top":              "Processes using the most CPU/RAM",
+	"cmd.proc":             "Details and child tree of a process",
+	"cmd.docker":           "Running containers and their status",
+	"cmd.docker_exec":      "Runs a command in a Docker container",
+	"cmd.swap_mc_server":   "Changes the active Minecraft server",
+	"cmd.docker_logs":      "Last 20 log lines of a container",
+	"cmd.logs_suscripcion": "Sends periodic log updates",
+	"cmd.docker_stats":     "Resource usage of a container",
+	"cmd.docker_restart":   "Restarts a Docker container",
+	"cmd.service_status":   "Status of a systemd service",
+	"cmd.ping":             "Connectivity test",
+	"cmd.traceroute":       "Network route to a host",
+	"cmd.dns":              "DNS lookup with an optional server",
+	"cmd.http_check":       "Status, latency, redirects and TLS of a URL",
+	"cmd.port_check":       "Checks whether a TCP port accepts connections",
+	"cmd.kill":             "Sends a signal to a process",
+	"cmd.renice":           "Changes the priority of a process",
+	"cmd.reboot":           "Reboots the server",
+	"cmd.service_start":    "Starts a systemd service",
+	"cmd.service_stop":     "Stops a systemd service",
+	"cmd.service_restart":  "Restarts a systemd service",
+	"cmd.service_enable":   "Enables a systemd service at boot",
+	"cmd.failed_units":     "systemd units in a failed state",
+	"cmd.journal":          "Journal entries of a unit",
+	"cmd.journal_follow":   "Follows the journal of a unit for a while",
+	"cmd.revanced_build":   "Starts the ReVanced build pipeline",
+	"cmd.revanced_status":  "Shows the ReVanced pipeline status",
+	"cmd.revanced_cancel":  "Cancels the ReVanced pipeline",
+	"cmd.status_page":      "Status and 24h uptime of the monitored services",
+	"cmd.certs":            "TLS certificates: issuer, SANs and days to expiry",
+	"cmd.bandwidth":        "Daily and monthly network traffic and quota usage",
+	"cmd.config":           "Configuration in use (without secrets)",
+	"cmd.reload":           "Reloads the configuration without restarting",
+	"cmd.digest":           "Daily or weekly server summary",
+	"cmd.schedule":         "Schedules commands with cron expressions (add|list|rm)",
+	"cmd.lang":             "Chat language",
+
+	// Alerts
+	"bot.unknown_command": "Unknown command.",
+	"alert.firing":        "[⚠️ ALERT] %s",
+	"alert.on_host":       "[⚠️ ALERT] [%s] %s",
+	"alert.agent_down":    "[⚠️ ALERT] Agent %s is not responding: %v",
+	"alert.agent_up":      "[✅ RECOVERED] Agent %s is responding again.",
+	"alert.cpu":           "High CPU: %.1f%% (threshold %.0f%%)",
+	"alert.memory":        "High RAM: %.1f%% (threshold %.0f%%)",
+	"alert.disk":          "Disk %s at %.1f%% (threshold %.0f%%)",
+	"alert.temp":          "High temperature: %s at %.1fºC (threshold %.0fºC)",
+
+	// Configuration
+	"reload.invalid":     "Invalid configuration, keeping the current one:\n<pre>%s</pre>",
+	"reload.done":        "Configuration reloaded: roles, disks, alerts, language and custom commands.",
+	"reload.restart":     "Require restarting the bot: %s",
+	"config.empty":       "No settings defined; everything uses the defaults.",
+	"config.origin_file": "file",
+	"config.list":        "<b>Configuration</b> (unlisted settings use their defaults)\n<pre>%s</pre>",
+
+	// Custom commands
+	"custom.invalid_value": "Invalid value for %s.\nUsage: %s",
+	"custom.usage":         "Usage: %s",
+	"custom.timeout":       "/%s exceeded the time limit (%s).",
+	"custom.exit":          "/%s exited with code %d.",
+	"custom.failed":        "Could not run /%s.",
+	"custom.done":          "/%s completed.",
+
+	// Scheduler
+	"schedule.usage":         "Usage:\n/schedule add [--chat <id>] <cron> /command [args]\n/schedule list\n/schedule rm <id>\n\nExample: /schedule add 0 8 * * * /stats",
+	"schedule.bad_chat":      "Invalid chat ID.",
+	"schedule.bad_cron":      "Invalid cron expression: %v",
+	"schedule.not_allowed":   "The /%s command cannot be scheduled.",
+	"schedule.save_failed":   "Could not save the job.",
+	"schedule.created":       "Job <b>#%d</b> created. Next run: %s",
+	"schedule.read_failed":   "Could not read the jobs.",
+	"schedule.empty":         "No scheduled jobs.",
+	"schedule.title":         "<b>Scheduled jobs</b>",
+	"schedule.next":          "• Chat %d - next: %s",
+	"schedule.rm_usage":      "Usage: /schedule rm <id>",
+	"schedule.bad_id":        "Invalid job ID.",
+	"schedule.remove_failed": "Could not remove the job.",
+	"schedule.not_found":     "Job #%d does not exist.",
+	"schedule.removed":       "Job #%d removed.",
+	"schedule.never":         "never",
+	"cron.fields":            "expected 5 fields, got %d",
+	"cron.empty":             "empty %s field",
+	"cron.bad_step":          "invalid step in %s: %q",
+	"cron.bad_range":         "invalid range in %s: %q",
+	"cron.bad_value":         "invalid value in %s: %q",
+	"cron.out_of_range":      "%s out of range (%d-%d): %d",
+	"cron.field.minute":      "minute",
+	"cron.field.hour":        "hour",
+	"cron.field.day":         "day of month",
+	"cron.field.month":       "month",
+	"cron.field.weekday":     "day of week",
+
+	// systemd
+	"service.failed_list_error": "Could not list the failed units.",
+	"service.no_failed":         "No units in a failed state.",
+	"service.failed_title":      "<b>🚨 Failed units: %d</b>",
+	"service.control_usage":     "Usage: /service_%s <service>",
+	"service.bad_name":          "Invalid service name.",
+	"service.denied":            "You are not allowed to manage %s.",
+	"service.control_failed":    "Could not run %s on %s.",
+	"service.start":             "started",
+	"service.stop":              "stopped",
+	"service.restart":           "restarted",
+	"service.enable":            "enabled",
+	"service.done":              "Service %s %s.",
+	"service.title":             "Service %s",
+	"service.state":             "State: %s (%s)",
+	"service.load":              "Load: %s",
+	"service.boot":              "At boot: %s",
+	"service.main_pid":          "Main PID: %d",
+	"service.since":             "Since: %s",
+	"journal.usage":             "Usage: /journal <unit> [--since <time>] [--priority <p>] [--grep <pattern>] [--lines <n>]",
+	"journal.bad_unit":          "Invalid unit name.",
+	"journal.bad_priority":      "Invalid priority. Use emerg, alert, crit, err, warning, notice, info, debug or 0-7.",
+	"journal.read_failed":       "Could not read the journal.",
+	"journal.empty":             "No journal entries for %s.",
+	"journal.follow_usage":      "Usage: /journal_follow <unit> [duration]",
+	"alert.unit_recovered":      "[✅ RECOVERED] Unit %s is no longer in a failed state.",
+	"alert.unit_failed":         "[⚠️ ALERT] Unit %s has failed (%s).",
+	"alert.unit_journal":        "Last journal lines:",
+
+	// Uptime
+	"uptime.unknown_kind":    "unknown type %q",
+	"uptime.loss":            "loss %.0f%%",
+	"alert.uptime_recovered": "[✅ RECOVERED] %s is responding again after %s down (%s).",
+	"uptime.title":           "<b>📡 Service status</b>",
+	"uptime.state":           "State: %s",
+	"uptime.last":            "Last check: %s - %s",
+	"uptime.pending":         "pending",
+	"uptime.down_since":      "down since %s",
+	"uptime.up":              "up",
+
+	// Certificates
+	"alert.cert_expired":  "[⚠️ ALERT] The certificate of %s has expired (%s). Issuer: %s.",
+	"certs.none":          "No certificates configured.",
+	"certs.title":         "<b>🔒 TLS certificates</b>",
+	"certs.issuer":        "Issuer: %s",
+	"certs.invalid_chain": "Invalid chain: %s",
+	"certs.more_sans":     "%s and %d more",
+
+	// Bandwidth
+	"bandwidth.out":         "outbound",
+	"bandwidth.in":          "inbound",
+	"bandwidth.both":        "inbound + outbound",
+	"alert.quota_reset":     "[✅ RECOVERED] New period %s: traffic counter reset.",
+	"alert.quota_reached":   "[⚠️ ALERT] Traffic for %s (%s) has reached %d%% of the monthly quota: %s of %s.",
+	"alert.quota_exceeded":  "[⚠️ ALERT] Traffic for %s (%s) has exceeded the monthly quota: %s of %s.",
+	"bandwidth.read_failed": "Could not read the traffic log.",
+	"bandwidth.title":       "<b>📶 Network traffic</b>",
+	"bandwidth.today":       "Today",
+	"bandwidth.month":       "Month %s",
+	"bandwidth.quota":       "Quota",
+	"bandwidth.quota_line":  "%s of %s (%.1f%%) - %s",
+	"bandwidth.recent":      "Last days",
+	"bandwidth.no_data":     "No data",
+
+	// Digest
+	"digest.weekly":       "Weekly summary",
+	"digest.daily":        "Daily summary",
+	"digest.usage":        "Usage: /digest [daily|weekly]",
+	"digest.generating":   "Generating summary...",
+	"digest.metrics":      "Metrics",
+	"digest.no_samples":   "No samples in the period.",
+	"digest.containers":   "Containers",
+	"digest.top_cpu":      "Top CPU: %s",
+	"digest.top_ram":      "Top RAM: %s",
+	"digest.alerts":       "Alerts",
+	"digest.build_failed": "Last build failed (%s): %s",
+	"digest.series":       "min %.1f%% / avg %.1f%% / max %.1f%%",
+	"digest.no_restarts":  "Restarts: none",
+	"digest.restarts":     "Restarts: %s",
+	"digest.alert_counts": "Fired: %d - Resolved: %d",
+	"digest.fired":        "fired",
+	"digest.resolved":     "resolved",
+
+	// ReVanced
+	"revanced.read_failed":       "Could not read the state",
+	"revanced.busy":              "Pipeline busy (phase: %s). Use /revanced_cancel to reset it.",
+	"revanced.save_failed":       "Could not save the state",
+	"revanced.resolving":         "Resolving the required versions...",
+	"revanced.phase":             "<b>Phase:</b> %s",
+	"revanced.started":           "<b>Started:</b> %s",
+	"revanced.error":             "<b>Error:</b> %s",
+	"revanced.failed":            "failed",
+	"revanced.last_build":        "<b>Last build:</b> %s (%s)",
+	"revanced.reset_failed":      "Could not reset the state",
+	"revanced.reset":             "Pipeline reset to idle.",
+	"revanced.timeout_empty":     "Timed out without receiving any APK. Pipeline back to idle.",
+	"revanced.timeout_build":     "60s without a new APK. Starting the build with: %s",
+	"revanced.download_failed":   "Could not download the APK: %s",
+	"revanced.read_apk_failed":   "Could not read the APK: %s",
+	"revanced.no_match":          "APK <code>%s</code> v%s does not match any required one.",
+	"revanced.merging":           "APKM <code>%s</code> v%s received. Merging with APKEditor...",
+	"revanced.copy_apkm_failed":  "Could not copy the APKM: %s",
+	"revanced.merge_failed":      "Could not merge the APKM: %s",
+	"revanced.copy_apk_failed":   "Could not copy the APK: %s",
+	"revanced.internal":          "Internal error: %s",
+	"revanced.all_received":      "All APKs received. Starting the build...",
+	"revanced.received":          "APK <code>%s</code> v%s received. (60s until a partial build)",
+	"revanced.resolve_failed":    "Resolution failed:\n<pre>%s</pre>",
+	"revanced.already_published": "Already published (same version)",
+	"revanced.all_present":       "All APKs available. Starting the build...",
+	"revanced.required":          "<b>Required APKs:</b>",
+	"revanced.present":           "Already available: %s",
+	"revanced.send_missing":      "Send the missing APKs as documents. (60s until a partial build)",
+	"revanced.building":          "Starting the build...",
+	"revanced.patching":          "Patching <b>%s</b> (%d/%d)...",
+	"revanced.compiling":         "Compiling resources (%d/%d)...",
+	"revanced.writing":           "Writing APK (%d/%d)...",
+	"revanced.aligning":          "Aligning APK (%d/%d)...",
+	"revanced.signing":           "Signing (%d/%d)...",
+	"revanced.patched":           "%d/%d apps patched...",
+	"revanced.failure":           "Failure detected (%d/%d)...",
+	"revanced.build_failed":      "Build failed:\n<pre>%s</pre>",
+	"revanced.publish_failed":    "Build succeeded but publishing failed: %s",
+	"revanced.build_ok":          "ReVanced build OK",
+
+	// Uptime
+	"uptime.tcp_open":         "TCP open",
+	"uptime.ping_unavailable": "ping not available",
+	"uptime.bad_tls":          "invalid TLS certificate",
+	"uptime.timeout":          "timeout",
+}
+
+// enPlurals holds the messages of enMessages that depend on a count.
+var enPlurals = map[string]Plural{
+	// Units
+	"days": {One: "%d day", Other: "%d days"},
+
+	// Scheduler
+	"schedule.missed": {One: "[NOTICE] Job #%d (/%s) missed %s run while the bot was stopped (last due: %s).", Other: "[NOTICE] Job #%d (/%s) missed %s runs while the bot was stopped (last due: %s)."},
+
+	// systemd
+	"journal.entries":       {One: "%d entry from %s", Other: "%d entries from %s"},
+	"alert.journal_entries": {One: "[⚠️ ALERT] %d entry with priority %s or higher in %s.", Other: "[⚠️ ALERT] %d entries with priority %s or higher in %s."},
+
+	// Uptime
+	"alert.uptime_down": {One: "[⚠️ ALERT] %s is not responding (%s): %s. %d failure in a row.", Other: "[⚠️ ALERT] %s is not responding (%s): %s. %d failures in a row."},
+	"uptime.failing":    {One: "failing (%d in a row)", Other: "failing (%d in a row)"},
+
+	// Certificates
+	"alert.cert_renewed":  {One: "[✅ RECOVERED] The certificate of %s was renewed: it expires on %s (%d day).", Other: "[✅ RECOVERED] The certificate of %s was renewed: it expires on %s (%d days)."},
+	"alert.cert_expiring": {One: "[⚠️ ALERT] The certificate of %s expires in %d day (%s). Issuer: %s.", Other: "[⚠️ ALERT] The certificate of %s expires in %d days (%s). Issuer: %s."},
+	"certs.expires":       {One: "Expires: %s (%d day)", Other: "Expires: %s (%d days)"},
+	"certs.expired":       {One: "Expired: %s (%d day ago)", Other: "Expired: %s (%d days ago)"},
+
+	// Digest
+	"digest.build_ok": {One: "Last build OK (%s) - %d APK", Other: "Last build OK (%s) - %d APKs"},
+
+	// Top
+	"top.more": {One: "... and %d more process", Other: "... and %d more processes"},
+
+	// systemd
+	"journal.skipped": {One: "[%d line skipped]", Other: "[%d lines skipped]"},
+}
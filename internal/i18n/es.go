@@ -0,0 +1,451 @@
+package i18n
+
+// esMessages is the Spanish catalog, the default language.
+var esMessages = map[string]string{
+	// Docker
+	"args.invalid":           "Argumentos invalidos: %s",
+	"args.escape":            "secuencia de escape incompleta",
+	"args.quote":             "comillas sin cerrar",
+	"docker.error":           "No se pudo consultar Docker.",
+	"docker.empty":           "No hay contenedores activos.",
+	"docker_exec.usage":      "Uso: /docker_exec <nombre_contenedor> <comando>",
+	"docker_exec.mc_only":    "Solo puedes ejecutar comando en el contenedor \"mc-server\" o \"mc-server-mod\".",
+	"docker_exec.error":      "No se pudo ejecutar el comando en el contenedor.",
+	"docker_exec.done":       "Comando ejecutado.",
+	"docker_logs.usage":      "Uso: /docker_logs <nombre_contenedor>",
+	"docker_logs.error":      "No se pudieron obtener los logs.",
+	"docker_logs.empty":      "Sin logs recientes.",
+	"logs_subscribe.usage":   "Uso: /logs_suscripcion <nombre_contenedor> [duracion]",
+	"docker_restart.usage":   "Uso: /docker_restart <nombre_contenedor>",
+	"docker_restart.mc_only": "Solo puedes reiniciar el contenedor \"mc-server\".",
+	"docker_restart.error":   "No se pudo reiniciar el contenedor.",
+	"docker_restart.done":    "Contenedor reiniciado.",
+	"docker_stats.usage":     "Uso: /docker_stats <nombre_contenedor>",
+	"docker_stats.error":     "No se pudieron obtener las estadisticas del contenedor.",
+	"docker_stats.empty":     "No se encontraron datos para el contenedor solicitado.",
+	"docker_stats.body":      "Contenedor: %s\nCPU: %s\nMemoria: %s (%s)\nRed: %s\nDisco: %s",
+
+	// Hosts and network diagnostics
+	"hosts.local_only":            "/%s solo se ejecuta en el servidor local.",
+	"hosts.none":                  "No hay hosts remotos configurados.",
+	"hosts.unknown":               "Host desconocido: @%s. Disponibles: %s",
+	"netdiag.invalid_target":      "Destino invalido.",
+	"netdiag.response":            "Respuesta",
+	"ping.unsupported":            "El comando /ping no está disponible en este sistema operativo.",
+	"ping.title":                  "<b>📶 Ping a %s</b>",
+	"ping.result":                 "Resultado",
+	"ping.summary":                "Enviados %d - Recibidos %d - Perdida %s%%",
+	"ping.error":                  "No se pudo ejecutar ping.",
+	"ping.empty":                  "No se obtuvo respuesta de ping.",
+	"traceroute.usage":            "Uso: /traceroute <host>",
+	"traceroute.running":          "Trazando ruta a %s...",
+	"traceroute.error":            "No se pudo ejecutar traceroute.",
+	"traceroute.no_hops":          "Sin saltos",
+	"traceroute.title":            "<b>🛰️ Traceroute a %s</b>",
+	"traceroute.hops":             "Saltos",
+	"dns.usage":                   "Uso: /dns <nombre> [A|AAAA|CNAME|MX|NS|TXT|PTR] [@servidor]",
+	"dns.invalid_name":            "Nombre invalido.",
+	"dns.invalid_server":          "Servidor DNS invalido.",
+	"dns.error":                   "No se pudo resolver el nombre.",
+	"dns.system":                  "sistema",
+	"dns.no_records":              "Sin registros",
+	"dns.query":                   "Consulta",
+	"dns.query_line":              "Servidor: %s - Tiempo: %s",
+	"http_check.usage":            "Uso: /http_check <url>",
+	"http_check.invalid_url":      "URL invalida.",
+	"http_check.connection_error": "error de conexion",
+	"http_check.tls_error":        "certificado TLS no valido",
+	"http_check.down":             "[ALERTA] %s no responde (%s, %s).",
+	"http_check.status":           "Estado: %d %s",
+	"http_check.latency":          "Latencia: %s",
+	"http_check.redirects":        "Redirecciones",
+	"http_check.expires":          "Caduca: %s (%s)",
+	"http_check.issuer":           "Emisor: %s",
+	"port_check.usage":            "Uso: /port_check <host:puerto>",
+	"port_check.invalid_target":   "Destino invalido. Usa host:puerto.",
+	"port_check.closed":           "cerrado o filtrado",
+	"port_check.no_response":      "sin respuesta",
+	"port_check.open":             "✅ %s abierto (%s).",
+
+	// Processes
+	"confirm.word":       "confirmar",
+	"proc.usage":         "Uso: /proc <pid>",
+	"proc.invalid_pid":   "PID invalido.",
+	"proc.not_found":     "No existe el proceso %d.",
+	"proc.error":         "No se pudo obtener la información del proceso.",
+	"proc.protected":     "El proceso %s esta protegido.",
+	"proc.confirm":       "[ALERTA] %s al proceso %s. Ejecuta %s para continuar.",
+	"proc.action_error":  "No se pudo aplicar %s al proceso %s.",
+	"proc.action_done":   "%s al proceso %s.",
+	"proc.title":         "<b>🔎 Proceso %d</b>",
+	"proc.name":          "Nombre: %s",
+	"proc.ppid_user":     "PPID: %d - Usuario: %s",
+	"proc.state_nice":    "Estado: %s - Nice: %d",
+	"proc.started":       "Inicio: %s",
+	"proc.general":       "General",
+	"proc.resources":     "Recursos",
+	"proc.threads_files": "Hilos: %d - Ficheros abiertos: %s",
+	"proc.container":     "Contenedor",
+	"proc.command":       "Comando",
+	"proc.children":      "Hijos",
+	"kill.usage":         "Uso: /kill <pid> [senal] confirmar",
+	"kill.bad_signal":    "Senal no permitida. Usa TERM, KILL, INT, HUP, QUIT, USR1, USR2, STOP o CONT.",
+	"kill.prompt":        "Se enviara SIG%s",
+	"kill.done":          "SIG%s enviada",
+	"renice.usage":       "Uso: /renice <pid> <n> confirmar",
+	"renice.range":       "La prioridad debe estar entre -20 y 19.",
+	"renice.prompt":      "Se cambiara la prioridad a %d",
+	"renice.done":        "Prioridad cambiada a %d",
+	"reboot.confirm":     "[ALERTA] Este comando reiniciara el servidor. Ejecuta /reboot %s para continuar.",
+	"reboot.running":     "Reiniciando servidor...",
+	"reboot.error":       "No se pudo iniciar el reinicio.",
+
+	// Services and Minecraft
+	"service_status.usage":  "Uso: /service_status <servicio>",
+	"service_status.error":  "No se pudo consultar el estado del servicio.",
+	"service_status.empty":  "Sin informacion disponible para el servicio.",
+	"swap_mc.state_error":   "No se pudo leer el estado de los contenedores.",
+	"swap_mc.none":          "No se detecta mc-server ni mc-server-mod en ejecución.",
+	"swap_mc.unsupported":   "El contenedor activo no es compatible con esta operación.",
+	"swap_mc.stop_error":    "No se pudo detener el servidor actual.",
+	"swap_mc.still_running": "No se detuvo el contenedor anterior.",
+	"swap_mc.start_error":   "No se pudo iniciar el nuevo servidor.",
+	"swap_mc.done":          "Se detuvo %s y se inició %s.",
+
+	// Stats
+	"stats.usage":        "Uso: /stats [cpu|mem|disk|net|gpu|all] [--json|--compact] [fresh]",
+	"stats.collecting":   "Recopilando metricas...",
+	"stats.error":        "<b>Error al obtener metricas:</b>\n%s",
+	"stats.no_agent":     "El host %s no tiene un agente que informe metricas.",
+	"stats.querying":     "Consultando %s...",
+	"stats.host_error":   "<b>Error al obtener metricas de %s:</b>\n%s",
+	"stats.format_error": "No se pudieron formatear las metricas.",
+	"stats.attached":     "Metricas en el adjunto.",
+	"stats.json_caption": "Metricas en JSON",
+	"stats.footer":       "<i>Muestra de las %s (hace %s). Usa /stats fresh para actualizar.</i>",
+
+	// Log subscriptions
+	"subscription.cancelled": "[ALERTA] Suscripcion cancelada: %v",
+	"subscription.started":   "Suscripcion iniciada a los logs de %s durante %s.",
+	"subscription.empty":     "Sin logs recientes para %s.",
+	"subscription.initial":   "Logs iniciales de %s:",
+	"subscription.ended":     "Fin de la suscripcion a logs de %s.",
+	"subscription.update":    "Actualizacion de %s:",
+
+	// Top
+	"top.usage":      "Uso: /top [cpu|mem|io] [N]",
+	"top.error":      "No se pudo obtener la información de procesos.",
+	"top.empty":      "No se obtuvo información de procesos.",
+	"top.io_remote":  "/top io no esta disponible en hosts remotos.",
+	"top.title_host": "<b>📋 Top procesos de %s por %s</b>",
+	"top.title":      "<b>📋 Top procesos por %s</b>",
+	"top.io":         "IO: L %s/s - E %s/s",
+	"top.user":       "Usuario: %s",
+	"top.container":  " - Contenedor: %s",
+	"top.by_memory":  "memoria",
+
+	// Metrics
+	"stats.title_host":          "<b>📊 Estado de %s</b>",
+	"stats.title":               "<b>📊 Estado del Servidor</b>",
+	"stats.cpu_usage":           "Uso: %.1f%%",
+	"stats.cpu_load":            "Carga: %.2f / %.2f / %.2f",
+	"stats.cpu_load_ratio":      "Uso global: %.0f%%",
+	"stats.memory":              "Memoria",
+	"stats.net_line":            "Red: ↑ %s/s <=> ↓ %s/s",
+	"stats.disk_io_line":        "Disco: R %s/s <=> W %s/s",
+	"stats.net_io":              "Red & IO",
+	"stats.net":                 "Red",
+	"stats.disk_io":             "Disco IO",
+	"stats.inodes":              " - inodos %.1f%%",
+	"stats.storage":             "Almacenamiento",
+	"stats.sensors":             "Sensores",
+	"stats.gpu_none":            "GPU: no disponible",
+	"stats.uptime_line":         "En marcha: %s",
+	"stats.warnings":            "Advertencias",
+	"stats.smart_nodata":        "%s: sin datos (%s)",
+	"stats.smart_wear":          "desgaste %d%%",
+	"stats.smart_failed":        "FALLO",
+	"stats.smart_reallocated":   "reasignados %d",
+	"stats.smart_pending":       "pendientes %d",
+	"stats.smart_uncorrectable": "incorregibles %d",
+	"stats.smart_media_errors":  "errores de medio %d",
+	"stats.fans":                "Ventiladores %s: %s",
+	"stats.power":               "Potencia %s: %s",
+	"stats.gpu_power":           "Potencia %.0fW",
+	"stats.gpu_clock":           "Reloj %.0f MHz",
+
+	// Help
+	"auth.denied":          "No autorizado.",
+	"help.title":           "<b>Comandos disponibles</b>",
+	"help.public":          "<b>Publicos</b>",
+	"help.admin":           "<b>Solo administrador</b>",
+	"help.owner":           "<b>Solo propietario</b>",
+	"help.footer":          "<i>Desarrollado por DaniMarqz - Go + Telegram API</i>",
+	"lang.name":            "Espanol",
+	"lang.status":          "Idioma: <b>%s</b> (%s)\nDisponibles: %s\nUso: /lang &lt;codigo&gt; o /lang auto",
+	"lang.source_chat":     "elegido en este chat",
+	"lang.source_telegram": "segun tu idioma de Telegram",
+	"lang.set":             "Idioma cambiado a %s.",
+	"lang.auto":            "Se usara el idioma de Telegram de cada usuario.",
+	"lang.unknown":         "Idioma desconocido: %s. Disponibles: %s.",
+	"lang.save_failed":     "No se pudo guardar el idioma.",
+
+	// Command descriptions
+	"cmd.help":             "Muestra esta ayuda",
+	"cmd.stats":            "Uso de CPU, RAM, red, discos y GPU",
+	"cmd.top":              "Procesos con mayor uso de CPU/RAM",
+	"cmd.proc":             "Detalle y arbol de hijos de un proceso",
+	"cmd.docker":           "Contenedores activos y estado",
+	"cmd.docker_exec":      "Ejecuta un comando en un contenedor Docker",
+	"cmd.swap_mc_server":   "Modifica el servidor de minecraft en activo",
+	"cmd.docker_logs":      "Ultimas 20 lineas del log de un contenedor",
+	"cmd.logs_suscripcion": "Envia actualizaciones periodicas de logs",
+	"cmd.docker_stats":     "Uso de recursos de un contenedor",
+	"cmd.docker_restart":   "Reinicia un contenedor Docker",
+	"cmd.service_status":   "Estado de un servicio systemd",
+	"cmd.ping":             "Prueba de conectividad",
+	"cmd.traceroute":       "Ruta de red hasta un host",
+	"cmd.dns":              "Consulta DNS con servidor opcional",
+	"cmd.http_check":       "Estado, latencia, redirecciones y TLS de una URL",
+	"cmd.port_check":       "Comprueba si un puerto TCP acepta conexiones",
+	"cmd.kill":             "Envia una senal a un proceso",
+	"cmd.renice":           "Cambia la prioridad de un proceso",
+	"cmd.reboot":           "Reinicia el servidor",
+	"cmd.service_start":    "Inicia un servicio systemd",
+	"cmd.service_stop":     "Detiene un servicio systemd",
+	"cmd.service_restart":  "Reinicia un servicio systemd",
+	"cmd.service_enable":   "Habilita un servicio systemd en el arranque",
+	"cmd.failed_units":     "Unidades systemd en estado fallido",
+	"cmd.journal":          "Entradas del journal de una unidad",
+	"cmd.journal_follow":   "Sigue el journal de una unidad durante un tiempo",
+	"cmd.revanced_build":   "Inicia el pipeline de build de ReVanced",
+	"cmd.revanced_status":  "Muestra el estado del pipeline de ReVanced",
+	"cmd.revanced_cancel":  "Cancela el pipeline de ReVanced",
+	"cmd.status_page":      "Estado y uptime 24h de los servicios vigilados",
+	"cmd.certs":            "Certificados TLS: emisor, SAN y dias hasta caducar",
+	"cmd.bandwidth":        "Trafico de red diario y mensual y uso de la cuota",
+	"cmd.config":           "Configuracion en uso (sin secretos)",
+	"cmd.reload":           "Recarga la configuracion sin reiniciar",
+	"cmd.digest":           "Resumen diario o semanal del servidor",
+	"cmd.schedule":         "Programa comandos con expresiones cron (add|list|rm)",
+	"cmd.lang":             "Idioma del chat",
+
+	// Alerts
+	"bot.unknown_command": "Comando no reconocido.",
+	"alert.firing":        "[⚠️ ALERTA] %s",
+	"alert.on_host":       "[⚠️ ALERTA] [%s] %s",
+	"alert.agent_down":    "[⚠️ ALERTA] El agente %s no responde: %v",
+	"alert.agent_up":      "[✅ RECUPERADO] El agente %s vuelve a responder.",
+	"alert.cpu":           "CPU alta: %.1f%% (umbral %.0f%%)",
+	"alert.memory":        "RAM alta: %.1f%% (umbral %.0f%%)",
+	"alert.disk":          "Disco %s al %.1f%% (umbral %.0f%%)",
+	"alert.temp":          "Temperatura alta: %s a %.1fºC (umbral %.0fºC)",
+
+	// Configuration
+	"reload.invalid":     "Configuracion no valida, se mantiene la actual:\n<pre>%s</pre>",
+	"reload.done":        "Configuracion recargada: roles, discos, alertas, idioma y comandos personalizados.",
+	"reload.restart":     "Requieren reiniciar el bot: %s",
+	"config.empty":       "No hay ajustes definidos; todo usa los valores por defecto.",
+	"config.origin_file": "archivo",
+	"config.list":        "<b>Configuracion</b> (los ajustes no listados usan su valor por defecto)\n<pre>%s</pre>",
+
+	// Custom commands
+	"custom.invalid_value": "Valor invalido para %s.\nUso: %s",
+	"custom.usage":         "Uso: %s",
+	"custom.timeout":       "/%s supero el tiempo limite (%s).",
+	"custom.exit":          "/%s termino con codigo %d.",
+	"custom.failed":        "No se pudo ejecutar /%s.",
+	"custom.done":          "/%s completado.",
+
+	// Scheduler
+	"schedule.usage":         "Uso:\n/schedule add [--chat <id>] <cron> /comando [args]\n/schedule list\n/schedule rm <id>\n\nEjemplo: /schedule add 0 8 * * * /stats",
+	"schedule.bad_chat":      "ID de chat invalido.",
+	"schedule.bad_cron":      "Expresion cron invalida: %v",
+	"schedule.not_allowed":   "El comando /%s no se puede programar.",
+	"schedule.save_failed":   "No se pudo guardar la tarea.",
+	"schedule.created":       "Tarea <b>#%d</b> creada. Proxima ejecucion: %s",
+	"schedule.read_failed":   "No se pudieron leer las tareas.",
+	"schedule.empty":         "No hay tareas programadas.",
+	"schedule.title":         "<b>Tareas programadas</b>",
+	"schedule.next":          "• Chat %d - proxima: %s",
+	"schedule.rm_usage":      "Uso: /schedule rm <id>",
+	"schedule.bad_id":        "ID de tarea invalido.",
+	"schedule.remove_failed": "No se pudo eliminar la tarea.",
+	"schedule.not_found":     "No existe la tarea #%d.",
+	"schedule.removed":       "Tarea #%d eliminada.",
+	"schedule.never":         "nunca",
+	"cron.fields":            "se esperaban 5 campos, hay %d",
+	"cron.empty":             "campo %s vacio",
+	"cron.bad_step":          "paso invalido en %s: %q",
+	"cron.bad_range":         "rango invalido en %s: %q",
+	"cron.bad_value":         "valor invalido en %s: %q",
+	"cron.out_of_range":      "%s fuera de rango (%d-%d): %d",
+	"cron.field.minute":      "minuto",
+	"cron.field.hour":        "hora",
+	"cron.field.day":         "dia",
+	"cron.field.month":       "mes",
+	"cron.field.weekday":     "dia de la semana",
+
+	// systemd
+	"service.failed_list_error": "No se pudieron listar las unidades fallidas.",
+	"service.no_failed":         "No hay unidades en estado fallido.",
+	"service.failed_title":      "<b>🚨 Unidades fallidas: %d</b>",
+	"service.control_usage":     "Uso: /service_%s <servicio>",
+	"service.bad_name":          "Nombre de servicio invalido.",
+	"service.denied":            "No tienes permiso para gestionar %s.",
+	"service.control_failed":    "No se pudo ejecutar %s sobre %s.",
+	"service.start":             "iniciado",
+	"service.stop":              "detenido",
+	"service.restart":           "reiniciado",
+	"service.enable":            "habilitado",
+	"service.done":              "Servicio %s %s.",
+	"service.title":             "Servicio %s",
+	"service.state":             "Estado: %s (%s)",
+	"service.load":              "Carga: %s",
+	"service.boot":              "Arranque: %s",
+	"service.main_pid":          "PID principal: %d",
+	"service.since":             "Desde: %s",
+	"journal.usage":             "Uso: /journal <unidad> [--since <tiempo>] [--priority <p>] [--grep <patron>] [--lines <n>]",
+	"journal.bad_unit":          "Nombre de unidad invalido.",
+	"journal.bad_priority":      "Prioridad invalida. Usa emerg, alert, crit, err, warning, notice, info, debug o 0-7.",
+	"journal.read_failed":       "No se pudo leer el journal.",
+	"journal.empty":             "Sin entradas en el journal para %s.",
+	"journal.follow_usage":      "Uso: /journal_follow <unidad> [duracion]",
+	"alert.unit_recovered":      "[✅ RECUPERADO] La unidad %s ya no esta en estado fallido.",
+	"alert.unit_failed":         "[⚠️ ALERTA] La unidad %s ha fallado (%s).",
+	"alert.unit_journal":        "Ultimas lineas del journal:",
+
+	// Uptime
+	"uptime.unknown_kind":    "tipo desconocido %q",
+	"uptime.loss":            "perdida %.0f%%",
+	"alert.uptime_recovered": "[✅ RECUPERADO] %s vuelve a responder tras %s caido (%s).",
+	"uptime.title":           "<b>📡 Estado de servicios</b>",
+	"uptime.state":           "Estado: %s",
+	"uptime.last":            "Ultima comprobacion: %s - %s",
+	"uptime.pending":         "pendiente",
+	"uptime.down_since":      "caido desde %s",
+	"uptime.up":              "operativo",
+
+	// Certificates
+	"alert.cert_expired":  "[⚠️ ALERTA] El certificado de %s ha caducado (%s). Emisor: %s.",
+	"certs.none":          "No hay certificados configurados.",
+	"certs.title":         "<b>🔒 Certificados TLS</b>",
+	"certs.issuer":        "Emisor: %s",
+	"certs.invalid_chain": "Cadena no valida: %s",
+	"certs.more_sans":     "%s y %d mas",
+
+	// Bandwidth
+	"bandwidth.out":         "salida",
+	"bandwidth.in":          "entrada",
+	"bandwidth.both":        "entrada + salida",
+	"alert.quota_reset":     "[✅ RECUPERADO] Nuevo periodo %s: contador de trafico reiniciado.",
+	"alert.quota_reached":   "[⚠️ ALERTA] El trafico de %s (%s) ha alcanzado el %d%% de la cuota mensual: %s de %s.",
+	"alert.quota_exceeded":  "[⚠️ ALERTA] El trafico de %s (%s) ha superado la cuota mensual: %s de %s.",
+	"bandwidth.read_failed": "No se pudo leer el registro de trafico.",
+	"bandwidth.title":       "<b>📶 Trafico de red</b>",
+	"bandwidth.today":       "Hoy",
+	"bandwidth.month":       "Mes %s",
+	"bandwidth.quota":       "Cuota",
+	"bandwidth.quota_line":  "%s de %s (%.1f%%) - %s",
+	"bandwidth.recent":      "Ultimos dias",
+	"bandwidth.no_data":     "Sin datos",
+
+	// Digest
+	"digest.weekly":       "Resumen semanal",
+	"digest.daily":        "Resumen diario",
+	"digest.usage":        "Uso: /digest [diario|semanal]",
+	"digest.generating":   "Generando resumen...",
+	"digest.metrics":      "Metricas",
+	"digest.no_samples":   "Sin muestras en el periodo.",
+	"digest.containers":   "Contenedores",
+	"digest.top_cpu":      "Top CPU: %s",
+	"digest.top_ram":      "Top RAM: %s",
+	"digest.alerts":       "Alertas",
+	"digest.build_failed": "Ultimo build fallido (%s): %s",
+	"digest.series":       "min %.1f%% / media %.1f%% / max %.1f%%",
+	"digest.no_restarts":  "Reinicios: ninguno",
+	"digest.restarts":     "Reinicios: %s",
+	"digest.alert_counts": "Disparadas: %d - Resueltas: %d",
+	"digest.fired":        "disparada",
+	"digest.resolved":     "resuelta",
+
+	// ReVanced
+	"revanced.read_failed":       "Error al leer estado",
+	"revanced.busy":              "Pipeline ocupado (fase: %s). Usa /revanced_cancel para reiniciar.",
+	"revanced.save_failed":       "Error al guardar estado",
+	"revanced.resolving":         "Resolviendo versiones requeridas...",
+	"revanced.phase":             "<b>Fase:</b> %s",
+	"revanced.started":           "<b>Inicio:</b> %s",
+	"revanced.error":             "<b>Error:</b> %s",
+	"revanced.failed":            "fallido",
+	"revanced.last_build":        "<b>Ultimo build:</b> %s (%s)",
+	"revanced.reset_failed":      "Error al resetear estado",
+	"revanced.reset":             "Pipeline reiniciado a idle.",
+	"revanced.timeout_empty":     "Timeout, no recibi ningun APK. Pipeline vuelve a idle.",
+	"revanced.timeout_build":     "60s sin APK nuevo. Arranco build con: %s",
+	"revanced.download_failed":   "Error al descargar APK: %s",
+	"revanced.read_apk_failed":   "No se pudo leer el APK: %s",
+	"revanced.no_match":          "APK <code>%s</code> v%s no coincide con ninguno requerido.",
+	"revanced.merging":           "APKM <code>%s</code> v%s recibido. Mergeando con APKEditor...",
+	"revanced.copy_apkm_failed":  "Error copiando APKM: %s",
+	"revanced.merge_failed":      "Error mergeando APKM: %s",
+	"revanced.copy_apk_failed":   "Error copiando APK: %s",
+	"revanced.internal":          "Error interno: %s",
+	"revanced.all_received":      "Todos los APKs recibidos. Iniciando build...",
+	"revanced.received":          "APK <code>%s</code> v%s recibido. (60s para build parcial)",
+	"revanced.resolve_failed":    "Error en resolución:\n<pre>%s</pre>",
+	"revanced.already_published": "Ya publicado (misma version)",
+	"revanced.all_present":       "Todos los APKs disponibles. Iniciando build...",
+	"revanced.required":          "<b>APKs requeridos:</b>",
+	"revanced.present":           "Ya disponibles: %s",
+	"revanced.send_missing":      "Envia los APKs faltantes como documentos. (60s para build parcial)",
+	"revanced.building":          "Iniciando build...",
+	"revanced.patching":          "Parcheando <b>%s</b> (%d/%d)...",
+	"revanced.compiling":         "Compilando recursos (%d/%d)...",
+	"revanced.writing":           "Escribiendo APK (%d/%d)...",
+	"revanced.aligning":          "Alineando APK (%d/%d)...",
+	"revanced.signing":           "Firmando (%d/%d)...",
+	"revanced.patched":           "%d/%d apps parcheadas...",
+	"revanced.failure":           "Fallo detectado (%d/%d)...",
+	"revanced.build_failed":      "Build fallido:\n<pre>%s</pre>",
+	"revanced.publish_failed":    "Build exitoso pero error al publicar: %s",
+	"revanced.build_ok":          "Build ReVanced OK",
+
+	// Uptime
+	"uptime.tcp_open":         "TCP abierto",
+	"uptime.ping_unavailable": "ping no disponible",
+	"uptime.bad_tls":          "certificado TLS no valido",
+	"uptime.timeout":          "sin respuesta a tiempo",
+}
+
+// esPlurals holds the messages of esMessages that depend on a count.
+var esPlurals = map[string]Plural{
+	// Units
+	"days": {One: "%d dia", Other: "%d dias"},
+
+	// Scheduler
+	"schedule.missed": {One: "[AVISO] La tarea #%d (/%s) no se ejecuto %s vez mientras el bot estaba detenido (ultima prevista: %s).", Other: "[AVISO] La tarea #%d (/%s) no se ejecuto %s veces mientras el bot estaba detenido (ultima prevista: %s)."},
+
+	// systemd
+	"journal.entries":       {One: "%d entrada de %s", Other: "%d entradas de %s"},
+	"alert.journal_entries": {One: "[⚠️ ALERTA] %d entrada con prioridad %s o superior en %s.", Other: "[⚠️ ALERTA] %d entradas con prioridad %s o superior en %s."},
+
+	// Uptime
+	"alert.uptime_down": {One: "[⚠️ ALERTA] %s no responde (%s): %s. %d fallo seguido.", Other: "[⚠️ ALERTA] %s no responde (%s): %s. %d fallos seguidos."},
+	"uptime.failing":    {One: "fallando (%d seguido)", Other: "fallando (%d seguidos)"},
+
+	// Certificates
+	"alert.cert_renewed":  {One: "[✅ RECUPERADO] El certificado de %s se ha renovado: caduca el %s (%d dia).", Other: "[✅ RECUPERADO] El certificado de %s se ha renovado: caduca el %s (%d dias)."},
+	"alert.cert_expiring": {One: "[⚠️ ALERTA] El certificado de %s caduca en %d dia (%s). Emisor: %s.", Other: "[⚠️ ALERTA] El certificado de %s caduca en %d dias (%s). Emisor: %s."},
+	"certs.expires":       {One: "Caduca: %s (%d dia)", Other: "Caduca: %s (%d dias)"},
+	"certs.expired":       {One: "Caducado: %s (hace %d dia)", Other: "Caducado: %s (hace %d dias)"},
+
+	// Digest
+	"digest.build_ok": {One: "Ultimo build OK (%s) - %d APK", Other: "Ultimo build OK (%s) - %d APKs"},
+
+	// Top
+	"top.more": {One: "... y %d proceso mas", Other: "... y %d procesos mas"},
+
+	// systemd
+	"journal.skipped": {One: "[%d linea omitida]", Other: "[%d lineas omitidas]"},
+}
@@ -0,0 +1,147 @@
+// Package i18n holds the translations of every user-facing message. Handlers
+// look messages up by key in the language of the chat; Spanish is the
+// default and English is also shipped. A key missing from a catalog falls
+// back to the key itself, which is how free-form text such as custom command
+// descriptions passes through unchanged.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when neither the chat nor the configuration
+// chooses one.
+const DefaultLanguage = "es"
+
+// Plural holds the forms of a message that depends on a count. Spanish and
+// English only distinguish one from everything else.
+type Plural struct {
+	One   string
+	Other string
+}
+
+type catalog struct {
+	messages map[string]string
+	plurals  map[string]Plural
+}
+
+var catalogs = map[string]catalog{
+	"es": {messages: esMessages, plurals: esPlurals},
+	"en": {messages: enMessages, plurals: enPlurals},
+}
+
+var (
+	mu       sync.RWMutex
+	fallback = DefaultLanguage
+)
+
+// SetDefault sets the language of chats that chose none and of messages not
+// sent in reply to anyone, such as alerts and digests.
+func SetDefault(lang string) error {
+	if !Supported(lang) {
+		return fmt.Errorf("unsupported language %q: want one of %s", lang, strings.Join(Languages(), ", "))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	fallback = lang
+	return nil
+}
+
+// Default returns the language set with SetDefault.
+func Default() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return fallback
+}
+
+// Languages lists the shipped languages, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Supported reports whether lang has a catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Match maps a Telegram language_code such as "en-US" to a shipped language.
+func Match(code string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	return code, Supported(code)
+}
+
+func lookup(lang string) catalog {
+	if c, ok := catalogs[lang]; ok {
+		return c
+	}
+	return catalogs[Default()]
+}
+
+// T returns the message key in lang, formatted with args. An unknown
+// language uses the default one.
+func T(lang, key string, args ...any) string {
+	msg, ok := lookup(lang).messages[key]
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N returns the form of the plural message key that suits n, formatted with
+// args, or with n alone when there are none.
+func N(lang, key string, n int, args ...any) string {
+	p, ok := lookup(lang).plurals[key]
+	if !ok {
+		return key
+	}
+	msg := p.Other
+	if n == 1 {
+		msg = p.One
+	}
+	if len(args) == 0 {
+		args = []any{n}
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Text is a message rendered once its language is known, such as an alert
+// that is built before the chat it goes to is looked up.
+type Text func(lang string) string
+
+// Tf is the Text of T(lang, key, args...).
+func Tf(key string, args ...any) Text {
+	return func(lang string) string { return T(lang, key, args...) }
+}
+
+// Nf is the Text of N(lang, key, n, args...).
+func Nf(key string, n int, args ...any) Text {
+	return func(lang string) string { return N(lang, key, n, args...) }
+}
+
+// Raw is a Text that reads s in every language, such as command output.
+func Raw(s string) Text {
+	return func(string) string { return s }
+}
+
+// Has reports whether key is a message or plural of the default catalog.
+func Has(key string) bool {
+	c := catalogs[DefaultLanguage]
+	_, msg := c.messages[key]
+	_, plural := c.plurals[key]
+	return msg || plural
+}
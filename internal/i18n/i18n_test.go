@@ -0,0 +1,167 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// verbPattern matches the fmt verbs of a message, ignoring "%%".
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z]`)
+
+func verbs(msg string) []string {
+	return verbPattern.FindAllString(strings.ReplaceAll(msg, "%%", ""), -1)
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	base := catalogs[DefaultLanguage]
+	for lang, c := range catalogs {
+		for key, want := range base.messages {
+			got, ok := c.messages[key]
+			if !ok || got == "" {
+				t.Errorf("%s: message %q is not translated", lang, key)
+				continue
+			}
+			if !slices.Equal(verbs(got), verbs(want)) {
+				t.Errorf("%s: message %q has verbs %v, want %v", lang, key, verbs(got), verbs(want))
+			}
+		}
+		for key := range c.messages {
+			if _, ok := base.messages[key]; !ok {
+				t.Errorf("%s: message %q is missing from %s", lang, key, DefaultLanguage)
+			}
+		}
+
+		for key, want := range base.plurals {
+			got, ok := c.plurals[key]
+			if !ok || got.One == "" || got.Other == "" {
+				t.Errorf("%s: plural %q is not translated", lang, key)
+				continue
+			}
+			for _, form := range []string{got.One, got.Other} {
+				if !slices.Equal(verbs(form), verbs(want.Other)) {
+					t.Errorf("%s: plural %q form %q has verbs %v, want %v", lang, key, form, verbs(form), verbs(want.Other))
+				}
+			}
+		}
+		for key := range c.plurals {
+			if _, ok := base.plurals[key]; !ok {
+				t.Errorf("%s: plural %q is missing from %s", lang, key, DefaultLanguage)
+			}
+		}
+	}
+}
+
+// keyPattern matches the literal key of a T, N, Tf or Nf call, whether on
+// the package or on a command context. Keys built at runtime, such as
+// "cron.field."+name, are left out.
+var keyPattern = regexp.MustCompile(`(?:\bi18n\.[TN]\(\w+,\s*|\bi18n\.[TN]f\(|\b(?:ctx|c)\.[TN]\()"([^"]+)"\s*[,)]`)
+
+func TestUsedKeysExist(t *testing.T) {
+	root := filepath.Join("..", "..")
+	found := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range keyPattern.FindAllSubmatch(src, -1) {
+			found++
+			if key := string(m[1]); !Has(key) {
+				t.Errorf("%s: key %q is not in the catalog", path, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == 0 {
+		t.Fatal("no keys found; is the pattern out of date?")
+	}
+}
+
+func TestTAndN(t *testing.T) {
+	if got := T("en", "auth.denied"); got != "Not authorized." {
+		t.Fatalf("T(en) = %q", got)
+	}
+	if got := T("fr", "auth.denied"); got != "No autorizado." {
+		t.Fatalf("T(fr) = %q, want the default language", got)
+	}
+	if got := T("en", "Free text"); got != "Free text" {
+		t.Fatalf("T(unknown key) = %q", got)
+	}
+	if got := N("es", "days", 1); got != "1 dia" {
+		t.Fatalf("N(1) = %q", got)
+	}
+	if got := N("en", "days", 3); got != "3 days" {
+		t.Fatalf("N(3) = %q", got)
+	}
+	if got := Tf("auth.denied")("en"); got != "Not authorized." {
+		t.Fatalf("Tf(en) = %q", got)
+	}
+	if got := Nf("days", 3)("es"); got != "3 dias" {
+		t.Fatalf("Nf(es) = %q", got)
+	}
+	if got := Raw("50%")("en"); got != "50%" {
+		t.Fatalf("Raw() = %q", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for code, want := range map[string]string{"en-US": "en", "ES": "es", "pt-br": ""} {
+		got, ok := Match(code)
+		if want == "" {
+			if ok {
+				t.Errorf("Match(%q) = %q, want no match", code, got)
+			}
+			continue
+		}
+		if !ok || got != want {
+			t.Errorf("Match(%q) = %q, %v, want %q", code, got, ok, want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "languages.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Resolve(1, "en-GB"); got != "en" {
+		t.Fatalf("Resolve with Telegram code = %q", got)
+	}
+	if err := s.Set(1, "es"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(2, "xx"); err == nil {
+		t.Fatal("Set accepted an unknown language")
+	}
+
+	reloaded, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Resolve(1, "en-GB"); got != "es" {
+		t.Fatalf("Resolve after reload = %q, want the chosen language", got)
+	}
+	if err := reloaded.Set(1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Resolve(1, "fr"); got != Default() {
+		t.Fatalf("Resolve after clearing = %q, want the default", got)
+	}
+
+	var nilStore *Store
+	if got := nilStore.Resolve(1, "en"); got != "en" {
+		t.Fatalf("nil store Resolve = %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Store remembers the language chosen with /lang in each chat. With a path
+// the choices are kept in a JSON file across restarts.
+type Store struct {
+	path string
+
+	mu    sync.Mutex
+	chats map[int64]string
+}
+
+// NewStore loads the choices saved at path. An empty path keeps them in
+// memory only; a missing file is an empty store.
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path, chats: map[int64]string{}}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read languages: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.chats); err != nil {
+		return nil, fmt.Errorf("parse languages: %w", err)
+	}
+	return s, nil
+}
+
+// Get returns the language chosen in chatID.
+func (s *Store) Get(chatID int64) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lang, ok := s.chats[chatID]
+	return lang, ok
+}
+
+// Set chooses lang for chatID; an empty lang goes back to the Telegram
+// language of each user.
+func (s *Store) Set(chatID int64, lang string) error {
+	if lang != "" && !Supported(lang) {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lang == "" {
+		delete(s.chats, chatID)
+	} else {
+		s.chats[chatID] = lang
+	}
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.chats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal languages: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("write languages: %w", err)
+	}
+	return nil
+}
+
+// Resolve picks the language of a message: the one chosen in chatID, else
+// the sender's Telegram language code when it is shipped, else the default.
+// A nil store only matches the code.
+func (s *Store) Resolve(chatID int64, code string) string {
+	if s != nil {
+		if lang, ok := s.Get(chatID); ok {
+			return lang
+		}
+	}
+	if lang, ok := Match(code); ok {
+		return lang
+	}
+	return Default()
+}
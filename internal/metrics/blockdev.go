@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sysBlockRoot is replaced in tests with a fake sysfs tree.
+var sysBlockRoot = "/sys/class/block"
+
+// physicalDevices resolves a mounted device (/dev/sda1, /dev/mapper/vg-root,
+// /dev/nvme0n1p2) to the kernel names of the whole disks behind it, following
+// partitions to their parent and device-mapper/md devices to their slaves.
+// Devices unknown to sysfs (overlay, tmpfs, network filesystems) yield nil.
+func physicalDevices(device string) []string {
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+	seen := make(map[string]bool)
+	resolveBlock(filepath.Base(device), seen, 0)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func resolveBlock(name string, out map[string]bool, depth int) {
+	// Stacks deeper than a few layers (dm on md on partitions) are not real.
+	if depth > 8 {
+		return
+	}
+	entry := filepath.Join(sysBlockRoot, name)
+	if _, err := os.Stat(entry); err != nil {
+		return
+	}
+
+	if slaves, err := os.ReadDir(filepath.Join(entry, "slaves")); err == nil && len(slaves) > 0 {
+		for _, slave := range slaves {
+			resolveBlock(slave.Name(), out, depth+1)
+		}
+		return
+	}
+
+	if _, err := os.Stat(filepath.Join(entry, "partition")); err == nil {
+		if real, err := filepath.EvalSymlinks(entry); err == nil {
+			out[filepath.Base(filepath.Dir(real))] = true
+			return
+		}
+	}
+	out[name] = true
+}
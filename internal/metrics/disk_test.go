@@ -0,0 +1,230 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// fakeSysBlock builds a sysfs-like tree: sda with partitions sda1 and sda2,
+// nvme0n1 with nvme0n1p1, and dm-0 spanning sda2 and nvme0n1p1.
+func fakeSysBlock(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	devices := filepath.Join(root, "devices")
+	class := filepath.Join(root, "class", "block")
+
+	mk := func(rel string, partition bool) string {
+		dir := filepath.Join(devices, rel)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if partition {
+			if err := os.WriteFile(filepath.Join(dir, "partition"), []byte("1\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.MkdirAll(class, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(dir, filepath.Join(class, filepath.Base(rel))); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	mk("pci0/sda", false)
+	mk("pci0/sda/sda1", true)
+	sda2 := mk("pci0/sda/sda2", true)
+	mk("pci1/nvme0n1", false)
+	nvme1 := mk("pci1/nvme0n1/nvme0n1p1", true)
+	dm := mk("virtual/dm-0", false)
+
+	slaves := filepath.Join(dm, "slaves")
+	if err := os.MkdirAll(slaves, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []string{sda2, nvme1} {
+		if err := os.Symlink(target, filepath.Join(slaves, filepath.Base(target))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prev := sysBlockRoot
+	sysBlockRoot = class
+	t.Cleanup(func() { sysBlockRoot = prev })
+	return root
+}
+
+func TestPhysicalDevices(t *testing.T) {
+	fakeSysBlock(t)
+
+	cases := map[string][]string{
+		"/dev/sda1":      {"sda"},
+		"/dev/sda":       {"sda"},
+		"/dev/nvme0n1p1": {"nvme0n1"},
+		"/dev/dm-0":      {"nvme0n1", "sda"},
+		"overlay":        {},
+		"tmpfs":          {},
+	}
+	for device, want := range cases {
+		if got := physicalDevices(device); !reflect.DeepEqual(got, want) {
+			t.Errorf("physicalDevices(%q) = %v, want %v", device, got, want)
+		}
+	}
+
+	got := targetDevices([]disk.PartitionStat{{Device: "/dev/sda1"}, {Device: "/dev/dm-0"}, {Device: "overlay"}})
+	if !reflect.DeepEqual(got, []string{"nvme0n1", "sda"}) {
+		t.Fatalf("targetDevices() = %v", got)
+	}
+}
+
+func TestDiskIORates(t *testing.T) {
+	first := map[string]disk.IOCountersStat{
+		"sda":     {ReadBytes: 1000, WriteBytes: 0, ReadCount: 10, WriteCount: 0, ReadTime: 50, WriteTime: 0, IoTime: 100},
+		"nvme0n1": {ReadBytes: 0, WriteBytes: 0, IoTime: 0},
+		"sdb":     {ReadBytes: 999},
+	}
+	second := map[string]disk.IOCountersStat{
+		"sda":     {ReadBytes: 5000, WriteBytes: 8000, ReadCount: 30, WriteCount: 20, ReadTime: 130, WriteTime: 120, IoTime: 600},
+		"nvme0n1": {ReadBytes: 0, WriteBytes: 0, IoTime: 0},
+		"sdb":     {ReadBytes: 99999},
+	}
+
+	got := diskIORates(first, second, []string{"nvme0n1", "sda"}, 2*time.Second)
+	if got.ReadPerSec != 2000 || got.WritePerSec != 4000 {
+		t.Fatalf("aggregate = %d/%d, want 2000/4000 (sdb is not a target)", got.ReadPerSec, got.WritePerSec)
+	}
+	if len(got.Devices) != 2 {
+		t.Fatalf("Devices = %+v", got.Devices)
+	}
+	sda := got.Devices[1]
+	want := DeviceIO{Name: "sda", ReadPerSec: 2000, WritePerSec: 4000, ReadIOPS: 10, WriteIOPS: 10, UtilPercent: 25, Await: 5}
+	if sda != want {
+		t.Fatalf("sda = %+v, want %+v", sda, want)
+	}
+	if idle := got.Devices[0]; idle.UtilPercent != 0 || idle.Await != 0 {
+		t.Fatalf("idle device = %+v", idle)
+	}
+}
+
+const smartctlATA = `{
+  "smartctl": {"exit_status": 0},
+  "model_name": "Samsung SSD 870 EVO 1TB",
+  "smart_status": {"passed": true},
+  "temperature": {"current": 34},
+  "power_on_time": {"hours": 12034},
+  "ata_smart_attributes": {"table": [
+    {"id": 5, "name": "Reallocated_Sector_Ct", "raw": {"value": 8}},
+    {"id": 9, "name": "Power_On_Hours", "raw": {"value": 12034}},
+    {"id": 197, "name": "Current_Pending_Sector", "raw": {"value": 0}},
+    {"id": 198, "name": "Offline_Uncorrectable", "raw": {"value": 0}}
+  ]}
+}`
+
+const smartctlNVMe = `{
+  "smartctl": {"exit_status": 0},
+  "model_name": "WD Blue SN570 1TB",
+  "smart_status": {"passed": false},
+  "temperature": {"current": 45},
+  "power_on_time": {"hours": 800},
+  "nvme_smart_health_information_log": {"percentage_used": 3, "media_errors": 2}
+}`
+
+const smartctlDenied = `{
+  "smartctl": {"exit_status": 2, "messages": [
+    {"string": "Smartctl open device: /dev/sda failed: Permission denied", "severity": "error"}
+  ]}
+}`
+
+func TestParseSmartctlJSON(t *testing.T) {
+	ata, err := ParseSmartctlJSON("sda", []byte(smartctlATA))
+	if err != nil {
+		t.Fatalf("ParseSmartctlJSON(ata) error = %v", err)
+	}
+	if !ata.Passed || ata.Reallocated != 8 || ata.Pending != 0 || ata.PercentUsed != -1 || ata.PowerOnHours != 12034 {
+		t.Fatalf("ata = %+v", ata)
+	}
+	if got := formatSMART("", ata); got != "sda Samsung SSD 870 EVO 1TB: reasignados 8 - 34ºC - 12034h" {
+		t.Fatalf("formatSMART(ata) = %q", got)
+	}
+
+	nvme, err := ParseSmartctlJSON("nvme0n1", []byte(smartctlNVMe))
+	if err != nil {
+		t.Fatalf("ParseSmartctlJSON(nvme) error = %v", err)
+	}
+	if got := formatSMART("", nvme); got != "nvme0n1 WD Blue SN570 1TB: FALLO - errores de medio 2 - 45ºC - 800h - desgaste 3%" {
+		t.Fatalf("formatSMART(nvme) = %q", got)
+	}
+
+	denied, err := ParseSmartctlJSON("sda", []byte(smartctlDenied))
+	if err != nil || !strings.Contains(denied.Err, "Permission denied") {
+		t.Fatalf("denied = %+v, %v", denied, err)
+	}
+	if len(denied.Problems()) != 0 {
+		t.Fatalf("a device without data has no problems: %v", denied.Problems())
+	}
+
+	if _, err := ParseSmartctlJSON("sda", []byte("Unknown option")); err == nil {
+		t.Fatalf("expected error for non-JSON output")
+	}
+}
+
+func TestCollectSMART(t *testing.T) {
+	prevLook, prevRun := lookSmartctl, runSmartctl
+	t.Cleanup(func() { lookSmartctl, runSmartctl = prevLook, prevRun })
+
+	lookSmartctl = func() (string, error) { return "", errors.New("not found") }
+	runSmartctl = func(context.Context, string, string) ([]byte, error) {
+		t.Fatalf("smartctl run while missing")
+		return nil, nil
+	}
+	if got, err := collectSMART(context.Background(), []string{"sda"}); got != nil || err != nil {
+		t.Fatalf("missing smartctl = %v, %v", got, err)
+	}
+
+	lookSmartctl = func() (string, error) { return "/usr/sbin/smartctl", nil }
+	var asked []string
+	runSmartctl = func(_ context.Context, _ string, device string) ([]byte, error) {
+		asked = append(asked, device)
+		if device == "/dev/sdb" {
+			// Exit status 4: some command failed, yet the JSON is usable.
+			return []byte(smartctlATA), errors.New("exit status 4")
+		}
+		return nil, errors.New("exit status 2")
+	}
+	got, err := collectSMART(context.Background(), []string{"sda", "sdb"})
+	if err != nil {
+		t.Fatalf("collectSMART() error = %v", err)
+	}
+	if strings.Join(asked, ",") != "/dev/sda,/dev/sdb" || len(got) != 2 || got[0].Err != "exit status 2" || got[1].Reallocated != 8 {
+		t.Fatalf("collectSMART() = %+v (asked %v)", got, asked)
+	}
+}
+
+func TestFormatHTMLDiskDetails(t *testing.T) {
+	output := FormatHTML(Stats{
+		Disks: []DiskUsage{{Mount: "/", Used: 1 << 30, Total: 4 << 30, UsedPercent: 25, InodesTotal: 1000, InodesUsed: 120, InodesPercent: 12}},
+		IO: DiskIOStats{ReadPerSec: 2048, Devices: []DeviceIO{
+			{Name: "sda", ReadPerSec: 2048, ReadIOPS: 4, WriteIOPS: 1, UtilPercent: 12.4, Await: 3.25},
+		}},
+		SMART: []SMARTStatus{{Device: "sda", Err: "standby"}},
+	})
+
+	for _, needle := range []string{
+		"/ 1.0GB/4.0GB =&gt; (25.0%) - inodos 12.0%",
+		"sda: R 2.0KB/s W 0B/s - 5 IOPS - util 12% - await 3.2ms",
+		"🩺 <b>SMART</b>",
+		"sda: sin datos (standby)",
+	} {
+		if !strings.Contains(output, needle) {
+			t.Fatalf("FormatHTML output missing %q:\n%s", needle, output)
+		}
+	}
+}
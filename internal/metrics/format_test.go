@@ -30,6 +30,10 @@ func TestFormatHTMLWithGPUAndWarnings(t *testing.T) {
 		Network: NetworkStats{
 			SentPerSec:     12 * 1024,
 			ReceivedPerSec: 24 * 1024,
+			Interfaces: []InterfaceStats{
+				{Name: "eth0", SentPerSec: 10 * 1024, ReceivedPerSec: 20 * 1024},
+				{Name: "wg0", SentPerSec: 2 * 1024, ReceivedPerSec: 4 * 1024},
+			},
 		},
 		IO: DiskIOStats{
 			ReadPerSec:  1024,
@@ -37,14 +41,17 @@ func TestFormatHTMLWithGPUAndWarnings(t *testing.T) {
 		},
 		GPU: []GPUStats{
 			{
-				Index:       "0",
+				Vendor:      "nvidia",
+				Index:       0,
 				Name:        "RTX 4090",
-				Utilization: "85",
-				MemoryUsed:  "1000MiB",
-				MemoryTotal: "12000MiB",
-				Temperature: "65",
-				Power:       "250W",
+				UtilPercent: 85,
+				MemoryUsed:  1000 * 1024 * 1024,
+				MemoryTotal: 12000 * 1024 * 1024,
+				Temperature: 65,
+				PowerWatts:  250,
+				ClockMHz:    -1,
 			},
+			{Vendor: "intel", Index: 1, Name: "Intel 0x46a6", UtilPercent: -1, Temperature: -1, PowerWatts: -1, ClockMHz: 1300},
 		},
 		Host: HostStats{
 			Uptime: 3*time.Hour + 4*time.Minute + 5*time.Second,
@@ -65,14 +72,15 @@ func TestFormatHTMLWithGPUAndWarnings(t *testing.T) {
 		"Swap: 512.0MB/1.0GB =&gt; (50.0%)",
 		"🌐 <b>Red &amp; IO</b>",
 		"Red: ↑ 12.0KB/s &lt;=&gt; ↓ 24.0KB/s",
+		"eth0: ↑ 10.0KB/s &lt;=&gt; ↓ 20.0KB/s",
+		"wg0: ↑ 2.0KB/s &lt;=&gt; ↓ 4.0KB/s",
 		"Disco: R 1.0KB/s &lt;=&gt; W 2.0KB/s",
 		"💾 <b>Almacenamiento</b>",
 		"/ 90.0GB/128.0GB =&gt; (70.3%)",
 		"🖥️ <b>GPU</b>",
 		"• GPU0 RTX 4090",
-		"• Util 85 - Mem 1000MiB / 12000MiB",
-		"• Temp 65ºC",
-		"• Potencia 250W",
+		"• Util 85% - Mem 1000.0MB / 11.7GB - Temp 65ºC - Potencia 250W\n",
+		"• GPU1 Intel 0x46a6\n• Reloj 1300 MHz",
 		"⏱️ <b>Uptime</b>",
 		"• En marcha: 3h 4m 5s",
 		"⚠️ <b>Advertencias</b>",
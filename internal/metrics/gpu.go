@@ -0,0 +1,352 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// GPUStats is one graphics adapter. Readings the backend cannot provide are
+// -1; MemoryTotal is 0 when memory is unknown.
+type GPUStats struct {
+	Vendor      string // nvidia, amd or intel
+	Index       int
+	Name        string
+	UtilPercent float64
+	MemoryUsed  uint64
+	MemoryTotal uint64
+	Temperature float64
+	PowerWatts  float64
+	ClockMHz    float64
+}
+
+// Label identifies the GPU in messages and alert keys, e.g. "GPU0 RTX 4090".
+func (g GPUStats) Label() string {
+	return fmt.Sprintf("GPU%d %s", g.Index, g.Name)
+}
+
+// GPUProvider reads the GPUs of one vendor.
+type GPUProvider interface {
+	Name() string
+	// Detect reports whether the backend has anything to read on this host.
+	// The collector calls it once and skips providers that return false.
+	Detect(ctx context.Context) bool
+	Collect(ctx context.Context) ([]GPUStats, error)
+}
+
+// DefaultGPUProviders returns the built-in NVIDIA, AMD and Intel backends.
+func DefaultGPUProviders() []GPUProvider {
+	return []GPUProvider{NVIDIAProvider{}, AMDProvider{}, IntelProvider{}}
+}
+
+// gpuProviders runs detection on first use so hosts without a GPU stop
+// reporting warnings after startup.
+func (c *Collector) gpuProviders(ctx context.Context) []GPUProvider {
+	c.gpuOnce.Do(func() {
+		for _, p := range c.options.GPUProviders {
+			if p.Detect(ctx) {
+				c.gpus = append(c.gpus, p)
+			}
+		}
+	})
+	return c.gpus
+}
+
+func (c *Collector) collectGPU(ctx context.Context) ([]GPUStats, error) {
+	var (
+		stats []GPUStats
+		errs  []error
+	)
+	for _, p := range c.gpuProviders(ctx) {
+		gpus, err := p.Collect(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		stats = append(stats, gpus...)
+	}
+	return stats, errors.Join(errs...)
+}
+
+// lookNvidiaSMI is replaced in tests; a missing nvidia-smi disables the
+// NVIDIA backend.
+var lookNvidiaSMI = func() (string, error) { return exec.LookPath("nvidia-smi") }
+
+// runNvidiaSMI is replaced in tests to return fixture output.
+var runNvidiaSMI = func(ctx context.Context, path string) ([]byte, error) {
+	return exec.CommandContext(ctx, path,
+		"--query-gpu=index,name,utilization.gpu,memory.used,memory.total,temperature.gpu,power.draw,clocks.gr",
+		"--format=csv,noheader,nounits").Output()
+}
+
+// NVIDIAProvider reads NVIDIA GPUs through nvidia-smi, which queries NVML
+// and avoids linking the library into the bot.
+type NVIDIAProvider struct{}
+
+func (NVIDIAProvider) Name() string { return "nvidia" }
+
+// Detect requires nvidia-smi to list at least one GPU: the binary is often
+// installed on hosts where the driver is not loaded.
+func (p NVIDIAProvider) Detect(ctx context.Context) bool {
+	gpus, err := p.Collect(ctx)
+	return err == nil && len(gpus) > 0
+}
+
+func (NVIDIAProvider) Collect(ctx context.Context) ([]GPUStats, error) {
+	path, err := lookNvidiaSMI()
+	if err != nil {
+		return nil, err
+	}
+	output, err := runNvidiaSMI(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNvidiaSMI(output)
+}
+
+// ParseNvidiaSMI reads nvidia-smi --query-gpu CSV output (noheader,nounits)
+// with the columns index, name, utilization.gpu, memory.used, memory.total,
+// temperature.gpu, power.draw and clocks.gr. "[N/A]" values become -1.
+func ParseNvidiaSMI(data []byte) ([]GPUStats, error) {
+	var stats []GPUStats
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		fields := strings.Split(string(line), ",")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("unexpected nvidia-smi line %q", strings.TrimSpace(string(line)))
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected nvidia-smi index %q", fields[0])
+		}
+
+		const mib = 1024 * 1024
+		gpu := GPUStats{
+			Vendor:      "nvidia",
+			Index:       index,
+			Name:        fields[1],
+			UtilPercent: nvidiaValue(fields[2]),
+			Temperature: nvidiaValue(fields[5]),
+			PowerWatts:  nvidiaValue(fields[6]),
+			ClockMHz:    -1,
+		}
+		if used, total := nvidiaValue(fields[3]), nvidiaValue(fields[4]); used >= 0 && total > 0 {
+			gpu.MemoryUsed, gpu.MemoryTotal = uint64(used*mib), uint64(total*mib)
+		}
+		if len(fields) > 7 {
+			gpu.ClockMHz = nvidiaValue(fields[7])
+		}
+		stats = append(stats, gpu)
+	}
+	return stats, nil
+}
+
+func nvidiaValue(raw string) float64 {
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+// drmRoot is replaced in tests with a fake sysfs tree.
+var drmRoot = "/sys/class/drm"
+
+// drmCard is one /sys/class/drm/cardN entry.
+type drmCard struct {
+	index  int
+	dir    string // cardN
+	device string // cardN/device
+}
+
+// drmCards lists the cards whose PCI vendor matches, ordered by index.
+// Connector entries such as card0-HDMI-A-1 are skipped.
+func drmCards(vendor string) []drmCard {
+	entries, err := os.ReadDir(drmRoot)
+	if err != nil {
+		return nil
+	}
+
+	var cards []drmCard
+	for _, entry := range entries {
+		name := entry.Name()
+		index, err := strconv.Atoi(strings.TrimPrefix(name, "card"))
+		if !strings.HasPrefix(name, "card") || err != nil {
+			continue
+		}
+		dir := filepath.Join(drmRoot, name)
+		device := filepath.Join(dir, "device")
+		if v, _ := readSysString(filepath.Join(device, "vendor")); v != vendor {
+			continue
+		}
+		cards = append(cards, drmCard{index: index, dir: dir, device: device})
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].index < cards[j].index })
+	return cards
+}
+
+// gpuName prefers the marketing name some drivers expose and falls back to
+// the PCI device id.
+func gpuName(card drmCard, vendor string) string {
+	if name, ok := readSysString(filepath.Join(card.device, "product_name")); ok {
+		return name
+	}
+	id, _ := readSysString(filepath.Join(card.device, "device"))
+	return strings.TrimSpace(vendor + " " + id)
+}
+
+// readGPUHwmon fills temperature, power and clock from the hwmon chip under
+// the card's device directory.
+func readGPUHwmon(card drmCard, gpu *GPUStats) {
+	dirs, _ := filepath.Glob(filepath.Join(card.device, "hwmon", "hwmon*"))
+	sort.Strings(dirs)
+	if len(dirs) == 0 {
+		return
+	}
+	dir := dirs[0]
+
+	var sensors SensorStats
+	readInputs(dir, "", &sensors)
+	for i, t := range sensors.Temperatures {
+		// amdgpu reports edge, junction and mem; edge matches what other
+		// tools show as "the" GPU temperature.
+		if i == 0 || t.Label == "edge" {
+			gpu.Temperature = t.Celsius
+		}
+		if t.Label == "edge" {
+			break
+		}
+	}
+	if len(sensors.Power) > 0 {
+		gpu.PowerWatts = sensors.Power[0].Watts
+	}
+	if hz, ok := readSysFloat(filepath.Join(dir, "freq1_input")); ok {
+		gpu.ClockMHz = hz / 1e6
+	}
+}
+
+func newSysfsGPU(vendor string, card drmCard) GPUStats {
+	return GPUStats{
+		Vendor:      vendor,
+		Index:       card.index,
+		UtilPercent: -1,
+		Temperature: -1,
+		PowerWatts:  -1,
+		ClockMHz:    -1,
+	}
+}
+
+// AMDProvider reads GPUs driven by amdgpu from sysfs.
+type AMDProvider struct{}
+
+const amdVendorID = "0x1002"
+
+func (AMDProvider) Name() string { return "amd" }
+
+func (AMDProvider) Detect(context.Context) bool {
+	return len(drmCards(amdVendorID)) > 0
+}
+
+func (AMDProvider) Collect(ctx context.Context) ([]GPUStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var stats []GPUStats
+	for _, card := range drmCards(amdVendorID) {
+		gpu := newSysfsGPU("amd", card)
+		gpu.Name = gpuName(card, "AMD")
+		if busy, ok := readSysFloat(filepath.Join(card.device, "gpu_busy_percent")); ok {
+			gpu.UtilPercent = busy
+		}
+		used, okUsed := readSysFloat(filepath.Join(card.device, "mem_info_vram_used"))
+		total, okTotal := readSysFloat(filepath.Join(card.device, "mem_info_vram_total"))
+		if okUsed && okTotal && total > 0 {
+			gpu.MemoryUsed, gpu.MemoryTotal = uint64(used), uint64(total)
+		}
+		readGPUHwmon(card, &gpu)
+		stats = append(stats, gpu)
+	}
+	return stats, nil
+}
+
+// IntelProvider reads i915 and xe GPUs from sysfs. The kernel does not
+// expose a busy percentage for them, so utilisation stays unknown and the
+// current graphics clock is reported instead.
+type IntelProvider struct{}
+
+const intelVendorID = "0x8086"
+
+func (IntelProvider) Name() string { return "intel" }
+
+func (IntelProvider) Detect(context.Context) bool {
+	return len(drmCards(intelVendorID)) > 0
+}
+
+func (IntelProvider) Collect(ctx context.Context) ([]GPUStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var stats []GPUStats
+	for _, card := range drmCards(intelVendorID) {
+		gpu := newSysfsGPU("intel", card)
+		gpu.Name = gpuName(card, "Intel")
+		readGPUHwmon(card, &gpu)
+		for _, freq := range []string{
+			filepath.Join(card.dir, "gt_act_freq_mhz"),                      // i915
+			filepath.Join(card.device, "tile0", "gt0", "freq0", "act_freq"), // xe
+		} {
+			if mhz, ok := readSysFloat(freq); ok {
+				gpu.ClockMHz = mhz
+				break
+			}
+		}
+		stats = append(stats, gpu)
+	}
+	return stats, nil
+}
+
+// gpuLines renders one entry per GPU, skipping unknown readings.
+func gpuLines(lang string, gpus []GPUStats) []string {
+	lines := make([]string, 0, len(gpus))
+	for _, gpu := range gpus {
+		var details []string
+		if gpu.UtilPercent >= 0 {
+			details = append(details, fmt.Sprintf("Util %.0f%%", gpu.UtilPercent))
+		}
+		if gpu.MemoryTotal > 0 {
+			details = append(details, fmt.Sprintf("Mem %s / %s", human(gpu.MemoryUsed), human(gpu.MemoryTotal)))
+		}
+		if gpu.Temperature >= 0 {
+			details = append(details, fmt.Sprintf("Temp %.0fºC", gpu.Temperature))
+		}
+		if gpu.PowerWatts >= 0 {
+			details = append(details, i18n.T(lang, "stats.gpu_power", gpu.PowerWatts))
+		}
+		if gpu.ClockMHz >= 0 {
+			details = append(details, i18n.T(lang, "stats.gpu_clock", gpu.ClockMHz))
+		}
+
+		entry := gpu.Label()
+		if len(details) > 0 {
+			entry += "\n• " + strings.Join(details, " - ")
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
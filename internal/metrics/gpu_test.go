@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// nvidiaSMIOutput was recorded on a host with an RTX 3090 and a Tesla T4
+// whose power reading is unsupported.
+const nvidiaSMIOutput = `0, NVIDIA GeForce RTX 3090, 37, 1523, 24576, 54, 112.45, 1695
+1, Tesla T4, 0, 3, 15360, 31, [N/A], 300
+`
+
+func TestParseNvidiaSMI(t *testing.T) {
+	got, err := ParseNvidiaSMI([]byte(nvidiaSMIOutput))
+	if err != nil {
+		t.Fatalf("ParseNvidiaSMI: %v", err)
+	}
+	want := []GPUStats{
+		{Vendor: "nvidia", Index: 0, Name: "NVIDIA GeForce RTX 3090", UtilPercent: 37,
+			MemoryUsed: 1523 << 20, MemoryTotal: 24576 << 20, Temperature: 54, PowerWatts: 112.45, ClockMHz: 1695},
+		{Vendor: "nvidia", Index: 1, Name: "Tesla T4", UtilPercent: 0,
+			MemoryUsed: 3 << 20, MemoryTotal: 15360 << 20, Temperature: 31, PowerWatts: -1, ClockMHz: 300},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseNvidiaSMI =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := ParseNvidiaSMI([]byte("NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver.")); err == nil {
+		t.Fatal("expected error for driver failure message")
+	}
+	if got, err := ParseNvidiaSMI(nil); err != nil || len(got) != 0 {
+		t.Fatalf("empty output = %v, %v", got, err)
+	}
+}
+
+func TestNVIDIAProviderDetect(t *testing.T) {
+	prevLook, prevRun := lookNvidiaSMI, runNvidiaSMI
+	t.Cleanup(func() { lookNvidiaSMI, runNvidiaSMI = prevLook, prevRun })
+
+	lookNvidiaSMI = func() (string, error) { return "", errors.New("not found") }
+	if (NVIDIAProvider{}).Detect(context.Background()) {
+		t.Fatal("Detect without nvidia-smi = true")
+	}
+
+	lookNvidiaSMI = func() (string, error) { return "/usr/bin/nvidia-smi", nil }
+	runNvidiaSMI = func(context.Context, string) ([]byte, error) { return nil, errors.New("exit status 9") }
+	if (NVIDIAProvider{}).Detect(context.Background()) {
+		t.Fatal("Detect with failing driver = true")
+	}
+
+	runNvidiaSMI = func(context.Context, string) ([]byte, error) { return []byte(nvidiaSMIOutput), nil }
+	if !(NVIDIAProvider{}).Detect(context.Background()) {
+		t.Fatal("Detect with GPUs = false")
+	}
+}
+
+// fakeDRM builds a class/drm tree; files maps paths relative to it.
+func fakeDRM(t *testing.T, files map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prev := drmRoot
+	drmRoot = root
+	t.Cleanup(func() { drmRoot = prev })
+}
+
+// drmFixture mirrors sysfs on a desktop with an RX 6800 (card1) next to the
+// Alder Lake iGPU (card0) and an i915 connector entry.
+var drmFixture = map[string]string{
+	"card0/device/vendor":                      "0x8086",
+	"card0/device/device":                      "0x4680",
+	"card0/gt_act_freq_mhz":                    "1450",
+	"card0-HDMI-A-1/status":                    "connected",
+	"card1/device/vendor":                      "0x1002",
+	"card1/device/device":                      "0x73bf",
+	"card1/device/gpu_busy_percent":            "23",
+	"card1/device/mem_info_vram_used":          "1073741824",
+	"card1/device/mem_info_vram_total":         "17163091968",
+	"card1/device/hwmon/hwmon3/name":           "amdgpu",
+	"card1/device/hwmon/hwmon3/temp1_input":    "48000",
+	"card1/device/hwmon/hwmon3/temp1_label":    "edge",
+	"card1/device/hwmon/hwmon3/temp2_input":    "61000",
+	"card1/device/hwmon/hwmon3/temp2_label":    "junction",
+	"card1/device/hwmon/hwmon3/power1_average": "38000000",
+	"card1/device/hwmon/hwmon3/freq1_input":    "2105000000",
+}
+
+func TestAMDProvider(t *testing.T) {
+	fakeDRM(t, drmFixture)
+
+	p := AMDProvider{}
+	if !p.Detect(context.Background()) {
+		t.Fatal("Detect = false")
+	}
+	got, err := p.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	want := []GPUStats{{Vendor: "amd", Index: 1, Name: "AMD 0x73bf", UtilPercent: 23,
+		MemoryUsed: 1 << 30, MemoryTotal: 17163091968, Temperature: 48, PowerWatts: 38, ClockMHz: 2105}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Collect =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestIntelProvider(t *testing.T) {
+	fakeDRM(t, drmFixture)
+
+	got, err := IntelProvider{}.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	want := []GPUStats{{Vendor: "intel", Index: 0, Name: "Intel 0x4680", UtilPercent: -1,
+		Temperature: -1, PowerWatts: -1, ClockMHz: 1450}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Collect =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+type countingProvider struct {
+	detects, collects *int
+	present           bool
+}
+
+func (countingProvider) Name() string { return "fake" }
+
+func (p countingProvider) Detect(context.Context) bool {
+	*p.detects++
+	return p.present
+}
+
+func (p countingProvider) Collect(context.Context) ([]GPUStats, error) {
+	*p.collects++
+	return []GPUStats{{Name: "fake"}}, nil
+}
+
+func TestCollectGPUDetectsOnce(t *testing.T) {
+	var absentDetects, absentCollects, presentDetects, presentCollects int
+	c := NewCollector(Options{GPUProviders: []GPUProvider{
+		countingProvider{detects: &absentDetects, collects: &absentCollects},
+		countingProvider{detects: &presentDetects, collects: &presentCollects, present: true},
+	}})
+
+	for range 3 {
+		gpus, err := c.collectGPU(context.Background())
+		if err != nil || len(gpus) != 1 {
+			t.Fatalf("collectGPU = %v, %v", gpus, err)
+		}
+	}
+	if absentDetects != 1 || presentDetects != 1 {
+		t.Fatalf("Detect calls = %d/%d, want 1/1", absentDetects, presentDetects)
+	}
+	if absentCollects != 0 || presentCollects != 3 {
+		t.Fatalf("Collect calls = %d/%d, want 0/3", absentCollects, presentCollects)
+	}
+}
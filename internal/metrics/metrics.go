@@ -1,15 +1,18 @@
 package metrics
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"html"
-	"os/exec"
+	"math"
+	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"serverbot/internal/i18n"
+
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/host"
@@ -22,11 +25,32 @@ import (
 type Options struct {
 	DiskTargets    []string
 	SampleInterval time.Duration
+	// NetInclude and NetExclude are glob patterns (path.Match syntax) of the
+	// network interfaces to report. An empty include list selects every
+	// interface; when both are empty DefaultNetExclude applies.
+	NetInclude []string
+	NetExclude []string
+	// GPUProviders are probed once on the first collection; nil selects
+	// DefaultGPUProviders.
+	GPUProviders []GPUProvider
+	// SourceTimeout is how long each source may run beyond SampleInterval
+	// before it is reported as timed out (default DefaultSourceTimeout).
+	SourceTimeout time.Duration
 }
 
+// DefaultNetExclude skips loopback and the virtual interfaces created by
+// Docker and libvirt, whose traffic is already counted on the uplink.
+var DefaultNetExclude = []string{"lo", "docker*", "br-*", "veth*", "virbr*"}
+
 // Collector collects system metrics in a single coherent call.
 type Collector struct {
 	options Options
+
+	mu      sync.Mutex
+	sources []Source
+
+	gpuOnce sync.Once
+	gpus    []GPUProvider
 }
 
 // NewCollector builds a Collector applying sensible defaults.
@@ -37,8 +61,35 @@ func NewCollector(opts Options) *Collector {
 	if len(opts.DiskTargets) == 0 {
 		opts.DiskTargets = []string{"/"}
 	}
+	if len(opts.NetInclude) == 0 && len(opts.NetExclude) == 0 {
+		opts.NetExclude = DefaultNetExclude
+	}
+	if opts.GPUProviders == nil {
+		opts.GPUProviders = DefaultGPUProviders()
+	}
+	if opts.SourceTimeout <= 0 {
+		opts.SourceTimeout = DefaultSourceTimeout
+	}
+
+	c := &Collector{options: opts}
+	c.registerBuiltins()
+	return c
+}
+
+// SetDiskTargets replaces the mount points reported from the next sample on.
+func (c *Collector) SetDiskTargets(targets []string) {
+	if len(targets) == 0 {
+		targets = []string{"/"}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.options.DiskTargets = append([]string(nil), targets...)
+}
 
-	return &Collector{options: opts}
+func (c *Collector) diskTargets() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.options.DiskTargets
 }
 
 // SampleInterval exposes the interval used for differential metrics.
@@ -55,7 +106,12 @@ type Stats struct {
 	IO       DiskIOStats
 	GPU      []GPUStats
 	Host     HostStats
+	SMART    []SMARTStatus
+	Sensors  SensorStats
 	Warnings []string
+	// Sources reports the duration and error of every source, in
+	// registration order.
+	Sources []SourceReport
 }
 
 type CPUStats struct {
@@ -77,111 +133,167 @@ type MemoryStats struct {
 }
 
 type DiskUsage struct {
-	Mount       string
-	Used        uint64
-	Total       uint64
-	UsedPercent float64
+	Mount         string
+	Device        string
+	Used          uint64
+	Total         uint64
+	UsedPercent   float64
+	InodesUsed    uint64
+	InodesTotal   uint64
+	InodesPercent float64
 }
 
+// NetworkStats aggregates the selected interfaces; Interfaces holds each one
+// sorted by name.
 type NetworkStats struct {
 	SentPerSec     uint64
 	ReceivedPerSec uint64
+	Interfaces     []InterfaceStats
 }
 
+// InterfaceStats are the rates of one interface over the sample interval
+// and its cumulative kernel counters at the end of it.
+type InterfaceStats struct {
+	Name           string
+	SentPerSec     uint64
+	ReceivedPerSec uint64
+	BytesSent      uint64
+	BytesRecv      uint64
+}
+
+// DiskIOStats aggregates the physical devices behind DiskTargets; Devices
+// holds each one sorted by name.
 type DiskIOStats struct {
 	ReadPerSec  uint64
 	WritePerSec uint64
+	Devices     []DeviceIO
 }
 
-type GPUStats struct {
-	Index       string
+// DeviceIO are the rates of one block device over the sample interval.
+// Await is the average time per completed request in milliseconds.
+type DeviceIO struct {
 	Name        string
-	Utilization string
-	MemoryUsed  string
-	MemoryTotal string
-	Temperature string
-	Power       string
+	ReadPerSec  uint64
+	WritePerSec uint64
+	ReadIOPS    float64
+	WriteIOPS   float64
+	UtilPercent float64
+	Await       float64
 }
 
 type HostStats struct {
 	Uptime time.Duration
 }
 
-// Collect gathers metrics, recording warnings for partial failures instead of aborting.
+// Collect runs every registered source concurrently over one shared sampling
+// window, recording warnings for failed or slow sources instead of aborting.
 func (c *Collector) Collect(ctx context.Context) (Stats, error) {
-	var stats Stats
+	sources := c.registered()
+	window := newWindow(c.options.SampleInterval)
 
-	cpuStats, err := collectCPU(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("CPU: %v", err))
-	} else {
-		stats.CPU = cpuStats
+	results := make([]sourceResult, len(sources))
+	var wg sync.WaitGroup
+	for i, s := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runSource(ctx, s, window)
+		}()
 	}
+	wg.Wait()
 
-	memStats, err := collectMemory(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("Memoria: %v", err))
-	} else {
-		stats.Memory = memStats
-	}
-
-	diskStats, err := c.collectDisks(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("Discos: %v", err))
-	} else {
-		stats.Disks = diskStats
-	}
-
-	networkStats, err := c.collectNetwork(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("Red: %v", err))
-	} else {
-		stats.Network = networkStats
-	}
-
-	diskIOStats, err := c.collectDiskIO(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("Disco IO: %v", err))
-	} else {
-		stats.IO = diskIOStats
+	var stats Stats
+	for i, s := range sources {
+		res := results[i]
+		stats.Sources = append(stats.Sources, SourceReport{Name: s.Name(), Duration: res.duration, Err: res.err})
+		if res.err != nil {
+			stats.Warnings = append(stats.Warnings, fmt.Sprintf("%s: %v", s.Name(), res.err))
+			continue
+		}
+		if res.apply != nil {
+			res.apply(&stats)
+		}
 	}
+	return stats, nil
+}
 
-	gpuStats, err := collectGPU(ctx)
-	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("GPU: %v", err))
-	} else {
-		stats.GPU = gpuStats
-	}
+// registerBuiltins registers the host sources in the order their warnings
+// are listed.
+func (c *Collector) registerBuiltins() {
+	c.Register(NewSource("CPU", func(ctx context.Context, w *Window) (Apply, error) {
+		cpuStats, err := collectCPU(ctx, w)
+		return func(s *Stats) { s.CPU = cpuStats }, err
+	}))
+	c.Register(NewSource("Memoria", func(ctx context.Context, _ *Window) (Apply, error) {
+		memStats, err := collectMemory(ctx)
+		return func(s *Stats) { s.Memory = memStats }, err
+	}))
+	c.Register(NewSource("Discos", func(ctx context.Context, _ *Window) (Apply, error) {
+		partitions, err := c.targetPartitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		disks, err := collectDisks(ctx, partitions)
+		return func(s *Stats) { s.Disks = disks }, err
+	}))
+	c.Register(NewSource("Red", func(ctx context.Context, w *Window) (Apply, error) {
+		networkStats, err := c.collectNetwork(ctx, w)
+		return func(s *Stats) { s.Network = networkStats }, err
+	}))
+	c.Register(NewSource("Disco IO", func(ctx context.Context, w *Window) (Apply, error) {
+		partitions, err := c.targetPartitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ioStats, err := collectDiskIO(ctx, w, targetDevices(partitions))
+		return func(s *Stats) { s.IO = ioStats }, err
+	}))
+	c.Register(NewSource("GPU", func(ctx context.Context, _ *Window) (Apply, error) {
+		gpuStats, err := c.collectGPU(ctx)
+		return func(s *Stats) { s.GPU = gpuStats }, err
+	}))
+	c.Register(NewSource("Host", func(ctx context.Context, _ *Window) (Apply, error) {
+		hostStats, err := collectHost(ctx)
+		return func(s *Stats) { s.Host = hostStats }, err
+	}))
+	c.Register(NewSource("Sensores", func(ctx context.Context, _ *Window) (Apply, error) {
+		sensorStats, err := collectSensors(ctx)
+		return func(s *Stats) { s.Sensors = sensorStats }, err
+	}))
+	c.Register(NewSource("SMART", func(ctx context.Context, _ *Window) (Apply, error) {
+		partitions, err := c.targetPartitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		smartStats, err := collectSMART(ctx, targetDevices(partitions))
+		return func(s *Stats) { s.SMART = smartStats }, err
+	}))
+}
 
-	hostStats, err := collectHost(ctx)
+// collectCPU measures usage from the CPU times at both ends of the window,
+// so concurrent collections do not disturb each other's baseline.
+func collectCPU(ctx context.Context, window *Window) (CPUStats, error) {
+	before, err := cpu.TimesWithContext(ctx, false)
 	if err != nil {
-		stats.Warnings = append(stats.Warnings, fmt.Sprintf("Host: %v", err))
-	} else {
-		stats.Host = hostStats
+		return CPUStats{}, err
 	}
-
-	return stats, nil
-}
-
-func collectCPU(ctx context.Context) (CPUStats, error) {
-	if err := ctx.Err(); err != nil {
+	if err := window.Wait(ctx); err != nil {
 		return CPUStats{}, err
 	}
-
-	perc, err := cpu.Percent(0, false)
+	after, err := cpu.TimesWithContext(ctx, false)
 	if err != nil {
 		return CPUStats{}, err
 	}
-	if len(perc) == 0 {
+	if len(before) == 0 || len(after) == 0 {
 		return CPUStats{}, fmt.Errorf("no CPU data")
 	}
 
-	loadAvg, err := load.Avg()
+	loadAvg, err := load.AvgWithContext(ctx)
 	if err != nil {
 		return CPUStats{}, err
 	}
 
-	cores, err := cpu.Counts(true)
+	cores, err := cpu.CountsWithContext(ctx, true)
 	if err != nil {
 		return CPUStats{}, err
 	}
@@ -192,7 +304,7 @@ func collectCPU(ctx context.Context) (CPUStats, error) {
 	}
 
 	return CPUStats{
-		Usage:     perc[0],
+		Usage:     cpuUsage(before[0], after[0]),
 		Load1:     loadAvg.Load1,
 		Load5:     loadAvg.Load5,
 		Load15:    loadAvg.Load15,
@@ -201,6 +313,17 @@ func collectCPU(ctx context.Context) (CPUStats, error) {
 	}, nil
 }
 
+// cpuUsage is the busy share of the time elapsed between two readings.
+func cpuUsage(before, after cpu.TimesStat) float64 {
+	idle := func(t cpu.TimesStat) float64 { return t.Idle + t.Iowait }
+	total := after.Total() - before.Total()
+	if total <= 0 {
+		return 0
+	}
+	busy := total - (idle(after) - idle(before))
+	return math.Min(math.Max(busy/total*100, 0), 100)
+}
+
 func collectMemory(ctx context.Context) (MemoryStats, error) {
 	if err := ctx.Err(); err != nil {
 		return MemoryStats{}, err
@@ -226,37 +349,49 @@ func collectMemory(ctx context.Context) (MemoryStats, error) {
 	}, nil
 }
 
-func (c *Collector) collectDisks(ctx context.Context) ([]DiskUsage, error) {
+// targetPartitions returns the mounted partitions listed in DiskTargets.
+func (c *Collector) targetPartitions(ctx context.Context) ([]disk.PartitionStat, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	partitions, err := disk.Partitions(false)
+	partitions, err := disk.PartitionsWithContext(ctx, false)
 	if err != nil {
 		return nil, err
 	}
 
-	targetSet := make(map[string]struct{}, len(c.options.DiskTargets))
-	for _, t := range c.options.DiskTargets {
+	diskTargets := c.diskTargets()
+	targetSet := make(map[string]struct{}, len(diskTargets))
+	for _, t := range diskTargets {
 		targetSet[strings.TrimSpace(t)] = struct{}{}
 	}
 
-	var usages []DiskUsage
+	var targets []disk.PartitionStat
 	for _, partition := range partitions {
-		if _, ok := targetSet[partition.Mountpoint]; !ok {
-			continue
+		if _, ok := targetSet[partition.Mountpoint]; ok {
+			targets = append(targets, partition)
 		}
+	}
+	return targets, nil
+}
 
-		usage, err := disk.Usage(partition.Mountpoint)
+func collectDisks(ctx context.Context, partitions []disk.PartitionStat) ([]DiskUsage, error) {
+	var usages []DiskUsage
+	for _, partition := range partitions {
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
 		if err != nil {
 			return nil, fmt.Errorf("mount %s: %w", partition.Mountpoint, err)
 		}
 
 		usages = append(usages, DiskUsage{
-			Mount:       partition.Mountpoint,
-			Used:        usage.Used,
-			Total:       usage.Total,
-			UsedPercent: usage.UsedPercent,
+			Mount:         partition.Mountpoint,
+			Device:        partition.Device,
+			Used:          usage.Used,
+			Total:         usage.Total,
+			UsedPercent:   usage.UsedPercent,
+			InodesUsed:    usage.InodesUsed,
+			InodesTotal:   usage.InodesTotal,
+			InodesPercent: usage.InodesUsedPercent,
 		})
 	}
 
@@ -267,119 +402,185 @@ func (c *Collector) collectDisks(ctx context.Context) ([]DiskUsage, error) {
 	return usages, nil
 }
 
-func (c *Collector) collectNetwork(ctx context.Context) (NetworkStats, error) {
-	if err := ctx.Err(); err != nil {
-		return NetworkStats{}, err
+// targetDevices returns the physical disks behind partitions, sorted and
+// without duplicates.
+func targetDevices(partitions []disk.PartitionStat) []string {
+	seen := make(map[string]bool)
+	var devices []string
+	for _, partition := range partitions {
+		for _, name := range physicalDevices(partition.Device) {
+			if !seen[name] {
+				seen[name] = true
+				devices = append(devices, name)
+			}
+		}
 	}
+	sort.Strings(devices)
+	return devices
+}
 
-	first, err := net.IOCounters(false)
+func (c *Collector) collectNetwork(ctx context.Context, window *Window) (NetworkStats, error) {
+	first, err := c.InterfaceCounters(ctx)
 	if err != nil {
 		return NetworkStats{}, err
 	}
-	if len(first) == 0 {
-		return NetworkStats{}, fmt.Errorf("no network data")
-	}
 
-	timer := time.NewTimer(c.options.SampleInterval)
-	select {
-	case <-ctx.Done():
-		timer.Stop()
-		return NetworkStats{}, ctx.Err()
-	case <-timer.C:
+	start := time.Now()
+	if err := window.Wait(ctx); err != nil {
+		return NetworkStats{}, err
 	}
 
-	second, err := net.IOCounters(false)
+	second, err := c.InterfaceCounters(ctx)
 	if err != nil {
 		return NetworkStats{}, err
 	}
-	if len(second) == 0 {
-		return NetworkStats{}, fmt.Errorf("no network data")
-	}
 
-	return NetworkStats{
-		SentPerSec:     second[0].BytesSent - first[0].BytesSent,
-		ReceivedPerSec: second[0].BytesRecv - first[0].BytesRecv,
-	}, nil
+	return networkRates(first, second, time.Since(start)), nil
 }
 
-func (c *Collector) collectDiskIO(ctx context.Context) (DiskIOStats, error) {
+// InterfaceCounters returns the cumulative byte counters of the interfaces
+// selected by NetInclude and NetExclude, sorted by name.
+func (c *Collector) InterfaceCounters(ctx context.Context) ([]InterfaceStats, error) {
 	if err := ctx.Err(); err != nil {
-		return DiskIOStats{}, err
+		return nil, err
 	}
 
-	first, err := disk.IOCounters()
+	counters, err := net.IOCountersWithContext(ctx, true)
 	if err != nil {
-		return DiskIOStats{}, err
+		return nil, err
 	}
 
-	timer := time.NewTimer(c.options.SampleInterval)
-	select {
-	case <-ctx.Done():
-		timer.Stop()
-		return DiskIOStats{}, ctx.Err()
-	case <-timer.C:
+	var out []InterfaceStats
+	for _, counter := range counters {
+		if !SelectInterface(counter.Name, c.options.NetInclude, c.options.NetExclude) {
+			continue
+		}
+		out = append(out, InterfaceStats{Name: counter.Name, BytesSent: counter.BytesSent, BytesRecv: counter.BytesRecv})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no network data")
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
 
-	second, err := disk.IOCounters()
-	if err != nil {
-		return DiskIOStats{}, err
+// SelectInterface reports whether name matches include (or include is empty)
+// and matches none of exclude.
+func SelectInterface(name string, include, exclude []string) bool {
+	if len(include) > 0 && !matchAny(name, include) {
+		return false
 	}
+	return !matchAny(name, exclude)
+}
 
-	for name, current := range second {
-		previous, ok := first[name]
+func matchAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// networkRates turns two counter snapshots taken elapsed apart into per
+// second rates. Interfaces that appeared in between are skipped.
+func networkRates(first, second []InterfaceStats, elapsed time.Duration) NetworkStats {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	previous := make(map[string]InterfaceStats, len(first))
+	for _, iface := range first {
+		previous[iface.Name] = iface
+	}
+
+	var stats NetworkStats
+	for _, current := range second {
+		before, ok := previous[current.Name]
 		if !ok {
 			continue
 		}
-		return DiskIOStats{
-			ReadPerSec:  current.ReadBytes - previous.ReadBytes,
-			WritePerSec: current.WriteBytes - previous.WriteBytes,
-		}, nil
+		current.SentPerSec = uint64(float64(counterDelta(before.BytesSent, current.BytesSent)) / seconds)
+		current.ReceivedPerSec = uint64(float64(counterDelta(before.BytesRecv, current.BytesRecv)) / seconds)
+		stats.SentPerSec += current.SentPerSec
+		stats.ReceivedPerSec += current.ReceivedPerSec
+		stats.Interfaces = append(stats.Interfaces, current)
 	}
+	return stats
+}
 
-	return DiskIOStats{}, fmt.Errorf("no disk io data")
+// counterDelta tolerates counters that went backwards (interface reset).
+func counterDelta(before, after uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
 }
 
-func collectGPU(ctx context.Context) ([]GPUStats, error) {
-	cmd := exec.CommandContext(ctx, "nvidia-smi",
-		"--query-gpu=index,name,utilization.gpu,memory.used,memory.total,temperature.gpu,power.draw",
-		"--format=csv,noheader,nounits")
+func collectDiskIO(ctx context.Context, window *Window, devices []string) (DiskIOStats, error) {
+	if err := ctx.Err(); err != nil {
+		return DiskIOStats{}, err
+	}
+	// Targets on overlay, tmpfs or network filesystems have no block device.
+	if len(devices) == 0 {
+		return DiskIOStats{}, nil
+	}
 
-	output, err := cmd.Output()
+	first, err := disk.IOCountersWithContext(ctx, devices...)
 	if err != nil {
-		return nil, err
+		return DiskIOStats{}, err
 	}
 
-	lines := bytes.Split(bytes.TrimSpace(output), []byte{'\n'})
-	if len(lines) == 0 || len(lines[0]) == 0 {
-		return nil, nil
+	start := time.Now()
+	if err := window.Wait(ctx); err != nil {
+		return DiskIOStats{}, err
 	}
 
-	stats := make([]GPUStats, 0, len(lines))
-	for _, line := range lines {
-		fields := bytes.Split(bytes.TrimSpace(line), []byte{','})
-		if len(fields) < 7 {
+	second, err := disk.IOCountersWithContext(ctx, devices...)
+	if err != nil {
+		return DiskIOStats{}, err
+	}
+
+	return diskIORates(first, second, devices, time.Since(start)), nil
+}
+
+// diskIORates turns two counter snapshots taken elapsed apart into per device
+// rates, utilization (busy time over wall time) and await.
+func diskIORates(first, second map[string]disk.IOCountersStat, devices []string, elapsed time.Duration) DiskIOStats {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	var stats DiskIOStats
+	for _, name := range devices {
+		before, ok1 := first[name]
+		after, ok2 := second[name]
+		if !ok1 || !ok2 {
 			continue
 		}
 
-		power := strings.TrimSpace(string(bytes.TrimSpace(fields[6])))
-		if power != "" && !strings.EqualFold(power, "N/A") {
-			power += "W"
-		} else {
-			power = ""
+		reads := counterDelta(before.ReadCount, after.ReadCount)
+		writes := counterDelta(before.WriteCount, after.WriteCount)
+		dev := DeviceIO{
+			Name:        name,
+			ReadPerSec:  uint64(float64(counterDelta(before.ReadBytes, after.ReadBytes)) / seconds),
+			WritePerSec: uint64(float64(counterDelta(before.WriteBytes, after.WriteBytes)) / seconds),
+			ReadIOPS:    float64(reads) / seconds,
+			WriteIOPS:   float64(writes) / seconds,
+			UtilPercent: min(float64(counterDelta(before.IoTime, after.IoTime))/(seconds*1000)*100, 100),
+		}
+		if ops := reads + writes; ops > 0 {
+			waited := counterDelta(before.ReadTime, after.ReadTime) + counterDelta(before.WriteTime, after.WriteTime)
+			dev.Await = float64(waited) / float64(ops)
 		}
 
-		stats = append(stats, GPUStats{
-			Index:       string(bytes.TrimSpace(fields[0])),
-			Name:        string(bytes.TrimSpace(fields[1])),
-			Utilization: string(bytes.TrimSpace(fields[2])) + "%",
-			MemoryUsed:  string(bytes.TrimSpace(fields[3])) + "MiB",
-			MemoryTotal: string(bytes.TrimSpace(fields[4])) + "MiB",
-			Temperature: string(bytes.TrimSpace(fields[5])),
-			Power:       power,
-		})
+		stats.ReadPerSec += dev.ReadPerSec
+		stats.WritePerSec += dev.WritePerSec
+		stats.Devices = append(stats.Devices, dev)
 	}
-
-	return stats, nil
+	return stats
 }
 
 func collectHost(ctx context.Context) (HostStats, error) {
@@ -396,100 +597,62 @@ func collectHost(ctx context.Context) (HostStats, error) {
 	}, nil
 }
 
-// FormatHTML renders the stats into Telegram-ready HTML.
-func FormatHTML(stats Stats) string {
-	var buf strings.Builder
-	buf.WriteString("<b>📊 Estado del Servidor</b>\n")
-
-	writeSection := func(icon, title string, lines []string) {
-		if len(lines) == 0 {
-			return
-		}
-
-		buf.WriteString("\n")
-		buf.WriteString(icon)
-		buf.WriteString(" <b>")
-		buf.WriteString(html.EscapeString(title))
-		buf.WriteString("</b>\n")
-
-		for _, line := range lines {
-			if line == "" {
-				continue
-			}
-			buf.WriteString("• ")
-			buf.WriteString(html.EscapeString(line))
-			buf.WriteByte('\n')
-		}
+func formatSMART(lang string, s SMARTStatus) string {
+	name := s.Device
+	if s.Model != "" {
+		name += " " + s.Model
 	}
-
-	if stats.CPU.Cores > 0 {
-		cpuLines := []string{
-			fmt.Sprintf("Uso: %.1f%%", stats.CPU.Usage),
-			fmt.Sprintf("Carga: %.2f / %.2f / %.2f", stats.CPU.Load1, stats.CPU.Load5, stats.CPU.Load15),
-		}
-		if stats.CPU.LoadRatio > 0 {
-			cpuLines = append(cpuLines, fmt.Sprintf("Uso global: %.0f%%", stats.CPU.LoadRatio))
-		}
-		writeSection("⚙️", "CPU", cpuLines)
+	if s.Err != "" {
+		return i18n.T(lang, "stats.smart_nodata", name, s.Err)
 	}
 
-	if stats.Memory.Total > 0 {
-		memLines := []string{
-			fmt.Sprintf("RAM: %s/%s => (%.1f%%)", human(stats.Memory.Used), human(stats.Memory.Total), stats.Memory.UsedPercent),
-		}
-		if stats.Memory.SwapTotal > 0 {
-			memLines = append(memLines, fmt.Sprintf("Swap: %s/%s => (%.1f%%)", human(stats.Memory.SwapUsed), human(stats.Memory.SwapTotal), stats.Memory.SwapPercent))
-		}
-		writeSection("🧠", "Memoria", memLines)
+	parts := []string{"OK"}
+	if problems := s.problems(lang); len(problems) > 0 {
+		parts = problems
 	}
-
-	netLines := make([]string, 0, 2)
-	if stats.Network.SentPerSec > 0 || stats.Network.ReceivedPerSec > 0 {
-		netLines = append(netLines, fmt.Sprintf("Red: ↑ %s/s <=> ↓ %s/s", human(stats.Network.SentPerSec), human(stats.Network.ReceivedPerSec)))
+	if s.Temperature > 0 {
+		parts = append(parts, fmt.Sprintf("%dºC", s.Temperature))
 	}
-	if stats.IO.ReadPerSec > 0 || stats.IO.WritePerSec > 0 {
-		netLines = append(netLines, fmt.Sprintf("Disco: R %s/s <=> W %s/s", human(stats.IO.ReadPerSec), human(stats.IO.WritePerSec)))
+	if s.PowerOnHours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", s.PowerOnHours))
 	}
-	writeSection("🌐", "Red & IO", netLines)
-
-	if len(stats.Disks) > 0 {
-		diskLines := make([]string, 0, len(stats.Disks))
-		for _, disk := range stats.Disks {
-			diskLines = append(diskLines, fmt.Sprintf("%s %s/%s => (%.1f%%)", disk.Mount, human(disk.Used), human(disk.Total), disk.UsedPercent))
-		}
-		writeSection("💾", "Almacenamiento", diskLines)
+	if s.PercentUsed >= 0 {
+		parts = append(parts, i18n.T(lang, "stats.smart_wear", s.PercentUsed))
 	}
+	return name + ": " + strings.Join(parts, " - ")
+}
 
-	if len(stats.GPU) > 0 {
-		gpuLines := make([]string, 0, len(stats.GPU))
-		for _, gpu := range stats.GPU {
-			var entry strings.Builder
-			if gpu.Index != "" {
-				entry.WriteString(fmt.Sprintf("GPU%s %s", gpu.Index, gpu.Name))
-			} else {
-				entry.WriteString(gpu.Name)
-			}
-			entry.WriteString(fmt.Sprintf("\n• Util %s - Mem %s / %s", gpu.Utilization, gpu.MemoryUsed, gpu.MemoryTotal))
-			entry.WriteString(fmt.Sprintf("\n• Temp %sºC", gpu.Temperature))
-			if gpu.Power != "" {
-				entry.WriteString(fmt.Sprintf("\n• Potencia %s", gpu.Power))
-			}
-			gpuLines = append(gpuLines, entry.String())
-		}
-		writeSection("🖥️", "GPU", gpuLines)
-	} else {
-		writeSection("🖥️", "GPU", []string{"GPU: no disponible"})
+// WriteSection appends a titled block of bullet lines using the layout of
+// FormatHTML. Titles and lines are escaped; empty sections are skipped.
+func WriteSection(buf *strings.Builder, icon, title string, lines []string) {
+	if len(lines) == 0 {
+		return
 	}
 
-	if stats.Host.Uptime > 0 {
-		writeSection("⏱️", "Uptime", []string{fmt.Sprintf("En marcha: %s", formatUptime(stats.Host.Uptime))})
-	}
+	buf.WriteString("\n")
+	buf.WriteString(icon)
+	buf.WriteString(" <b>")
+	buf.WriteString(html.EscapeString(title))
+	buf.WriteString("</b>\n")
 
-	if len(stats.Warnings) > 0 {
-		writeSection("⚠️", "Advertencias", stats.Warnings)
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		buf.WriteString("• ")
+		buf.WriteString(html.EscapeString(line))
+		buf.WriteByte('\n')
 	}
+}
+
+// HumanBytes formats a byte count with binary units (e.g. 1.5GB).
+func HumanBytes(bytes uint64) string {
+	return human(bytes)
+}
 
-	return strings.TrimSpace(buf.String())
+// FormatUptime renders a duration as "3h 4m 5s".
+func FormatUptime(d time.Duration) string {
+	return formatUptime(d)
 }
 
 func human(bytes uint64) string {
@@ -1,6 +1,11 @@
 package metrics
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
 
 func TestHuman(t *testing.T) {
 	tests := []struct {
@@ -23,3 +28,60 @@ func TestHuman(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectInterface(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             bool
+	}{
+		{"eth0", nil, DefaultNetExclude, true},
+		{"lo", nil, DefaultNetExclude, false},
+		{"docker0", nil, DefaultNetExclude, false},
+		{"br-1a2b3c", nil, DefaultNetExclude, false},
+		{"vethab12", nil, DefaultNetExclude, false},
+		{"wlan0", []string{"eth*", "enp*"}, nil, false},
+		{"enp3s0", []string{"eth*", "enp*"}, []string{"enp3s0"}, false},
+		{"enp4s0", []string{"eth*", "enp*"}, []string{"enp3s0"}, true},
+	}
+	for _, tt := range tests {
+		if got := SelectInterface(tt.name, tt.include, tt.exclude); got != tt.want {
+			t.Errorf("SelectInterface(%q, %v, %v) = %v, want %v", tt.name, tt.include, tt.exclude, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkRates(t *testing.T) {
+	first := []InterfaceStats{
+		{Name: "eth0", BytesSent: 1000, BytesRecv: 5000},
+		{Name: "wg0", BytesSent: 100, BytesRecv: 100},
+	}
+	second := []InterfaceStats{
+		{Name: "eth0", BytesSent: 3000, BytesRecv: 9000},
+		{Name: "new0", BytesSent: 50, BytesRecv: 50},
+		{Name: "wg0", BytesSent: 50, BytesRecv: 300},
+	}
+
+	got := networkRates(first, second, 2*time.Second)
+	if got.SentPerSec != 1000 || got.ReceivedPerSec != 2100 {
+		t.Fatalf("aggregate = %d/%d, want 1000/2100", got.SentPerSec, got.ReceivedPerSec)
+	}
+	if len(got.Interfaces) != 2 || got.Interfaces[0].Name != "eth0" || got.Interfaces[1].Name != "wg0" {
+		t.Fatalf("Interfaces = %+v", got.Interfaces)
+	}
+	if wg := got.Interfaces[1]; wg.SentPerSec != 0 || wg.ReceivedPerSec != 100 || wg.BytesRecv != 300 {
+		t.Fatalf("wg0 = %+v", wg)
+	}
+}
+
+func TestCPUUsage(t *testing.T) {
+	before := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 50}
+	after := cpu.TimesStat{User: 160, System: 70, Idle: 900, Iowait: 70}
+	// 200 jiffies elapsed, 120 of them idle or waiting on IO.
+	if got := cpuUsage(before, after); got != 40 {
+		t.Fatalf("cpuUsage = %v, want 40", got)
+	}
+	if got := cpuUsage(after, after); got != 0 {
+		t.Fatalf("cpuUsage without elapsed time = %v, want 0", got)
+	}
+}
@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// Section selects parts of a snapshot to render. Sections combine as a bit
+// set; AllSections adds the parts without a name of their own (sensors and
+// uptime).
+type Section uint
+
+const (
+	SectionCPU Section = 1 << iota
+	SectionMemory
+	SectionDisk
+	SectionNetwork
+	SectionGPU
+	sectionOther
+
+	AllSections = SectionCPU | SectionMemory | SectionDisk | SectionNetwork | SectionGPU | sectionOther
+)
+
+// sectionNames are the names accepted by ParseSection, in render order.
+var sectionNames = []struct {
+	name    string
+	section Section
+}{
+	{"cpu", SectionCPU},
+	{"mem", SectionMemory},
+	{"disk", SectionDisk},
+	{"net", SectionNetwork},
+	{"gpu", SectionGPU},
+	{"all", AllSections},
+}
+
+// ParseSection maps cpu, mem, disk, net, gpu or all to its Section.
+func ParseSection(name string) (Section, bool) {
+	for _, s := range sectionNames {
+		if s.name == name {
+			return s.section, true
+		}
+	}
+	return 0, false
+}
+
+// Has reports whether s includes every bit of other.
+func (s Section) Has(other Section) bool {
+	return s&other == other
+}
+
+// Renderer formats the selected sections of a snapshot.
+type Renderer interface {
+	Render(snap Snapshot, sections Section) (string, error)
+}
+
+// FormatHTML renders every section of stats into Telegram-ready HTML.
+func FormatHTML(stats Stats) string {
+	out, _ := HTMLRenderer{}.Render(Snapshot{Stats: stats}, AllSections)
+	return out
+}
+
+// HTMLRenderer produces the Telegram HTML layout of /stats. Labels are in
+// Lang, or in the default language when it is empty.
+type HTMLRenderer struct {
+	Lang string
+}
+
+func (r HTMLRenderer) Render(snap Snapshot, sections Section) (string, error) {
+	stats := snap.Stats
+	t := func(key string, args ...any) string { return i18n.T(r.Lang, key, args...) }
+
+	var buf strings.Builder
+	if snap.Host != "" {
+		buf.WriteString(t("stats.title_host", html.EscapeString(snap.Host)) + "\n")
+	} else {
+		buf.WriteString(t("stats.title") + "\n")
+	}
+
+	writeSection := func(icon, title string, lines []string) {
+		WriteSection(&buf, icon, title, lines)
+	}
+
+	if sections.Has(SectionCPU) && stats.CPU.Cores > 0 {
+		cpuLines := []string{
+			t("stats.cpu_usage", stats.CPU.Usage),
+			t("stats.cpu_load", stats.CPU.Load1, stats.CPU.Load5, stats.CPU.Load15),
+		}
+		if stats.CPU.LoadRatio > 0 {
+			cpuLines = append(cpuLines, t("stats.cpu_load_ratio", stats.CPU.LoadRatio))
+		}
+		writeSection("⚙️", "CPU", cpuLines)
+	}
+
+	if sections.Has(SectionMemory) && stats.Memory.Total > 0 {
+		memLines := []string{
+			fmt.Sprintf("RAM: %s/%s => (%.1f%%)", human(stats.Memory.Used), human(stats.Memory.Total), stats.Memory.UsedPercent),
+		}
+		if stats.Memory.SwapTotal > 0 {
+			memLines = append(memLines, fmt.Sprintf("Swap: %s/%s => (%.1f%%)", human(stats.Memory.SwapUsed), human(stats.Memory.SwapTotal), stats.Memory.SwapPercent))
+		}
+		writeSection("🧠", t("stats.memory"), memLines)
+	}
+
+	// Network and disk IO share a section; each half follows its own
+	// selector.
+	netLines := make([]string, 0, 2)
+	if sections.Has(SectionNetwork) && (stats.Network.SentPerSec > 0 || stats.Network.ReceivedPerSec > 0) {
+		netLines = append(netLines, t("stats.net_line", human(stats.Network.SentPerSec), human(stats.Network.ReceivedPerSec)))
+		if len(stats.Network.Interfaces) > 1 {
+			for _, iface := range stats.Network.Interfaces {
+				netLines = append(netLines, fmt.Sprintf("%s: ↑ %s/s <=> ↓ %s/s", iface.Name, human(iface.SentPerSec), human(iface.ReceivedPerSec)))
+			}
+		}
+	}
+	if sections.Has(SectionDisk) {
+		if stats.IO.ReadPerSec > 0 || stats.IO.WritePerSec > 0 {
+			netLines = append(netLines, t("stats.disk_io_line", human(stats.IO.ReadPerSec), human(stats.IO.WritePerSec)))
+		}
+		for _, dev := range stats.IO.Devices {
+			netLines = append(netLines, fmt.Sprintf("%s: R %s/s W %s/s - %.0f IOPS - util %.0f%% - await %.1fms",
+				dev.Name, human(dev.ReadPerSec), human(dev.WritePerSec), dev.ReadIOPS+dev.WriteIOPS, dev.UtilPercent, dev.Await))
+		}
+	}
+	switch {
+	case sections.Has(SectionNetwork | SectionDisk):
+		writeSection("🌐", t("stats.net_io"), netLines)
+	case sections.Has(SectionNetwork):
+		writeSection("🌐", t("stats.net"), netLines)
+	case sections.Has(SectionDisk):
+		writeSection("🌐", t("stats.disk_io"), netLines)
+	}
+
+	if sections.Has(SectionDisk) && len(stats.Disks) > 0 {
+		diskLines := make([]string, 0, len(stats.Disks))
+		for _, disk := range stats.Disks {
+			line := fmt.Sprintf("%s %s/%s => (%.1f%%)", disk.Mount, human(disk.Used), human(disk.Total), disk.UsedPercent)
+			if disk.InodesTotal > 0 {
+				line += t("stats.inodes", disk.InodesPercent)
+			}
+			diskLines = append(diskLines, line)
+		}
+		writeSection("💾", t("stats.storage"), diskLines)
+	}
+
+	if sections.Has(SectionDisk) && len(stats.SMART) > 0 {
+		smartLines := make([]string, 0, len(stats.SMART))
+		for _, s := range stats.SMART {
+			smartLines = append(smartLines, formatSMART(r.Lang, s))
+		}
+		writeSection("🩺", "SMART", smartLines)
+	}
+
+	if sections.Has(sectionOther) && !stats.Sensors.Empty() {
+		writeSection("🌡️", t("stats.sensors"), sensorLines(r.Lang, stats.Sensors))
+	}
+
+	if sections.Has(SectionGPU) {
+		if len(stats.GPU) > 0 {
+			writeSection("🖥️", "GPU", gpuLines(r.Lang, stats.GPU))
+		} else {
+			writeSection("🖥️", "GPU", []string{t("stats.gpu_none")})
+		}
+	}
+
+	if sections.Has(sectionOther) && stats.Host.Uptime > 0 {
+		writeSection("⏱️", "Uptime", []string{t("stats.uptime_line", formatUptime(stats.Host.Uptime))})
+	}
+
+	if len(stats.Warnings) > 0 {
+		writeSection("⚠️", t("stats.warnings"), stats.Warnings)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// CompactRenderer produces a single plain-text line suited to notifications,
+// e.g. "CPU 12% · RAM 50% · / 70% · ↑ 1.0KB/s ↓ 2.0KB/s · GPU0 85% 65ºC".
+type CompactRenderer struct{}
+
+func (CompactRenderer) Render(snap Snapshot, sections Section) (string, error) {
+	stats := snap.Stats
+
+	var parts []string
+	if sections.Has(SectionCPU) && stats.CPU.Cores > 0 {
+		parts = append(parts, fmt.Sprintf("CPU %.0f%%", stats.CPU.Usage))
+	}
+	if sections.Has(SectionMemory) && stats.Memory.Total > 0 {
+		parts = append(parts, fmt.Sprintf("RAM %.0f%%", stats.Memory.UsedPercent))
+	}
+	if sections.Has(SectionDisk) {
+		for _, disk := range stats.Disks {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", disk.Mount, disk.UsedPercent))
+		}
+	}
+	if sections.Has(SectionNetwork) {
+		parts = append(parts, fmt.Sprintf("↑ %s/s ↓ %s/s", human(stats.Network.SentPerSec), human(stats.Network.ReceivedPerSec)))
+	}
+	if sections.Has(SectionDisk) && len(stats.IO.Devices) > 0 {
+		parts = append(parts, fmt.Sprintf("IO R %s/s W %s/s", human(stats.IO.ReadPerSec), human(stats.IO.WritePerSec)))
+	}
+	if sections.Has(SectionGPU) {
+		for _, gpu := range stats.GPU {
+			part := fmt.Sprintf("GPU%d", gpu.Index)
+			if gpu.UtilPercent >= 0 {
+				part += fmt.Sprintf(" %.0f%%", gpu.UtilPercent)
+			}
+			if gpu.Temperature >= 0 {
+				part += fmt.Sprintf(" %.0fºC", gpu.Temperature)
+			}
+			parts = append(parts, part)
+		}
+	}
+	if sections.Has(sectionOther) && stats.Host.Uptime > 0 {
+		parts = append(parts, "up "+formatUptime(stats.Host.Uptime))
+	}
+	if n := len(stats.Warnings); n > 0 {
+		parts = append(parts, fmt.Sprintf("⚠️ %d", n))
+	}
+	line := strings.Join(parts, " · ")
+	if snap.Host != "" {
+		line = snap.Host + ": " + line
+	}
+	return line, nil
+}
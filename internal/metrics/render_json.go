@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSONRenderer produces a stable JSON document for scripts and other bots.
+// Sections that were not selected are omitted; GPU readings the backend
+// cannot provide are null.
+type JSONRenderer struct {
+	Indent bool
+}
+
+type jsonStats struct {
+	Host          string           `json:"host,omitempty"`
+	Timestamp     string           `json:"timestamp,omitempty"`
+	CPU           *jsonCPU         `json:"cpu,omitempty"`
+	Memory        *jsonMemory      `json:"memory,omitempty"`
+	Disks         *[]jsonDisk      `json:"disks,omitempty"`
+	DiskIO        *jsonDiskIO      `json:"disk_io,omitempty"`
+	SMART         []jsonSMART      `json:"smart,omitempty"`
+	Network       *jsonNetwork     `json:"network,omitempty"`
+	GPUs          *[]jsonGPU       `json:"gpus,omitempty"`
+	Sensors       *jsonSensors     `json:"sensors,omitempty"`
+	UptimeSeconds *float64         `json:"uptime_seconds,omitempty"`
+	Warnings      []string         `json:"warnings"`
+	Sources       []jsonSourceInfo `json:"sources,omitempty"`
+}
+
+type jsonCPU struct {
+	UsagePercent     float64 `json:"usage_percent"`
+	Load1            float64 `json:"load1"`
+	Load5            float64 `json:"load5"`
+	Load15           float64 `json:"load15"`
+	Cores            int     `json:"cores"`
+	LoadRatioPercent float64 `json:"load_ratio_percent"`
+}
+
+type jsonMemory struct {
+	UsedBytes      uint64  `json:"used_bytes"`
+	TotalBytes     uint64  `json:"total_bytes"`
+	UsedPercent    float64 `json:"used_percent"`
+	SwapUsedBytes  uint64  `json:"swap_used_bytes"`
+	SwapTotalBytes uint64  `json:"swap_total_bytes"`
+	SwapPercent    float64 `json:"swap_percent"`
+}
+
+type jsonDisk struct {
+	Mount         string  `json:"mount"`
+	Device        string  `json:"device"`
+	UsedBytes     uint64  `json:"used_bytes"`
+	TotalBytes    uint64  `json:"total_bytes"`
+	UsedPercent   float64 `json:"used_percent"`
+	InodesUsed    uint64  `json:"inodes_used"`
+	InodesTotal   uint64  `json:"inodes_total"`
+	InodesPercent float64 `json:"inodes_percent"`
+}
+
+type jsonDiskIO struct {
+	ReadBytesPerSec  uint64         `json:"read_bytes_per_sec"`
+	WriteBytesPerSec uint64         `json:"write_bytes_per_sec"`
+	Devices          []jsonDeviceIO `json:"devices"`
+}
+
+type jsonDeviceIO struct {
+	Name             string  `json:"name"`
+	ReadBytesPerSec  uint64  `json:"read_bytes_per_sec"`
+	WriteBytesPerSec uint64  `json:"write_bytes_per_sec"`
+	ReadIOPS         float64 `json:"read_iops"`
+	WriteIOPS        float64 `json:"write_iops"`
+	UtilPercent      float64 `json:"util_percent"`
+	AwaitMillis      float64 `json:"await_ms"`
+}
+
+type jsonSMART struct {
+	Device  string   `json:"device"`
+	Model   string   `json:"model,omitempty"`
+	Passed  bool     `json:"passed"`
+	Error   string   `json:"error,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+	Celsius int      `json:"temperature_celsius,omitempty"`
+}
+
+type jsonNetwork struct {
+	SentBytesPerSec     uint64          `json:"sent_bytes_per_sec"`
+	ReceivedBytesPerSec uint64          `json:"received_bytes_per_sec"`
+	Interfaces          []jsonInterface `json:"interfaces"`
+}
+
+type jsonInterface struct {
+	Name                string `json:"name"`
+	SentBytesPerSec     uint64 `json:"sent_bytes_per_sec"`
+	ReceivedBytesPerSec uint64 `json:"received_bytes_per_sec"`
+	BytesSent           uint64 `json:"bytes_sent"`
+	BytesReceived       uint64 `json:"bytes_received"`
+}
+
+type jsonGPU struct {
+	Vendor           string   `json:"vendor"`
+	Index            int      `json:"index"`
+	Name             string   `json:"name"`
+	UtilPercent      *float64 `json:"util_percent"`
+	MemoryUsedBytes  *uint64  `json:"memory_used_bytes"`
+	MemoryTotalBytes *uint64  `json:"memory_total_bytes"`
+	Celsius          *float64 `json:"temperature_celsius"`
+	PowerWatts       *float64 `json:"power_watts"`
+	ClockMHz         *float64 `json:"clock_mhz"`
+}
+
+type jsonSensors struct {
+	Temperatures []TempReading  `json:"temperatures"`
+	Fans         []FanReading   `json:"fans"`
+	Power        []PowerReading `json:"power"`
+}
+
+type jsonSourceInfo struct {
+	Name       string  `json:"name"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func (r JSONRenderer) Render(snap Snapshot, sections Section) (string, error) {
+	stats := snap.Stats
+	out := jsonStats{Host: snap.Host, Warnings: stats.Warnings}
+	if out.Warnings == nil {
+		out.Warnings = []string{}
+	}
+	if !snap.At.IsZero() {
+		out.Timestamp = snap.At.UTC().Format(time.RFC3339)
+	}
+
+	if sections.Has(SectionCPU) {
+		out.CPU = &jsonCPU{
+			UsagePercent:     stats.CPU.Usage,
+			Load1:            stats.CPU.Load1,
+			Load5:            stats.CPU.Load5,
+			Load15:           stats.CPU.Load15,
+			Cores:            stats.CPU.Cores,
+			LoadRatioPercent: stats.CPU.LoadRatio,
+		}
+	}
+	if sections.Has(SectionMemory) {
+		m := stats.Memory
+		out.Memory = &jsonMemory{m.Used, m.Total, m.UsedPercent, m.SwapUsed, m.SwapTotal, m.SwapPercent}
+	}
+	if sections.Has(SectionDisk) {
+		disks := make([]jsonDisk, 0, len(stats.Disks))
+		for _, d := range stats.Disks {
+			disks = append(disks, jsonDisk{d.Mount, d.Device, d.Used, d.Total, d.UsedPercent, d.InodesUsed, d.InodesTotal, d.InodesPercent})
+		}
+		out.Disks = &disks
+		out.DiskIO = &jsonDiskIO{ReadBytesPerSec: stats.IO.ReadPerSec, WriteBytesPerSec: stats.IO.WritePerSec, Devices: []jsonDeviceIO{}}
+		for _, d := range stats.IO.Devices {
+			out.DiskIO.Devices = append(out.DiskIO.Devices, jsonDeviceIO{d.Name, d.ReadPerSec, d.WritePerSec, d.ReadIOPS, d.WriteIOPS, d.UtilPercent, d.Await})
+		}
+		for _, s := range stats.SMART {
+			out.SMART = append(out.SMART, jsonSMART{Device: s.Device, Model: s.Model, Passed: s.Passed, Error: s.Err, Issues: s.Problems(), Celsius: s.Temperature})
+		}
+	}
+	if sections.Has(SectionNetwork) {
+		out.Network = &jsonNetwork{SentBytesPerSec: stats.Network.SentPerSec, ReceivedBytesPerSec: stats.Network.ReceivedPerSec, Interfaces: []jsonInterface{}}
+		for _, i := range stats.Network.Interfaces {
+			out.Network.Interfaces = append(out.Network.Interfaces, jsonInterface{i.Name, i.SentPerSec, i.ReceivedPerSec, i.BytesSent, i.BytesRecv})
+		}
+	}
+	if sections.Has(SectionGPU) {
+		gpus := make([]jsonGPU, 0, len(stats.GPU))
+		for _, g := range stats.GPU {
+			gpu := jsonGPU{
+				Vendor:      g.Vendor,
+				Index:       g.Index,
+				Name:        g.Name,
+				UtilPercent: known(g.UtilPercent),
+				Celsius:     known(g.Temperature),
+				PowerWatts:  known(g.PowerWatts),
+				ClockMHz:    known(g.ClockMHz),
+			}
+			if g.MemoryTotal > 0 {
+				gpu.MemoryUsedBytes, gpu.MemoryTotalBytes = &g.MemoryUsed, &g.MemoryTotal
+			}
+			gpus = append(gpus, gpu)
+		}
+		out.GPUs = &gpus
+	}
+	if sections.Has(sectionOther) {
+		if !stats.Sensors.Empty() {
+			out.Sensors = &jsonSensors{
+				Temperatures: append([]TempReading{}, stats.Sensors.Temperatures...),
+				Fans:         append([]FanReading{}, stats.Sensors.Fans...),
+				Power:        append([]PowerReading{}, stats.Sensors.Power...),
+			}
+		}
+		if stats.Host.Uptime > 0 {
+			seconds := stats.Host.Uptime.Seconds()
+			out.UptimeSeconds = &seconds
+		}
+		for _, src := range stats.Sources {
+			info := jsonSourceInfo{Name: src.Name, DurationMS: float64(src.Duration.Microseconds()) / 1000}
+			if src.Err != nil {
+				info.Error = src.Err.Error()
+			}
+			out.Sources = append(out.Sources, info)
+		}
+	}
+
+	var data []byte
+	var err error
+	if r.Indent {
+		data, err = json.MarshalIndent(out, "", "  ")
+	} else {
+		data, err = json.Marshal(out)
+	}
+	return string(data), err
+}
+
+// known maps the -1 "unknown" marker to null.
+func known(v float64) *float64 {
+	if v < 0 {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden with the current output")
+
+// goldenSnapshot is a fixed host with every kind of reading.
+func goldenSnapshot() Snapshot {
+	return Snapshot{
+		At: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		Stats: Stats{
+			CPU: CPUStats{Usage: 72.5, Load1: 1.23, Load5: 0.98, Load15: 0.75, Cores: 8, LoadRatio: 15.4},
+			Memory: MemoryStats{
+				Used: 8 << 30, Total: 16 << 30, UsedPercent: 50,
+				SwapUsed: 512 << 20, SwapTotal: 1 << 30, SwapPercent: 50,
+			},
+			Disks: []DiskUsage{{
+				Mount: "/", Device: "/dev/nvme0n1p2", Used: 90 << 30, Total: 128 << 30, UsedPercent: 70.3,
+				InodesUsed: 512000, InodesTotal: 8192000, InodesPercent: 6.25,
+			}},
+			Network: NetworkStats{
+				SentPerSec: 12 << 10, ReceivedPerSec: 24 << 10,
+				Interfaces: []InterfaceStats{
+					{Name: "eth0", SentPerSec: 10 << 10, ReceivedPerSec: 20 << 10, BytesSent: 1 << 30, BytesRecv: 4 << 30},
+					{Name: "wg0", SentPerSec: 2 << 10, ReceivedPerSec: 4 << 10, BytesSent: 1 << 20, BytesRecv: 2 << 20},
+				},
+			},
+			IO: DiskIOStats{
+				ReadPerSec: 1 << 10, WritePerSec: 2 << 10,
+				Devices: []DeviceIO{{Name: "nvme0n1", ReadPerSec: 1 << 10, WritePerSec: 2 << 10, ReadIOPS: 3, WriteIOPS: 5, UtilPercent: 1.5, Await: 0.4}},
+			},
+			SMART: []SMARTStatus{{
+				Device: "nvme0n1", Model: "Samsung SSD 980", Passed: true, Temperature: 41, PowerOnHours: 1200,
+				Reallocated: -1, Pending: -1, Uncorrectable: -1, PercentUsed: 2, MediaErrors: 0,
+			}},
+			Sensors: SensorStats{
+				Temperatures: []TempReading{{Chip: "coretemp", Label: "Package id 0", Celsius: 52, High: 80, Critical: 100}},
+				Fans:         []FanReading{{Chip: "nct6775", Label: "fan1", RPM: 1100}},
+			},
+			GPU: []GPUStats{{
+				Vendor: "nvidia", Index: 0, Name: "RTX 4090", UtilPercent: 85,
+				MemoryUsed: 1000 << 20, MemoryTotal: 24564 << 20, Temperature: 65, PowerWatts: 250, ClockMHz: -1,
+			}},
+			Host:     HostStats{Uptime: 3*time.Hour + 4*time.Minute + 5*time.Second},
+			Warnings: []string{"SMART: sda: permission denied"},
+			Sources: []SourceReport{
+				{Name: "CPU", Duration: time.Second},
+				{Name: "GPU", Duration: 6 * time.Second, Err: errors.New("timed out after 6s")},
+			},
+		},
+	}
+}
+
+func TestRenderersGolden(t *testing.T) {
+	tests := []struct {
+		golden   string
+		renderer Renderer
+		sections Section
+	}{
+		{"html_all", HTMLRenderer{}, AllSections},
+		{"html_cpu_mem", HTMLRenderer{}, SectionCPU | SectionMemory},
+		{"html_disk", HTMLRenderer{}, SectionDisk},
+		{"html_net", HTMLRenderer{}, SectionNetwork},
+		{"json_all", JSONRenderer{Indent: true}, AllSections},
+		{"json_gpu", JSONRenderer{Indent: true}, SectionGPU},
+		{"compact_all", CompactRenderer{}, AllSections},
+		{"compact_net", CompactRenderer{}, SectionNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got, err := tt.renderer.Render(goldenSnapshot(), tt.sections)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			path := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file (run go test -update): %v", err)
+			}
+			if got+"\n" != string(want) {
+				t.Fatalf("%s output differs from %s:\n%s", tt.golden, path, got)
+			}
+		})
+	}
+}
+
+func TestParseSection(t *testing.T) {
+	for _, name := range []string{"cpu", "mem", "disk", "net", "gpu"} {
+		section, ok := ParseSection(name)
+		if !ok || !AllSections.Has(section) || section == AllSections {
+			t.Fatalf("ParseSection(%q) = %v, %v", name, section, ok)
+		}
+	}
+	if s, ok := ParseSection("all"); !ok || s != AllSections {
+		t.Fatalf("ParseSection(all) = %v, %v", s, ok)
+	}
+	if _, ok := ParseSection("sensors"); ok {
+		t.Fatal("ParseSection accepted an unknown name")
+	}
+}
+
+func TestRenderersNameRemoteHost(t *testing.T) {
+	snap := goldenSnapshot()
+	snap.Host = "nas"
+
+	htmlOut, _ := HTMLRenderer{}.Render(snap, SectionCPU)
+	if !strings.HasPrefix(htmlOut, "<b>📊 Estado de nas</b>") {
+		t.Fatalf("HTML title = %q", htmlOut)
+	}
+	compact, _ := CompactRenderer{}.Render(snap, SectionCPU)
+	if !strings.HasPrefix(compact, "nas: CPU 72%") {
+		t.Fatalf("compact = %q", compact)
+	}
+	jsonOut, _ := JSONRenderer{}.Render(snap, SectionCPU)
+	if !strings.HasPrefix(jsonOut, `{"host":"nas",`) {
+		t.Fatalf("JSON = %s", jsonOut)
+	}
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Snapshot is a collected Stats and the time the collection finished.
+type Snapshot struct {
+	Stats Stats
+	At    time.Time
+	// Host names the agent the snapshot came from; empty for this machine.
+	Host string
+}
+
+// Sampler collects in the background and keeps the latest snapshot, so
+// /stats, alerts, the digest and the exporter share one sample stream
+// instead of each running its own collection.
+type Sampler struct {
+	Collector *Collector
+	// Interval between background samples.
+	Interval time.Duration
+	// Timeout bounds one collection.
+	Timeout time.Duration
+	Logger  *log.Logger
+
+	mu       sync.Mutex
+	latest   Snapshot
+	inflight *sampleCall
+	now      func() time.Time
+}
+
+type sampleCall struct {
+	done chan struct{}
+	snap Snapshot
+	err  error
+}
+
+// Run samples immediately and then every Interval until ctx is cancelled.
+func (s *Sampler) Run(ctx context.Context) {
+	s.refresh(ctx)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *Sampler) refresh(ctx context.Context) {
+	if _, err := s.Sample(ctx); err != nil && ctx.Err() == nil {
+		s.log("sample error: %v", err)
+	}
+}
+
+// Latest returns the newest snapshot, if any sample has completed.
+func (s *Sampler) Latest() (Snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest, !s.latest.At.IsZero()
+}
+
+// Get returns the latest snapshot when it is younger than maxAge and takes
+// a new sample otherwise.
+func (s *Sampler) Get(ctx context.Context, maxAge time.Duration) (Snapshot, error) {
+	if snap, ok := s.Latest(); ok && s.clock().Sub(snap.At) < maxAge {
+		return snap, nil
+	}
+	return s.Sample(ctx)
+}
+
+// Sample collects a new snapshot. Concurrent callers share the collection
+// already in flight; it keeps running when one of them gives up, so the
+// others and the cache still receive it.
+func (s *Sampler) Sample(ctx context.Context) (Snapshot, error) {
+	s.mu.Lock()
+	call := s.inflight
+	if call == nil {
+		call = &sampleCall{done: make(chan struct{})}
+		s.inflight = call
+		go s.collect(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Snapshot{}, ctx.Err()
+	case <-call.done:
+		return call.snap, call.err
+	}
+}
+
+func (s *Sampler) collect(ctx context.Context, call *sampleCall) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	stats, err := s.Collector.Collect(ctx)
+	call.snap, call.err = Snapshot{Stats: stats, At: s.clock()}, err
+
+	s.mu.Lock()
+	if err == nil {
+		s.latest = call.snap
+	}
+	s.inflight = nil
+	s.mu.Unlock()
+	close(call.done)
+}
+
+func (s *Sampler) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *Sampler) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("metrics: "+format, args...)
+	}
+}
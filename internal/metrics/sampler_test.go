@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingCollector returns a collector whose only source counts its runs
+// and takes delay to finish.
+func countingCollector(runs *atomic.Int32, delay time.Duration) *Collector {
+	c := &Collector{options: Options{SampleInterval: time.Millisecond, SourceTimeout: time.Second}}
+	c.Register(NewSource("CPU", func(ctx context.Context, _ *Window) (Apply, error) {
+		n := runs.Add(1)
+		time.Sleep(delay)
+		return func(s *Stats) { s.CPU.Usage = float64(n) }, nil
+	}))
+	return c
+}
+
+func TestSamplerGetReusesFreshSnapshot(t *testing.T) {
+	var runs atomic.Int32
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := &Sampler{Collector: countingCollector(&runs, 0), now: func() time.Time { return now }}
+
+	if _, ok := s.Latest(); ok {
+		t.Fatal("Latest before any sample reported a snapshot")
+	}
+	first, err := s.Get(context.Background(), time.Minute)
+	if err != nil || first.Stats.CPU.Usage != 1 || !first.At.Equal(now) {
+		t.Fatalf("first Get = %+v, %v", first, err)
+	}
+
+	now = now.Add(30 * time.Second)
+	cached, _ := s.Get(context.Background(), time.Minute)
+	if cached.Stats.CPU.Usage != 1 || runs.Load() != 1 {
+		t.Fatalf("Get within maxAge collected again (runs=%d)", runs.Load())
+	}
+
+	now = now.Add(time.Minute)
+	stale, _ := s.Get(context.Background(), time.Minute)
+	if stale.Stats.CPU.Usage != 2 {
+		t.Fatalf("Get past maxAge = %+v, want a new sample", stale.Stats.CPU)
+	}
+	if latest, ok := s.Latest(); !ok || latest.Stats.CPU.Usage != 2 {
+		t.Fatalf("Latest = %+v, %v", latest, ok)
+	}
+}
+
+func TestSamplerSharesInflightSample(t *testing.T) {
+	var runs atomic.Int32
+	s := &Sampler{Collector: countingCollector(&runs, 50*time.Millisecond)}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Sample(context.Background()); err != nil {
+				t.Errorf("Sample: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("concurrent Sample calls collected %d times, want 1", got)
+	}
+}
+
+func TestSamplerCancelledCallerKeepsSample(t *testing.T) {
+	var runs atomic.Int32
+	s := &Sampler{Collector: countingCollector(&runs, 50*time.Millisecond)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Sample(ctx); err == nil {
+		t.Fatal("Sample with expired context returned no error")
+	}
+
+	snap, err := s.Sample(context.Background())
+	if err != nil || snap.Stats.CPU.Usage != 1 || runs.Load() != 1 {
+		t.Fatalf("second Sample = %+v, %v (runs=%d), want the shared first sample", snap.Stats.CPU, err, runs.Load())
+	}
+}
@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// hwmonRoot is replaced in tests with a fake sysfs tree.
+var hwmonRoot = "/sys/class/hwmon"
+
+// SensorStats are the hwmon readings of the host.
+type SensorStats struct {
+	Temperatures []TempReading
+	Fans         []FanReading
+	Power        []PowerReading
+}
+
+// TempReading is one temperature input. High and Critical are the limits the
+// chip reports, 0 when unknown.
+type TempReading struct {
+	Chip     string  `json:"chip"`
+	Label    string  `json:"label"`
+	Celsius  float64 `json:"celsius"`
+	High     float64 `json:"high,omitempty"`
+	Critical float64 `json:"critical,omitempty"`
+}
+
+// FanReading is one fan speed input.
+type FanReading struct {
+	Chip  string  `json:"chip"`
+	Label string  `json:"label"`
+	RPM   float64 `json:"rpm"`
+}
+
+// PowerReading is one power input.
+type PowerReading struct {
+	Chip  string  `json:"chip"`
+	Label string  `json:"label"`
+	Watts float64 `json:"watts"`
+}
+
+// Empty reports whether no sensor was found.
+func (s SensorStats) Empty() bool {
+	return len(s.Temperatures) == 0 && len(s.Fans) == 0 && len(s.Power) == 0
+}
+
+// Name identifies a reading as "chip label".
+func (t TempReading) Name() string {
+	return t.Chip + " " + t.Label
+}
+
+type hwmonChip struct {
+	dir    string
+	name   string
+	device string
+}
+
+// collectSensors reads every hwmon chip. Hosts without hwmon (containers,
+// most VMs) yield empty stats rather than an error.
+func collectSensors(ctx context.Context) (SensorStats, error) {
+	if err := ctx.Err(); err != nil {
+		return SensorStats{}, err
+	}
+
+	entries, err := os.ReadDir(hwmonRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SensorStats{}, nil
+		}
+		return SensorStats{}, err
+	}
+
+	var chips []hwmonChip
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		chip, ok := readChip(filepath.Join(hwmonRoot, entry.Name()))
+		if ok {
+			chips = append(chips, chip)
+			counts[chip.name]++
+		}
+	}
+	sort.Slice(chips, func(i, j int) bool { return chips[i].dir < chips[j].dir })
+
+	var stats SensorStats
+	for _, chip := range chips {
+		label := chip.name
+		// Two NVMe drives both call themselves "nvme"; use the device instead.
+		if counts[chip.name] > 1 && chip.device != "" {
+			label = chip.device
+		}
+		readInputs(chip.dir, label, &stats)
+	}
+	return stats, nil
+}
+
+// readChip resolves the attribute directory and name of a hwmon entry. Old
+// drivers keep attributes under device/.
+func readChip(dir string) (hwmonChip, bool) {
+	chip := hwmonChip{dir: dir}
+	if dev, err := filepath.EvalSymlinks(filepath.Join(dir, "device")); err == nil {
+		chip.device = filepath.Base(dev)
+	}
+
+	for _, candidate := range []string{dir, filepath.Join(dir, "device")} {
+		if name, ok := readSysString(filepath.Join(candidate, "name")); ok {
+			chip.name = name
+			inputs, _ := filepath.Glob(filepath.Join(candidate, "*_input"))
+			averages, _ := filepath.Glob(filepath.Join(candidate, "power*_average"))
+			if len(inputs)+len(averages) > 0 {
+				chip.dir = candidate
+				return chip, true
+			}
+		}
+	}
+	return chip, false
+}
+
+func readInputs(dir, chip string, stats *SensorStats) {
+	inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+	sort.Slice(inputs, func(i, j int) bool { return naturalLess(filepath.Base(inputs[i]), filepath.Base(inputs[j])) })
+
+	for _, input := range inputs {
+		prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+		raw, ok := readSysFloat(input)
+		if !ok {
+			continue
+		}
+		label, ok := readSysString(filepath.Join(dir, prefix+"_label"))
+		if !ok {
+			label = prefix
+		}
+		attr := func(suffix string) float64 {
+			v, _ := readSysFloat(filepath.Join(dir, prefix+"_"+suffix))
+			return v
+		}
+
+		switch {
+		case strings.HasPrefix(prefix, "temp"):
+			stats.Temperatures = append(stats.Temperatures, TempReading{
+				Chip: chip, Label: label, Celsius: raw / 1000, High: attr("max") / 1000, Critical: attr("crit") / 1000,
+			})
+		case strings.HasPrefix(prefix, "fan"):
+			stats.Fans = append(stats.Fans, FanReading{Chip: chip, Label: label, RPM: raw})
+		case strings.HasPrefix(prefix, "power"):
+			stats.Power = append(stats.Power, PowerReading{Chip: chip, Label: label, Watts: raw / 1e6})
+		}
+	}
+
+	// Some drivers (amdgpu) only expose power*_average.
+	averages, _ := filepath.Glob(filepath.Join(dir, "power*_average"))
+	for _, avg := range averages {
+		prefix := strings.TrimSuffix(filepath.Base(avg), "_average")
+		if _, err := os.Stat(filepath.Join(dir, prefix+"_input")); err == nil {
+			continue
+		}
+		raw, ok := readSysFloat(avg)
+		if !ok {
+			continue
+		}
+		label, ok := readSysString(filepath.Join(dir, prefix+"_label"))
+		if !ok {
+			label = prefix
+		}
+		stats.Power = append(stats.Power, PowerReading{Chip: chip, Label: label, Watts: raw / 1e6})
+	}
+}
+
+// naturalLess orders temp2_input before temp10_input.
+func naturalLess(a, b string) bool {
+	ka, na := splitTrailingNumber(strings.TrimSuffix(a, "_input"))
+	kb, nb := splitTrailingNumber(strings.TrimSuffix(b, "_input"))
+	if ka != kb {
+		return ka < kb
+	}
+	return na < nb
+}
+
+func splitTrailingNumber(s string) (string, int) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(s[i:])
+	return s[:i], n
+}
+
+func readSysString(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	s := strings.TrimSpace(string(data))
+	return s, s != ""
+}
+
+func readSysFloat(path string) (float64, bool) {
+	s, ok := readSysString(path)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// sensorLines renders one line per chip for each kind of reading.
+func sensorLines(lang string, s SensorStats) []string {
+	var lines []string
+
+	type group struct {
+		chip  string
+		parts []string
+	}
+	appendGroup := func(groups []group, chip, part string) []group {
+		if n := len(groups); n > 0 && groups[n-1].chip == chip {
+			groups[n-1].parts = append(groups[n-1].parts, part)
+			return groups
+		}
+		return append(groups, group{chip: chip, parts: []string{part}})
+	}
+
+	var temps []group
+	for _, t := range s.Temperatures {
+		temps = appendGroup(temps, t.Chip, fmt.Sprintf("%s %.0fºC", t.Label, t.Celsius))
+	}
+	for _, g := range temps {
+		lines = append(lines, g.chip+": "+strings.Join(g.parts, ", "))
+	}
+
+	var fans []group
+	for _, f := range s.Fans {
+		fans = appendGroup(fans, f.Chip, fmt.Sprintf("%s %.0f RPM", f.Label, f.RPM))
+	}
+	for _, g := range fans {
+		lines = append(lines, i18n.T(lang, "stats.fans", g.chip, strings.Join(g.parts, ", ")))
+	}
+
+	var power []group
+	for _, p := range s.Power {
+		power = appendGroup(power, p.Chip, fmt.Sprintf("%s %.1fW", p.Label, p.Watts))
+	}
+	for _, g := range power {
+		lines = append(lines, i18n.T(lang, "stats.power", g.chip, strings.Join(g.parts, ", ")))
+	}
+	return lines
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeHwmon lays out class/hwmon/hwmonN entries pointing at device dirs, as
+// sysfs does. files maps "hwmonN/attr" (or "hwmonN/device/attr") to content.
+func fakeHwmon(t *testing.T, devices map[string]string, files map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	class := filepath.Join(root, "class", "hwmon")
+	if err := os.MkdirAll(class, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for hwmon, device := range devices {
+		devDir := filepath.Join(root, "devices", device)
+		if err := os.MkdirAll(devDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		dir := filepath.Join(class, hwmon)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(devDir, filepath.Join(dir, "device")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for rel, content := range files {
+		path := filepath.Join(class, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prev := hwmonRoot
+	hwmonRoot = class
+	t.Cleanup(func() { hwmonRoot = prev })
+}
+
+func TestCollectSensors(t *testing.T) {
+	fakeHwmon(t,
+		map[string]string{
+			"hwmon0": "platform/coretemp.0",
+			"hwmon1": "pci/nvme0",
+			"hwmon2": "pci/nvme1",
+			"hwmon3": "platform/nct6775.656",
+			"hwmon4": "pci/amdgpu",
+			"hwmon5": "virtual/legacy",
+		},
+		map[string]string{
+			"hwmon0/name":         "coretemp",
+			"hwmon0/temp1_input":  "54000",
+			"hwmon0/temp1_label":  "Package id 0",
+			"hwmon0/temp1_max":    "80000",
+			"hwmon0/temp1_crit":   "100000",
+			"hwmon0/temp2_input":  "50000",
+			"hwmon0/temp2_label":  "Core 0",
+			"hwmon0/temp10_input": "51000",
+			"hwmon0/temp10_label": "Core 8",
+
+			"hwmon1/name":        "nvme",
+			"hwmon1/temp1_input": "38850",
+			"hwmon1/temp1_label": "Composite",
+			"hwmon2/name":        "nvme",
+			"hwmon2/temp1_input": "41850",
+			"hwmon2/temp1_label": "Composite",
+
+			"hwmon3/name":       "nct6775",
+			"hwmon3/fan1_input": "1180",
+			"hwmon3/fan2_input": "0",
+			"hwmon3/fan2_label": "Chassis",
+
+			"hwmon4/name":           "amdgpu",
+			"hwmon4/power1_average": "35000000",
+			"hwmon4/power1_label":   "PPT",
+
+			// Attributes under device/ on old drivers.
+			"hwmon5/device/name":        "acpitz",
+			"hwmon5/device/temp1_input": "27800",
+		},
+	)
+
+	got, err := collectSensors(context.Background())
+	if err != nil {
+		t.Fatalf("collectSensors() error = %v", err)
+	}
+
+	wantTemps := []TempReading{
+		{Chip: "coretemp", Label: "Package id 0", Celsius: 54, High: 80, Critical: 100},
+		{Chip: "coretemp", Label: "Core 0", Celsius: 50},
+		{Chip: "coretemp", Label: "Core 8", Celsius: 51},
+		{Chip: "nvme0", Label: "Composite", Celsius: 38.85},
+		{Chip: "nvme1", Label: "Composite", Celsius: 41.85},
+		{Chip: "acpitz", Label: "temp1", Celsius: 27.8},
+	}
+	if !reflect.DeepEqual(got.Temperatures, wantTemps) {
+		t.Fatalf("Temperatures =\n%+v\nwant\n%+v", got.Temperatures, wantTemps)
+	}
+	wantFans := []FanReading{{Chip: "nct6775", Label: "fan1", RPM: 1180}, {Chip: "nct6775", Label: "Chassis", RPM: 0}}
+	if !reflect.DeepEqual(got.Fans, wantFans) {
+		t.Fatalf("Fans = %+v", got.Fans)
+	}
+	if len(got.Power) != 1 || got.Power[0] != (PowerReading{Chip: "amdgpu", Label: "PPT", Watts: 35}) {
+		t.Fatalf("Power = %+v", got.Power)
+	}
+
+	output := FormatHTML(Stats{Sensors: got})
+	for _, needle := range []string{
+		"🌡️ <b>Sensores</b>",
+		"• coretemp: Package id 0 54ºC, Core 0 50ºC, Core 8 51ºC",
+		"• nvme0: Composite 39ºC",
+		"• nvme1: Composite 42ºC",
+		"• Ventiladores nct6775: fan1 1180 RPM, Chassis 0 RPM",
+		"• Potencia amdgpu: PPT 35.0W",
+	} {
+		if !strings.Contains(output, needle) {
+			t.Fatalf("FormatHTML output missing %q:\n%s", needle, output)
+		}
+	}
+}
+
+func TestCollectSensorsWithoutHwmon(t *testing.T) {
+	prev := hwmonRoot
+	hwmonRoot = filepath.Join(t.TempDir(), "missing")
+	t.Cleanup(func() { hwmonRoot = prev })
+
+	got, err := collectSensors(context.Background())
+	if err != nil || !got.Empty() {
+		t.Fatalf("collectSensors() = %+v, %v", got, err)
+	}
+	if strings.Contains(FormatHTML(Stats{}), "Sensores") {
+		t.Fatalf("empty sensors should not render a section")
+	}
+}
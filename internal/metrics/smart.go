@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// SMARTStatus is the health summary smartctl reports for one disk. Counters
+// that the drive does not expose are -1.
+type SMARTStatus struct {
+	Device        string
+	Model         string
+	Passed        bool
+	Temperature   int
+	PowerOnHours  int
+	Reallocated   int64
+	Pending       int64
+	Uncorrectable int64
+	PercentUsed   int // NVMe wear
+	MediaErrors   int64
+	// Err explains why no health data is available (standby, permissions,
+	// unsupported device).
+	Err string
+}
+
+// lookSmartctl is replaced in tests; a missing smartctl disables SMART.
+var lookSmartctl = func() (string, error) { return exec.LookPath("smartctl") }
+
+// runSmartctl is replaced in tests to return fixture output.
+var runSmartctl = func(ctx context.Context, path, device string) ([]byte, error) {
+	// -n standby keeps sleeping disks asleep; smartctl then exits non-zero.
+	return exec.CommandContext(ctx, path, "--json", "-n", "standby", "-i", "-H", "-A", device).Output()
+}
+
+type smartctlOutput struct {
+	Smartctl struct {
+		Messages []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	ModelName   string `json:"model_name"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours int `json:"hours"`
+	} `json:"power_on_time"`
+	ATAAttributes struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeLog *struct {
+		PercentageUsed int   `json:"percentage_used"`
+		MediaErrors    int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// ParseSmartctlJSON reads the output of smartctl --json -i -H -A. Output
+// without a health verdict yields a status with Err set from smartctl's
+// messages; malformed JSON is an error.
+func ParseSmartctlJSON(device string, data []byte) (SMARTStatus, error) {
+	var out smartctlOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return SMARTStatus{}, fmt.Errorf("parse smartctl output for %s: %w", device, err)
+	}
+
+	status := SMARTStatus{
+		Device:        device,
+		Model:         strings.TrimSpace(out.ModelName),
+		Temperature:   out.Temperature.Current,
+		PowerOnHours:  out.PowerOnTime.Hours,
+		Reallocated:   -1,
+		Pending:       -1,
+		Uncorrectable: -1,
+		PercentUsed:   -1,
+		MediaErrors:   -1,
+	}
+
+	if out.SmartStatus == nil {
+		var msgs []string
+		for _, m := range out.Smartctl.Messages {
+			msgs = append(msgs, strings.TrimSpace(m.String))
+		}
+		status.Err = strings.Join(msgs, "; ")
+		if status.Err == "" {
+			status.Err = "no health data"
+		}
+		return status, nil
+	}
+
+	status.Passed = out.SmartStatus.Passed
+	for _, attr := range out.ATAAttributes.Table {
+		switch attr.ID {
+		case 5:
+			status.Reallocated = attr.Raw.Value
+		case 197:
+			status.Pending = attr.Raw.Value
+		case 198:
+			status.Uncorrectable = attr.Raw.Value
+		}
+	}
+	if out.NVMeLog != nil {
+		status.PercentUsed = out.NVMeLog.PercentageUsed
+		status.MediaErrors = out.NVMeLog.MediaErrors
+	}
+	return status, nil
+}
+
+// collectSMART queries every device. A missing smartctl is not an error: the
+// section is simply absent.
+func collectSMART(ctx context.Context, devices []string) ([]SMARTStatus, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	path, err := lookSmartctl()
+	if err != nil {
+		return nil, nil
+	}
+
+	var statuses []SMARTStatus
+	var errs []error
+	for _, name := range devices {
+		output, runErr := runSmartctl(ctx, path, "/dev/"+name)
+		if len(output) == 0 {
+			if runErr == nil {
+				runErr = errors.New("empty output")
+			}
+			statuses = append(statuses, SMARTStatus{Device: name, Err: runErr.Error()})
+			continue
+		}
+		// smartctl encodes drive problems in its exit status; the JSON
+		// still describes them, so only a parse failure is an error.
+		status, err := ParseSmartctlJSON(name, output)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, errors.Join(errs...)
+}
+
+// Problems lists the reasons a disk needs attention: a failed verdict or
+// non-zero error counters, in the default language.
+func (s SMARTStatus) Problems() []string {
+	return s.problems("")
+}
+
+func (s SMARTStatus) problems(lang string) []string {
+	var problems []string
+	if s.Err == "" && !s.Passed {
+		problems = append(problems, i18n.T(lang, "stats.smart_failed"))
+	}
+	counters := []struct {
+		key   string
+		value int64
+	}{
+		{"stats.smart_reallocated", s.Reallocated},
+		{"stats.smart_pending", s.Pending},
+		{"stats.smart_uncorrectable", s.Uncorrectable},
+		{"stats.smart_media_errors", s.MediaErrors},
+	}
+	for _, c := range counters {
+		if c.value > 0 {
+			problems = append(problems, i18n.T(lang, c.key, c.value))
+		}
+	}
+	return problems
+}
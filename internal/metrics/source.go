@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultSourceTimeout bounds each source beyond the sampling window.
+const DefaultSourceTimeout = 5 * time.Second
+
+// Source contributes one part of a Stats snapshot. Collect runs concurrently
+// with the other sources and returns a function that stores its readings;
+// Collect applies those in registration order, so Apply needs no locking.
+type Source interface {
+	// Name labels the source in warnings ("Red: ...") and reports.
+	Name() string
+	Collect(ctx context.Context, window *Window) (Apply, error)
+}
+
+// Apply stores a source's readings in the snapshot.
+type Apply func(*Stats)
+
+// NewSource adapts a function to the Source interface.
+func NewSource(name string, collect func(ctx context.Context, window *Window) (Apply, error)) Source {
+	return sourceFunc{name: name, collect: collect}
+}
+
+type sourceFunc struct {
+	name    string
+	collect func(context.Context, *Window) (Apply, error)
+}
+
+func (s sourceFunc) Name() string { return s.name }
+
+func (s sourceFunc) Collect(ctx context.Context, window *Window) (Apply, error) {
+	return s.collect(ctx, window)
+}
+
+// Window is the sampling interval shared by every source of one collection.
+// Differential sources take a first reading, Wait, and take the second, so
+// rates, CPU usage and IO all describe the same second.
+type Window struct {
+	interval time.Duration
+	done     chan struct{}
+}
+
+func newWindow(interval time.Duration) *Window {
+	w := &Window{interval: interval, done: make(chan struct{})}
+	time.AfterFunc(interval, func() { close(w.done) })
+	return w
+}
+
+// Interval is the configured window length.
+func (w *Window) Interval() time.Duration {
+	return w.interval
+}
+
+// Wait blocks until the window closes or ctx is done.
+func (w *Window) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.done:
+		return nil
+	}
+}
+
+// SourceReport records how one source fared in a collection.
+type SourceReport struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// ErrSourceTimeout marks a source that did not return within its deadline.
+var ErrSourceTimeout = errors.New("timed out")
+
+// Register adds a source to every following collection.
+func (c *Collector) Register(s Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, s)
+}
+
+func (c *Collector) registered() []Source {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Source(nil), c.sources...)
+}
+
+type sourceResult struct {
+	apply    Apply
+	err      error
+	duration time.Duration
+}
+
+// runSource collects s with its own deadline. A source that ignores its
+// context is abandoned when the deadline passes; its late result is dropped.
+func (c *Collector) runSource(ctx context.Context, s Source, window *Window) sourceResult {
+	timeout := window.Interval() + c.options.SourceTimeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan sourceResult, 1)
+	go func() {
+		apply, err := s.Collect(ctx, window)
+		done <- sourceResult{apply: apply, err: err}
+	}()
+
+	var res sourceResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res = sourceResult{err: ctx.Err()}
+	}
+	if errors.Is(res.err, context.DeadlineExceeded) {
+		res.err = fmt.Errorf("%w after %s", ErrSourceTimeout, timeout)
+	}
+	res.duration = time.Since(start)
+	return res
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollectRunsSourcesConcurrently(t *testing.T) {
+	c := &Collector{options: Options{SampleInterval: 50 * time.Millisecond, SourceTimeout: 100 * time.Millisecond}}
+
+	windowed := func(name string, apply Apply) Source {
+		return NewSource(name, func(ctx context.Context, w *Window) (Apply, error) {
+			if err := w.Wait(ctx); err != nil {
+				return nil, err
+			}
+			return apply, nil
+		})
+	}
+	hung := make(chan struct{})
+	t.Cleanup(func() { close(hung) })
+
+	c.Register(windowed("CPU", func(s *Stats) { s.CPU.Usage = 12 }))
+	c.Register(NewSource("GPU", func(context.Context, *Window) (Apply, error) {
+		<-hung // ignores its context, like a stuck nvidia-smi
+		return func(s *Stats) { s.GPU = []GPUStats{{Name: "late"}} }, nil
+	}))
+	c.Register(NewSource("Host", func(context.Context, *Window) (Apply, error) {
+		return nil, errors.New("boom")
+	}))
+	c.Register(windowed("Red", func(s *Stats) { s.Network.SentPerSec = 7 }))
+
+	start := time.Now()
+	stats, err := c.Collect(context.Background())
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	// Two 50ms windowed sources in sequence plus the 150ms deadline would
+	// exceed 250ms; in parallel the hung source bounds the collection.
+	if elapsed < 150*time.Millisecond || elapsed > 250*time.Millisecond {
+		t.Fatalf("Collect took %v, want about 150ms", elapsed)
+	}
+	if stats.CPU.Usage != 12 || stats.Network.SentPerSec != 7 || stats.GPU != nil {
+		t.Fatalf("stats = %+v", stats)
+	}
+	wantWarnings := []string{"GPU: timed out after 150ms", "Host: boom"}
+	if !reflect.DeepEqual(stats.Warnings, wantWarnings) {
+		t.Fatalf("Warnings = %q, want %q", stats.Warnings, wantWarnings)
+	}
+
+	if len(stats.Sources) != 4 {
+		t.Fatalf("Sources = %+v", stats.Sources)
+	}
+	for i, name := range []string{"CPU", "GPU", "Host", "Red"} {
+		if stats.Sources[i].Name != name {
+			t.Fatalf("Sources[%d] = %q, want %q", i, stats.Sources[i].Name, name)
+		}
+	}
+	if !errors.Is(stats.Sources[1].Err, ErrSourceTimeout) || stats.Sources[0].Err != nil {
+		t.Fatalf("source errors = %v / %v", stats.Sources[0].Err, stats.Sources[1].Err)
+	}
+}
+
+func TestCollectCancelled(t *testing.T) {
+	c := &Collector{options: Options{SampleInterval: time.Hour, SourceTimeout: time.Second}}
+	c.Register(NewSource("Red", func(ctx context.Context, w *Window) (Apply, error) {
+		return nil, w.Wait(ctx)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stats, _ := c.Collect(ctx)
+	if len(stats.Warnings) != 1 || stats.Warnings[0] != "Red: context canceled" {
+		t.Fatalf("Warnings = %q", stats.Warnings)
+	}
+}
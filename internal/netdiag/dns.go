@@ -0,0 +1,98 @@
+package netdiag
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DNSTypes lists the record types Lookup supports.
+var DNSTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "PTR"}
+
+// DNSResult holds the answers of a lookup.
+type DNSResult struct {
+	Name    string
+	Type    string
+	Server  string // empty for the system resolver
+	Records []string
+	Latency time.Duration
+}
+
+// Lookup resolves name with Go's resolver. When server is set ("1.1.1.1" or
+// "1.1.1.1:53") every query is sent there instead of the system resolver.
+func Lookup(ctx context.Context, name, recordType, server string) (DNSResult, error) {
+	recordType = strings.ToUpper(recordType)
+	if recordType == "" {
+		recordType = "A"
+		if net.ParseIP(name) != nil {
+			recordType = "PTR"
+		}
+	}
+
+	resolver := net.DefaultResolver
+	if server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	res := DNSResult{Name: name, Type: recordType, Server: server}
+	start := time.Now()
+	var err error
+
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		var ips []net.IP
+		ips, err = resolver.LookupIP(ctx, network, name)
+		for _, ip := range ips {
+			res.Records = append(res.Records, ip.String())
+		}
+	case "CNAME":
+		var cname string
+		cname, err = resolver.LookupCNAME(ctx, name)
+		if cname != "" {
+			res.Records = []string{cname}
+		}
+	case "MX":
+		var mxs []*net.MX
+		mxs, err = resolver.LookupMX(ctx, name)
+		for _, mx := range mxs {
+			res.Records = append(res.Records, fmt.Sprintf("%d %s", mx.Pref, mx.Host))
+		}
+	case "NS":
+		var nss []*net.NS
+		nss, err = resolver.LookupNS(ctx, name)
+		for _, ns := range nss {
+			res.Records = append(res.Records, ns.Host)
+		}
+	case "TXT":
+		res.Records, err = resolver.LookupTXT(ctx, name)
+	case "PTR":
+		res.Records, err = resolver.LookupAddr(ctx, name)
+	default:
+		return DNSResult{}, fmt.Errorf("unsupported record type %q", recordType)
+	}
+	res.Latency = time.Since(start)
+
+	if err != nil {
+		return res, err
+	}
+	if recordType != "MX" {
+		sort.Strings(res.Records)
+	}
+	return res, nil
+}
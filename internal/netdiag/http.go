@@ -0,0 +1,108 @@
+package netdiag
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// maxRedirects bounds the redirect chain followed by HTTPCheck.
+const maxRedirects = 10
+
+// Redirect is one hop of a redirect chain.
+type Redirect struct {
+	URL    string
+	Status int
+}
+
+// TLSInfo describes the leaf certificate served by the final URL.
+type TLSInfo struct {
+	Subject  string
+	Issuer   string
+	NotAfter time.Time
+}
+
+// DaysLeft returns the whole days until the certificate expires.
+func (t TLSInfo) DaysLeft(now time.Time) int {
+	return int(t.NotAfter.Sub(now).Hours() / 24)
+}
+
+// HTTPResult summarizes an HTTP check.
+type HTTPResult struct {
+	URL       string
+	FinalURL  string
+	Status    int
+	Latency   time.Duration
+	Redirects []Redirect
+	TLS       *TLSInfo
+}
+
+// HTTPCheck performs a GET on rawURL following up to maxRedirects redirects.
+// Latency covers the whole chain up to the final response headers.
+func HTTPCheck(ctx context.Context, rawURL string, client *http.Client) (HTTPResult, error) {
+	res := HTTPResult{URL: rawURL}
+
+	if client == nil {
+		client = &http.Client{}
+	}
+	checked := *client
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		prev := via[len(via)-1]
+		res.Redirects = append(res.Redirects, Redirect{URL: prev.URL.String(), Status: req.Response.StatusCode})
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return res, err
+	}
+	req.Header.Set("User-Agent", "serverbot-http-check")
+
+	start := time.Now()
+	resp, err := checked.Do(req)
+	res.Latency = time.Since(start)
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.CopyN(io.Discard, resp.Body, 64<<10)
+
+	res.Status = resp.StatusCode
+	res.FinalURL = resp.Request.URL.String()
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		leaf := resp.TLS.PeerCertificates[0]
+		res.TLS = &TLSInfo{
+			Subject:  leaf.Subject.CommonName,
+			Issuer:   leaf.Issuer.CommonName,
+			NotAfter: leaf.NotAfter,
+		}
+	}
+	return res, nil
+}
+
+// PortCheck opens a TCP connection to hostport and reports how long it took.
+func PortCheck(ctx context.Context, hostport string) (time.Duration, error) {
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", hostport)
+	elapsed := time.Since(start)
+	if err != nil {
+		return elapsed, err
+	}
+	return elapsed, conn.Close()
+}
+
+// IsTLSError reports whether err comes from certificate verification, so a
+// caller can tell an expired or untrusted certificate from a network error.
+func IsTLSError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	return errors.As(err, &certErr)
+}
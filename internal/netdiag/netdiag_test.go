@@ -0,0 +1,150 @@
+package netdiag
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateHost(t *testing.T) {
+	valid := map[string]string{
+		"Example.COM":  "example.com",
+		"8.8.8.8":      "8.8.8.8",
+		"[::1]":        "::1",
+		"my_host.lan.": "my_host.lan",
+	}
+	for raw, want := range valid {
+		if got, err := ValidateHost(raw); err != nil || got != want {
+			t.Fatalf("ValidateHost(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "-f", "--help", "a b", "host;id", "-example.com", "exa$mple.com", "a..b"} {
+		if _, err := ValidateHost(raw); !errors.Is(err, ErrInvalidTarget) {
+			t.Fatalf("ValidateHost(%q) accepted", raw)
+		}
+	}
+}
+
+func TestValidateHostPortAndURL(t *testing.T) {
+	if got, err := ValidateHostPort("example.com:443"); err != nil || got != "example.com:443" {
+		t.Fatalf("ValidateHostPort() = %q, %v", got, err)
+	}
+	for _, raw := range []string{"example.com", "example.com:0", "-x:22", "example.com:http"} {
+		if _, err := ValidateHostPort(raw); err == nil {
+			t.Fatalf("ValidateHostPort(%q) accepted", raw)
+		}
+	}
+
+	if got, err := ValidateURL("example.com/health"); err != nil || got != "https://example.com/health" {
+		t.Fatalf("ValidateURL() = %q, %v", got, err)
+	}
+	for _, raw := range []string{"ftp://example.com", "http://user:pw@example.com", "http://-x/", "file:///etc/passwd"} {
+		if _, err := ValidateURL(raw); err == nil {
+			t.Fatalf("ValidateURL(%q) accepted", raw)
+		}
+	}
+}
+
+func TestParsePing(t *testing.T) {
+	linux := `PING 8.8.8.8 (8.8.8.8) 56(84) bytes of data.
+64 bytes from 8.8.8.8: icmp_seq=1 ttl=117 time=10.2 ms
+
+--- 8.8.8.8 ping statistics ---
+4 packets transmitted, 3 received, 25% packet loss, time 3004ms
+rtt min/avg/max/mdev = 10.123/11.456/12.789/0.512 ms
+`
+	s, ok := ParsePing(linux)
+	if !ok || s.Transmitted != 4 || s.Received != 3 || s.LossPercent != 25 || s.Min != 10.123 || s.Avg != 11.456 || s.Max != 12.789 {
+		t.Fatalf("ParsePing(linux) = %+v, %v", s, ok)
+	}
+
+	mac := `4 packets transmitted, 4 packets received, 0.0% packet loss
+round-trip min/avg/max/stddev = 1.1/2.2/3.3/0.4 ms`
+	s, ok = ParsePing(mac)
+	if !ok || s.Received != 4 || s.Max != 3.3 {
+		t.Fatalf("ParsePing(mac) = %+v, %v", s, ok)
+	}
+	if got := strings.Join(s.Lines(""), "|"); got != "Enviados 4 - Recibidos 4 - Perdida 0%|RTT min/avg/max: 1.1 / 2.2 / 3.3 ms" {
+		t.Fatalf("Lines() = %q", got)
+	}
+
+	lost, ok := ParsePing("4 packets transmitted, 0 received, 100% packet loss, time 3060ms")
+	if !ok || lost.LossPercent != 100 || len(lost.Lines("")) != 1 {
+		t.Fatalf("ParsePing(lost) = %+v, %v", lost, ok)
+	}
+
+	if _, ok := ParsePing("ping: unknown host"); ok {
+		t.Fatalf("ParsePing should fail without statistics")
+	}
+}
+
+func TestParseTraceroute(t *testing.T) {
+	out := `traceroute to 8.8.8.8 (8.8.8.8), 20 hops max, 60 byte packets
+ 1  192.168.1.1  0.512 ms
+ 2  *
+ 3  10.0.0.1  8.301 ms
+`
+	hops := ParseTraceroute(out)
+	if len(hops) != 3 {
+		t.Fatalf("hops = %+v", hops)
+	}
+	if hops[0] != (Hop{TTL: 1, Addr: "192.168.1.1", RTT: 0.512}) || hops[1] != (Hop{TTL: 2}) || hops[2].Addr != "10.0.0.1" {
+		t.Fatalf("hops = %+v", hops)
+	}
+}
+
+func TestHTTPCheckFollowsRedirectsAndReadsTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	res, err := HTTPCheck(context.Background(), srv.URL+"/old", srv.Client())
+	if err != nil {
+		t.Fatalf("HTTPCheck() error = %v", err)
+	}
+	if res.Status != http.StatusNoContent || res.FinalURL != srv.URL+"/new" {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(res.Redirects) != 1 || res.Redirects[0].Status != http.StatusMovedPermanently || res.Redirects[0].URL != srv.URL+"/old" {
+		t.Fatalf("redirects = %+v", res.Redirects)
+	}
+	if res.TLS == nil || res.TLS.NotAfter.Before(time.Now()) {
+		t.Fatalf("TLS = %+v", res.TLS)
+	}
+}
+
+func TestHTTPCheckReportsUntrustedCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, err := HTTPCheck(context.Background(), srv.URL, &http.Client{})
+	if err == nil || !IsTLSError(err) {
+		t.Fatalf("HTTPCheck() error = %v, want TLS verification error", err)
+	}
+}
+
+func TestPortCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	if _, err := PortCheck(context.Background(), addr); err != nil {
+		t.Fatalf("PortCheck(open) error = %v", err)
+	}
+	ln.Close()
+	if _, err := PortCheck(context.Background(), addr); err == nil {
+		t.Fatalf("PortCheck(closed) should fail")
+	}
+}
@@ -0,0 +1,61 @@
+package netdiag
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"serverbot/internal/i18n"
+)
+
+// PingSummary is the parsed statistics block of ping(8).
+type PingSummary struct {
+	Transmitted int
+	Received    int
+	LossPercent float64
+
+	// Round-trip times in milliseconds; zero when no reply arrived.
+	Min float64
+	Avg float64
+	Max float64
+}
+
+var (
+	pingPacketsPattern = regexp.MustCompile(`(\d+) packets transmitted, (\d+) (?:packets )?received.*?([\d.]+)% packet loss`)
+	pingRTTPattern     = regexp.MustCompile(`(?:rtt|round-trip) min/avg/max(?:/\w+)? = ([\d.]+)/([\d.]+)/([\d.]+)`)
+)
+
+// ParsePing extracts the statistics of iputils, BSD/macOS and busybox ping
+// output. It reports false when no statistics block is present.
+func ParsePing(out string) (PingSummary, bool) {
+	m := pingPacketsPattern.FindStringSubmatch(out)
+	if m == nil {
+		return PingSummary{}, false
+	}
+
+	var s PingSummary
+	s.Transmitted, _ = strconv.Atoi(m[1])
+	s.Received, _ = strconv.Atoi(m[2])
+	s.LossPercent, _ = strconv.ParseFloat(m[3], 64)
+
+	if rtt := pingRTTPattern.FindStringSubmatch(out); rtt != nil {
+		s.Min, _ = strconv.ParseFloat(rtt[1], 64)
+		s.Avg, _ = strconv.ParseFloat(rtt[2], 64)
+		s.Max, _ = strconv.ParseFloat(rtt[3], 64)
+	}
+	return s, true
+}
+
+// Lines renders the summary for a FormatHTML-style section in lang.
+func (s PingSummary) Lines(lang string) []string {
+	lines := []string{i18n.T(lang, "ping.summary", s.Transmitted, s.Received, trimFloat(s.LossPercent))}
+	if s.Received > 0 {
+		lines = append(lines, fmt.Sprintf("RTT min/avg/max: %.1f / %.1f / %.1f ms", s.Min, s.Avg, s.Max))
+	}
+	return lines
+}
+
+func trimFloat(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(v, 'f', 1, 64), "0"), ".")
+}
@@ -0,0 +1,48 @@
+package netdiag
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Hop is one line of traceroute output.
+type Hop struct {
+	TTL  int
+	Addr string  // empty when every probe timed out
+	RTT  float64 // milliseconds of the first answered probe
+}
+
+// ParseTraceroute reads `traceroute -n` output, skipping the header line.
+func ParseTraceroute(out string) []Hop {
+	var hops []Hop
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ttl, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		hop := Hop{TTL: ttl}
+		for i := 1; i < len(fields); i++ {
+			f := fields[i]
+			switch {
+			case f == "*":
+			case f == "ms":
+			case hop.Addr == "" && !isNumber(f):
+				hop.Addr = f
+			case hop.RTT == 0 && isNumber(f) && i+1 < len(fields) && fields[i+1] == "ms":
+				hop.RTT, _ = strconv.ParseFloat(f, 64)
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
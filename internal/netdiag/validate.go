@@ -0,0 +1,90 @@
+package netdiag
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTarget is returned for hosts, ports or URLs that are malformed or
+// could be interpreted as command-line options.
+var ErrInvalidTarget = errors.New("invalid target")
+
+// ValidateHost accepts an IP address or an RFC 1123 hostname.
+func ValidateHost(raw string) (string, error) {
+	host := strings.TrimSpace(raw)
+	if host == "" || strings.HasPrefix(host, "-") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, raw)
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		return ip.String(), nil
+	}
+
+	name := strings.TrimSuffix(host, ".")
+	if len(name) > 253 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, raw)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !validLabel(label) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTarget, raw)
+		}
+	}
+	return strings.ToLower(name), nil
+}
+
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateHostPort accepts host:port (with [brackets] for IPv6) and returns it
+// normalized.
+func ValidateHostPort(raw string) (string, error) {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, raw)
+	}
+	host, err = ValidateHost(host)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("%w: port %q", ErrInvalidTarget, port)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(n)), nil
+}
+
+// ValidateURL accepts absolute http and https URLs with a valid host. A bare
+// hostname is treated as https.
+func ValidateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTarget, raw)
+	}
+	if _, err := ValidateHost(u.Hostname()); err != nil {
+		return "", err
+	}
+	if p := u.Port(); p != "" {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("%w: port %q", ErrInvalidTarget, p)
+		}
+	}
+	return u.String(), nil
+}
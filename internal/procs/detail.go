@@ -0,0 +1,152 @@
+package procs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// maxTreeNodes bounds the children tree returned by Describe.
+const maxTreeNodes = 40
+
+// ErrNotFound is returned when a PID does not exist.
+var ErrNotFound = errors.New("process not found")
+
+// defaultProtected lists process names that must never be signalled.
+var defaultProtected = []string{"init", "systemd", "sshd"}
+
+// Detail is the full description of a single process.
+type Detail struct {
+	Info
+
+	Status    string
+	Nice      int32
+	Started   time.Time
+	Threads   int32
+	OpenFiles int32 // -1 when the file descriptor table cannot be read
+
+	// Children is the descendant tree in depth-first order.
+	Children  []TreeNode
+	Truncated bool
+}
+
+// TreeNode is one descendant of a process.
+type TreeNode struct {
+	PID   int32
+	Name  string
+	Depth int
+}
+
+// Describe gathers the details of pid without sampling CPU usage.
+func Describe(ctx context.Context, pid int32) (Detail, error) {
+	exists, err := process.PidExistsWithContext(ctx, pid)
+	if err != nil {
+		return Detail{}, fmt.Errorf("check pid %d: %w", pid, err)
+	}
+	if !exists {
+		return Detail{}, ErrNotFound
+	}
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return Detail{}, ErrNotFound
+	}
+
+	d := Detail{Info: Info{PID: pid}, OpenFiles: -1}
+	fillDetails(ctx, p, &d.Info)
+
+	if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil {
+		d.RSS = memInfo.RSS
+		if vmem, err := mem.VirtualMemoryWithContext(ctx); err == nil && vmem.Total > 0 {
+			d.MemPercent = float64(memInfo.RSS) / float64(vmem.Total) * 100
+		}
+	}
+	if status, err := p.StatusWithContext(ctx); err == nil {
+		d.Status = strings.Join(status, ",")
+	}
+	if nice, err := p.NiceWithContext(ctx); err == nil {
+		d.Nice = nice
+	}
+	if created, err := p.CreateTimeWithContext(ctx); err == nil {
+		d.Started = time.UnixMilli(created)
+	}
+	if threads, err := p.NumThreadsWithContext(ctx); err == nil {
+		d.Threads = threads
+	}
+	if fds, err := p.NumFDsWithContext(ctx); err == nil {
+		d.OpenFiles = fds
+	}
+
+	d.Children, d.Truncated = descendants(ctx, pid)
+	return d, nil
+}
+
+// descendants walks the process table once and returns the children tree of
+// pid, capped at maxTreeNodes.
+func descendants(ctx context.Context, pid int32) ([]TreeNode, bool) {
+	list, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	children := make(map[int32][]TreeNode)
+	for _, p := range list {
+		ppid, err := p.PpidWithContext(ctx)
+		if err != nil || ppid == p.Pid {
+			continue
+		}
+		name, _ := p.NameWithContext(ctx)
+		children[ppid] = append(children[ppid], TreeNode{PID: p.Pid, Name: name})
+	}
+	return BuildTree(pid, children)
+}
+
+// BuildTree flattens the subtree under root depth-first, ordering siblings by
+// PID and setting each node's depth. It reports whether the result was cut at
+// maxTreeNodes.
+func BuildTree(root int32, children map[int32][]TreeNode) ([]TreeNode, bool) {
+	var nodes []TreeNode
+	truncated := false
+	visited := map[int32]bool{root: true}
+
+	var walk func(pid int32, depth int)
+	walk = func(pid int32, depth int) {
+		kids := append([]TreeNode(nil), children[pid]...)
+		sort.Slice(kids, func(i, j int) bool { return kids[i].PID < kids[j].PID })
+		for _, kid := range kids {
+			if visited[kid.PID] {
+				continue
+			}
+			if len(nodes) >= maxTreeNodes {
+				truncated = true
+				return
+			}
+			visited[kid.PID] = true
+			kid.Depth = depth
+			nodes = append(nodes, kid)
+			walk(kid.PID, depth+1)
+		}
+	}
+	walk(root, 0)
+	return nodes, truncated
+}
+
+// Protected reports whether a process must not be signalled or reniced: init,
+// sshd, the bot itself and any name listed in extra.
+func Protected(pid int32, name string, extra []string) bool {
+	if pid <= 1 || int(pid) == os.Getpid() {
+		return true
+	}
+	for _, protected := range append(defaultProtected, extra...) {
+		if strings.EqualFold(name, protected) {
+			return true
+		}
+	}
+	return false
+}
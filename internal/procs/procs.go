@@ -0,0 +1,242 @@
+package procs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// procRoot is where per-process cgroup files are read from.
+var procRoot = "/proc"
+
+// SortKey selects the ranking used by Top.
+type SortKey string
+
+const (
+	SortCPU SortKey = "cpu"
+	SortMem SortKey = "mem"
+	SortIO  SortKey = "io"
+)
+
+// ParseSortKey validates a user supplied ranking name.
+func ParseSortKey(raw string) (SortKey, bool) {
+	switch SortKey(strings.ToLower(raw)) {
+	case SortCPU:
+		return SortCPU, true
+	case SortMem, "ram":
+		return SortMem, true
+	case SortIO:
+		return SortIO, true
+	}
+	return "", false
+}
+
+// Info describes a process sampled over an interval.
+type Info struct {
+	PID     int32
+	PPID    int32
+	Name    string
+	User    string
+	Cmdline string
+
+	// CPUPercent is relative to a single core, as in top(1), so a busy
+	// multi-threaded process can exceed 100.
+	CPUPercent  float64
+	RSS         uint64
+	MemPercent  float64
+	ReadPerSec  uint64
+	WritePerSec uint64
+
+	// ContainerID is the full Docker container ID derived from the cgroup,
+	// empty for host processes.
+	ContainerID string
+}
+
+// IOPerSec is the combined read and write rate.
+func (i Info) IOPerSec() uint64 {
+	return i.ReadPerSec + i.WritePerSec
+}
+
+// TopOptions controls a Top sampling run.
+type TopOptions struct {
+	Interval time.Duration
+	SortBy   SortKey
+	Limit    int
+}
+
+type counters struct {
+	proc  *process.Process
+	cpu   float64
+	read  uint64
+	write uint64
+}
+
+// Top samples every process twice, Interval apart, and returns the Limit
+// heaviest ones according to SortBy with their details filled in.
+func Top(ctx context.Context, opts TopOptions) ([]Info, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.SortBy == "" {
+		opts.SortBy = SortCPU
+	}
+
+	first, err := snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(opts.Interval)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	second, err := snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalMem uint64
+	if vmem, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		totalMem = vmem.Total
+	}
+
+	seconds := opts.Interval.Seconds()
+	infos := make([]Info, 0, len(second))
+	for pid, cur := range second {
+		prev, ok := first[pid]
+		if !ok {
+			continue
+		}
+
+		info := Info{
+			PID:         pid,
+			CPUPercent:  max(0, (cur.cpu-prev.cpu)/seconds*100),
+			ReadPerSec:  uint64(float64(delta(cur.read, prev.read)) / seconds),
+			WritePerSec: uint64(float64(delta(cur.write, prev.write)) / seconds),
+		}
+		if memInfo, err := cur.proc.MemoryInfoWithContext(ctx); err == nil {
+			info.RSS = memInfo.RSS
+			if totalMem > 0 {
+				info.MemPercent = float64(memInfo.RSS) / float64(totalMem) * 100
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	SortInfos(infos, opts.SortBy)
+	if opts.Limit > 0 && len(infos) > opts.Limit {
+		infos = infos[:opts.Limit]
+	}
+
+	for i := range infos {
+		fillDetails(ctx, second[infos[i].PID].proc, &infos[i])
+	}
+	return infos, nil
+}
+
+// SortInfos orders processes from heaviest to lightest by key, using the
+// PID as a stable tiebreaker.
+func SortInfos(infos []Info, key SortKey) {
+	value := func(i Info) float64 {
+		switch key {
+		case SortMem:
+			return float64(i.RSS)
+		case SortIO:
+			return float64(i.IOPerSec())
+		default:
+			return i.CPUPercent
+		}
+	}
+	sort.SliceStable(infos, func(a, b int) bool {
+		va, vb := value(infos[a]), value(infos[b])
+		if va != vb {
+			return va > vb
+		}
+		return infos[a].PID < infos[b].PID
+	})
+}
+
+func snapshot(ctx context.Context) (map[int32]counters, error) {
+	list, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list processes: %w", err)
+	}
+
+	out := make(map[int32]counters, len(list))
+	for _, p := range list {
+		times, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		c := counters{proc: p, cpu: times.User + times.System}
+		// IO counters need the same user or CAP_SYS_PTRACE; keep zeros otherwise.
+		if io, err := p.IOCountersWithContext(ctx); err == nil {
+			c.read, c.write = io.ReadBytes, io.WriteBytes
+		}
+		out[p.Pid] = c
+	}
+	return out, nil
+}
+
+func fillDetails(ctx context.Context, p *process.Process, info *Info) {
+	if p == nil {
+		return
+	}
+	if name, err := p.NameWithContext(ctx); err == nil {
+		info.Name = name
+	}
+	if ppid, err := p.PpidWithContext(ctx); err == nil {
+		info.PPID = ppid
+	}
+	if user, err := p.UsernameWithContext(ctx); err == nil {
+		info.User = user
+	}
+	if cmdline, err := p.CmdlineWithContext(ctx); err == nil {
+		info.Cmdline = cmdline
+	}
+	info.ContainerID = ContainerID(info.PID)
+}
+
+// ContainerID returns the Docker container ID a process belongs to, or an
+// empty string for host processes.
+func ContainerID(pid int32) string {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	return ParseCgroupContainerID(string(data))
+}
+
+var containerIDPattern = regexp.MustCompile(`(?:docker[-/]|cri-containerd-|libpod-)([0-9a-f]{64})`)
+
+// ParseCgroupContainerID extracts the container ID from a /proc/<pid>/cgroup
+// file, covering cgroup v1 (/docker/<id>) and v2 systemd scopes
+// (docker-<id>.scope).
+func ParseCgroupContainerID(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if m := containerIDPattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func delta(cur, prev uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
@@ -0,0 +1,118 @@
+package procs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testContainerID = "3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1b2a3f4e"
+
+func TestParseCgroupContainerID(t *testing.T) {
+	cases := map[string]string{
+		"0::/system.slice/docker-" + testContainerID + ".scope\n":                      testContainerID,
+		"12:memory:/docker/" + testContainerID + "\n11:cpu:/docker/" + testContainerID: testContainerID,
+		"0::/kubepods/besteffort/pod1/cri-containerd-" + testContainerID + ".scope":    testContainerID,
+		"0::/user.slice/user-1000.slice/session-2.scope\n":                             "",
+		"": "",
+	}
+	for content, want := range cases {
+		if got := ParseCgroupContainerID(content); got != want {
+			t.Fatalf("ParseCgroupContainerID(%q) = %q, want %q", content, got, want)
+		}
+	}
+}
+
+func TestContainerIDReadsProcCgroup(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "42"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "42", "cgroup"), []byte("0::/docker/"+testContainerID+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := procRoot
+	procRoot = root
+	defer func() { procRoot = old }()
+
+	if got := ContainerID(42); got != testContainerID {
+		t.Fatalf("ContainerID(42) = %q", got)
+	}
+	if got := ContainerID(7); got != "" {
+		t.Fatalf("ContainerID(7) = %q, want empty", got)
+	}
+}
+
+func TestSortInfos(t *testing.T) {
+	infos := []Info{
+		{PID: 3, CPUPercent: 5, RSS: 300, ReadPerSec: 1},
+		{PID: 1, CPUPercent: 50, RSS: 100, WritePerSec: 10},
+		{PID: 2, CPUPercent: 5, RSS: 200, ReadPerSec: 20},
+	}
+
+	order := func() string {
+		var b strings.Builder
+		for _, i := range infos {
+			b.WriteByte(byte('0' + i.PID))
+		}
+		return b.String()
+	}
+
+	SortInfos(infos, SortCPU)
+	if got := order(); got != "123" {
+		t.Fatalf("cpu order = %s, want 123", got)
+	}
+	SortInfos(infos, SortMem)
+	if got := order(); got != "321" {
+		t.Fatalf("mem order = %s, want 321", got)
+	}
+	SortInfos(infos, SortIO)
+	if got := order(); got != "213" {
+		t.Fatalf("io order = %s, want 213", got)
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	if key, ok := ParseSortKey("RAM"); !ok || key != SortMem {
+		t.Fatalf("ParseSortKey(RAM) = %q, %v", key, ok)
+	}
+	if _, ok := ParseSortKey("disk"); ok {
+		t.Fatalf("ParseSortKey(disk) should fail")
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	children := map[int32][]TreeNode{
+		1:  {{PID: 20, Name: "b"}, {PID: 10, Name: "a"}},
+		10: {{PID: 11, Name: "a1"}},
+		11: {{PID: 10, Name: "loop"}},
+	}
+	nodes, truncated := BuildTree(1, children)
+	if truncated {
+		t.Fatalf("unexpected truncation")
+	}
+	var got []string
+	for _, n := range nodes {
+		got = append(got, strings.Repeat("-", n.Depth)+n.Name)
+	}
+	if strings.Join(got, ",") != "a,-a1,b" {
+		t.Fatalf("tree = %v", got)
+	}
+}
+
+func TestProtected(t *testing.T) {
+	if !Protected(1, "anything", nil) {
+		t.Fatalf("pid 1 must be protected")
+	}
+	if !Protected(int32(os.Getpid()), "serverbot", nil) {
+		t.Fatalf("the bot itself must be protected")
+	}
+	if !Protected(500, "SSHD", nil) || !Protected(501, "postgres", []string{"postgres"}) {
+		t.Fatalf("named processes must be protected")
+	}
+	if Protected(502, "stress", nil) {
+		t.Fatalf("stress should not be protected")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -47,26 +48,27 @@ func NewService(stateFile, repoDir, serveDir, baseURL string, logger *log.Logger
 func (s *Service) HandleBuild(ctx *commands.Context) error {
 	st, err := s.Store.Load()
 	if err != nil {
-		return ctx.ReplyError("Error al leer estado", err)
+		return ctx.ReplyError(ctx.T("revanced.read_failed"), err)
 	}
 	if st.Phase != PhaseIdle {
-		return ctx.Reply(fmt.Sprintf("Pipeline ocupado (fase: %s). Usa /revanced_cancel para reiniciar.", st.Phase))
+		return ctx.Reply(ctx.T("revanced.busy", st.Phase))
 	}
 
 	if err := s.Store.Save(State{
 		Phase:     PhaseResolving,
 		ChatID:    ctx.Update.Message.Chat.ID,
 		StartedAt: time.Now(),
+		Lang:      ctx.Lang,
 	}); err != nil {
-		return ctx.ReplyError("Error al guardar estado", err)
+		return ctx.ReplyError(ctx.T("revanced.save_failed"), err)
 	}
 
-	sent, err := ctx.ReplyMessage("Resolviendo versiones requeridas...")
+	sent, err := ctx.ReplyMessage(ctx.T("revanced.resolving"))
 	if err != nil {
 		return err
 	}
 
-	go s.runResolve(ctx.RequestContext, ctx.Bot, ctx.Update.Message.Chat.ID, sent.MessageID)
+	go s.runResolve(ctx.RequestContext, ctx.Bot, ctx.Update.Message.Chat.ID, sent.MessageID, ctx.Lang)
 	return nil
 }
 
@@ -74,17 +76,24 @@ func (s *Service) HandleBuild(ctx *commands.Context) error {
 func (s *Service) HandleStatus(ctx *commands.Context) error {
 	st, err := s.Store.Load()
 	if err != nil {
-		return ctx.ReplyError("Error al leer estado", err)
+		return ctx.ReplyError(ctx.T("revanced.read_failed"), err)
 	}
 
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("<b>Fase:</b> %s\n", html.EscapeString(string(st.Phase))))
+	b.WriteString(ctx.T("revanced.phase", html.EscapeString(string(st.Phase))) + "\n")
 
 	if !st.StartedAt.IsZero() {
-		b.WriteString(fmt.Sprintf("<b>Inicio:</b> %s\n", st.StartedAt.Format("15:04:05")))
+		b.WriteString(ctx.T("revanced.started", st.StartedAt.Format("15:04:05")) + "\n")
 	}
 	if st.Error != "" {
-		b.WriteString(fmt.Sprintf("<b>Error:</b> %s\n", html.EscapeString(st.Error)))
+		b.WriteString(ctx.T("revanced.error", html.EscapeString(st.Error)) + "\n")
+	}
+	if lb := st.LastBuild; lb != nil {
+		result := "OK"
+		if !lb.OK {
+			result = ctx.T("revanced.failed")
+		}
+		b.WriteString(ctx.T("revanced.last_build", result, lb.FinishedAt.Format("02/01 15:04")) + "\n")
 	}
 
 	for _, apk := range st.RequiredAPKs {
@@ -102,9 +111,9 @@ func (s *Service) HandleStatus(ctx *commands.Context) error {
 func (s *Service) HandleCancel(ctx *commands.Context) error {
 	s.stopTimer()
 	if err := s.Store.Save(State{Phase: PhaseIdle}); err != nil {
-		return ctx.ReplyError("Error al resetear estado", err)
+		return ctx.ReplyError(ctx.T("revanced.reset_failed"), err)
 	}
-	return ctx.Reply("Pipeline reiniciado a idle.")
+	return ctx.Reply(ctx.T("revanced.reset"))
 }
 
 // armTimer (re)starts the idle timer.  When it fires, a build is launched
@@ -138,11 +147,11 @@ func (s *Service) onBuildTimeout(ctx context.Context, bot *tgbotapi.BotAPI, chat
 	received := receivedAppNames(st.RequiredAPKs)
 	if len(received) == 0 {
 		_ = s.Store.Save(State{Phase: PhaseIdle})
-		_ = sendText(bot, chatID, "Timeout, no recibi ningun APK. Pipeline vuelve a idle.")
+		_ = sendText(bot, chatID, i18n.T(st.Lang, "revanced.timeout_empty"))
 		return
 	}
 
-	_ = sendText(bot, chatID, fmt.Sprintf("60s sin APK nuevo. Arranco build con: %s", strings.Join(received, ", ")))
+	_ = sendText(bot, chatID, i18n.T(st.Lang, "revanced.timeout_build", strings.Join(received, ", ")))
 	if err := s.Store.Update(func(state *State) error {
 		state.Phase = PhaseBuilding
 		return nil
@@ -188,7 +197,7 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 		tmp, dlErr := downloadToTemp(tgFile.Link(bot.Token))
 		if dlErr != nil {
 			s.log("download error: %v", dlErr)
-			_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("Error al descargar APK: %s", dlErr))
+			_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.download_failed", dlErr))
 			return true
 		}
 		defer os.Remove(tmp)
@@ -198,7 +207,7 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 	info, err := ReadBundleInfo(localPath)
 	if err != nil {
 		s.log("readAPK error: %v", err)
-		_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("No se pudo leer el APK: %s", err))
+		_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.read_apk_failed", err))
 		return true
 	}
 
@@ -215,7 +224,7 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 
 	if matchedApp == "" {
 		_ = sendText(bot, update.Message.Chat.ID,
-			fmt.Sprintf("APK <code>%s</code> v%s no coincide con ninguno requerido.",
+			i18n.T(st.Lang, "revanced.no_match",
 				html.EscapeString(info.PackageName), html.EscapeString(info.VersionName)))
 		return true
 	}
@@ -226,25 +235,25 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 		// can take 10-30s and we don't want it to fire mid-merge.
 		s.stopTimer()
 		_ = sendText(bot, update.Message.Chat.ID,
-			fmt.Sprintf("APKM <code>%s</code> v%s recibido. Mergeando con APKEditor...",
+			i18n.T(st.Lang, "revanced.merging",
 				html.EscapeString(info.PackageName), html.EscapeString(info.VersionName)))
 
 		apkmDst := filepath.Join(s.RepoDir, "apks", matchedApp+".apkm")
 		if cpErr := copyFile(localPath, apkmDst); cpErr != nil {
 			s.log("copy apkm: %v", cpErr)
-			_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("Error copiando APKM: %s", cpErr))
+			_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.copy_apkm_failed", cpErr))
 			return true
 		}
 		if mErr := MergeAPKM(ctx, s.RepoDir, matchedApp+".apkm", matchedApp+".apk"); mErr != nil {
 			s.log("merge apkm: %v", mErr)
 			_ = os.Remove(apkmDst)
-			_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("Error mergeando APKM: %s", mErr))
+			_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.merge_failed", mErr))
 			return true
 		}
 		_ = os.Remove(apkmDst)
 	} else if cpErr := copyFile(localPath, apkDst); cpErr != nil {
 		s.log("copy apk: %v", cpErr)
-		_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("Error copiando APK: %s", cpErr))
+		_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.copy_apk_failed", cpErr))
 		return true
 	}
 
@@ -264,20 +273,20 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 		return nil
 	}); err != nil {
 		s.log("update state error: %v", err)
-		_ = sendText(bot, update.Message.Chat.ID, fmt.Sprintf("Error interno: %s", err))
+		_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.internal", err))
 		return true
 	}
 
 	if !matched {
 		_ = sendText(bot, update.Message.Chat.ID,
-			fmt.Sprintf("APK <code>%s</code> v%s no coincide con ninguno requerido.",
+			i18n.T(st.Lang, "revanced.no_match",
 				html.EscapeString(info.PackageName), html.EscapeString(info.VersionName)))
 		return true
 	}
 
 	if allReceived {
 		s.stopTimer()
-		_ = sendText(bot, update.Message.Chat.ID, "Todos los APKs recibidos. Iniciando build...")
+		_ = sendText(bot, update.Message.Chat.ID, i18n.T(st.Lang, "revanced.all_received"))
 		if err := s.Store.Update(func(state *State) error {
 			state.Phase = PhaseBuilding
 			return nil
@@ -288,7 +297,7 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 	} else {
 		s.armTimer(ctx, bot, update.Message.Chat.ID)
 		_ = sendText(bot, update.Message.Chat.ID,
-			fmt.Sprintf("APK <code>%s</code> v%s recibido. (60s para build parcial)",
+			i18n.T(st.Lang, "revanced.received",
 				html.EscapeString(info.PackageName), html.EscapeString(info.VersionName)))
 	}
 
@@ -297,12 +306,12 @@ func (s *Service) HandleDocument(ctx context.Context, bot *tgbotapi.BotAPI, upda
 
 // runResolve executes the resolver in the background and transitions to
 // awaiting_apks or reports the error.
-func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, editMsgID int) {
+func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, editMsgID int, lang string) {
 	versions, err := Resolve(ctx, s.RepoDir)
 	if err != nil {
 		s.log("resolve error: %v", err)
 		_ = s.Store.Save(State{Phase: PhaseIdle, Error: err.Error()})
-		_ = editText(bot, chatID, editMsgID, fmt.Sprintf("Error en resolución:\n<pre>%s</pre>", html.EscapeString(err.Error())))
+		_ = editText(bot, chatID, editMsgID, i18n.T(lang, "revanced.resolve_failed", html.EscapeString(err.Error())))
 		return
 	}
 
@@ -320,7 +329,7 @@ func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID i
 		}
 		if names := s.alreadyPublished(resolvedAPKs); len(names) > 0 {
 			_ = s.Store.Save(State{Phase: PhaseIdle})
-			_ = editText(bot, chatID, editMsgID, s.formatPublished("Ya publicado (misma version)", names))
+			_ = editText(bot, chatID, editMsgID, s.formatPublished(i18n.T(lang, "revanced.already_published"), names))
 			return
 		}
 	}
@@ -366,8 +375,9 @@ func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID i
 			RequiredAPKs: required,
 			ChatID:       chatID,
 			StartedAt:    time.Now(),
+			Lang:         lang,
 		})
-		_ = editText(bot, chatID, editMsgID, "Todos los APKs disponibles. Iniciando build...")
+		_ = editText(bot, chatID, editMsgID, i18n.T(lang, "revanced.all_present"))
 		go s.runBuild(ctx, bot, chatID)
 		return
 	}
@@ -377,10 +387,11 @@ func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID i
 		RequiredAPKs: required,
 		ChatID:       chatID,
 		StartedAt:    time.Now(),
+		Lang:         lang,
 	})
 
 	var b strings.Builder
-	b.WriteString("<b>APKs requeridos:</b>\n")
+	b.WriteString(i18n.T(lang, "revanced.required") + "\n")
 	for _, r := range required {
 		status := "⏳"
 		if r.Received {
@@ -389,9 +400,9 @@ func (s *Service) runResolve(ctx context.Context, bot *tgbotapi.BotAPI, chatID i
 		b.WriteString(fmt.Sprintf("%s <code>%s</code> v%s\n", status, html.EscapeString(r.PackageName), html.EscapeString(r.Version)))
 	}
 	if len(present) > 0 {
-		b.WriteString(fmt.Sprintf("\nYa disponibles: %s\n", strings.Join(present, ", ")))
+		b.WriteString("\n" + i18n.T(lang, "revanced.present", strings.Join(present, ", ")) + "\n")
 	}
-	b.WriteString("\nEnvia los APKs faltantes como documentos. (60s para build parcial)")
+	b.WriteString("\n" + i18n.T(lang, "revanced.send_missing"))
 	_ = editText(bot, chatID, editMsgID, b.String())
 
 	s.armTimer(ctx, bot, chatID)
@@ -407,10 +418,11 @@ func (s *Service) runBuild(ctx context.Context, bot *tgbotapi.BotAPI, chatID int
 	if loadErr == nil {
 		appNames = receivedAppNames(st.RequiredAPKs)
 	}
+	t := func(key string, args ...any) string { return i18n.T(st.Lang, key, args...) }
 	apps := strings.Join(appNames, ",")
 	total := len(appNames)
 
-	sent, sendErr := sendTextMsg(bot, chatID, "Iniciando build...")
+	sent, sendErr := sendTextMsg(bot, chatID, t("revanced.building"))
 	if sendErr != nil {
 		s.log("send error: %v", sendErr)
 		return
@@ -443,48 +455,61 @@ func (s *Service) runBuild(ctx context.Context, bot *tgbotapi.BotAPI, chatID int
 		switch {
 		case strings.HasPrefix(line, "Trying to build "):
 			app := strings.TrimPrefix(line, "Trying to build ")
-			setStatus(fmt.Sprintf("Parcheando <b>%s</b> (%d/%d)...",
+			setStatus(t("revanced.patching",
 				html.EscapeString(app), done+1, total))
 		case strings.Contains(line, "INFO: Compiling modified resources"):
-			setStatus(fmt.Sprintf("Compilando recursos (%d/%d)...", done+1, total))
+			setStatus(t("revanced.compiling", done+1, total))
 		case strings.Contains(line, "INFO: Writing resource APK"):
-			setStatus(fmt.Sprintf("Escribiendo APK (%d/%d)...", done+1, total))
+			setStatus(t("revanced.writing", done+1, total))
 		case strings.Contains(line, "INFO: Aligning APK"):
-			setStatus(fmt.Sprintf("Alineando APK (%d/%d)...", done+1, total))
+			setStatus(t("revanced.aligning", done+1, total))
 		case strings.Contains(line, "INFO: Signing APK"):
-			setStatus(fmt.Sprintf("Firmando (%d/%d)...", done+1, total))
+			setStatus(t("revanced.signing", done+1, total))
 		case strings.Contains(line, "Successfully completed"):
 			statusMu.Lock()
 			done++
 			statusMu.Unlock()
-			setStatus(fmt.Sprintf("%d/%d apps parcheadas...", done, total))
+			setStatus(t("revanced.patched", done, total))
 		case strings.Contains(line, "FAILED"):
-			setStatus(fmt.Sprintf("Fallo detectado (%d/%d)...", done, total))
+			setStatus(t("revanced.failure", done, total))
 		}
 	}
 
 	err := Build(ctx, s.RepoDir, apps, onLine)
 	if err != nil {
 		s.log("build error: %v", err)
-		_ = s.Store.Save(State{Phase: PhaseIdle, Error: err.Error()})
+		_ = s.Store.Save(State{Phase: PhaseIdle, Error: err.Error(), LastBuild: buildResult(err, nil)})
 		errMsg := err.Error()
 		if len(errMsg) > 3000 {
 			errMsg = errMsg[:3000] + "\n..."
 		}
-		_ = editText(bot, chatID, msgID, fmt.Sprintf("Build fallido:\n<pre>%s</pre>", html.EscapeString(errMsg)))
+		_ = editText(bot, chatID, msgID, t("revanced.build_failed", html.EscapeString(errMsg)))
 		return
 	}
 
 	published, err := Publish(s.RepoDir, s.ServeDir)
 	if err != nil {
 		s.log("publish error: %v", err)
-		_ = s.Store.Save(State{Phase: PhaseIdle, Error: err.Error()})
-		_ = editText(bot, chatID, msgID, fmt.Sprintf("Build exitoso pero error al publicar: %s", err))
+		_ = s.Store.Save(State{Phase: PhaseIdle, Error: err.Error(), LastBuild: buildResult(err, published)})
+		_ = editText(bot, chatID, msgID, t("revanced.publish_failed", err))
 		return
 	}
 
-	_ = s.Store.Save(State{Phase: PhaseIdle})
-	_ = editText(bot, chatID, msgID, s.formatPublished("Build ReVanced OK", published))
+	_ = s.Store.Save(State{Phase: PhaseIdle, LastBuild: buildResult(nil, published)})
+	_ = editText(bot, chatID, msgID, s.formatPublished(t("revanced.build_ok"), published))
+}
+
+// buildResult captures the outcome of a finished build for later reports.
+func buildResult(err error, published []string) *BuildResult {
+	res := &BuildResult{
+		FinishedAt: time.Now(),
+		OK:         err == nil,
+		Published:  published,
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
 }
 
 // alreadyPublished checks if the serve dir already contains patched APKs
@@ -1,14 +1,14 @@
 package revanced
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"time"
+
+	"serverbot/internal/system"
 )
 
 const (
@@ -17,6 +17,9 @@ const (
 	mergeTimeout   = 5 * time.Minute
 )
 
+// runner executes the docker compose commands; tests replace it.
+var runner system.StreamRunner = system.NewCommandRunner()
+
 // VersionEntry mirrors one element of the resolver's versions.json output.
 type VersionEntry struct {
 	AppName     string `json:"app_name"`
@@ -30,15 +33,13 @@ func Resolve(ctx context.Context, repoDir string) ([]VersionEntry, error) {
 	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "docker", "compose",
+	res, err := runner.Stream(ctx, system.StreamOptions{Dir: repoDir}, "docker", "compose",
 		"-f", filepath.Join(repoDir, "docker-compose-local.yml"),
 		"--profile", "build", "run", "--rm", "revanced",
 		"python", "main.py", "--resolve-only",
 	)
-	cmd.Dir = repoDir
-	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, fmt.Errorf("resolve: %w\n%s", err, string(out))
+		return nil, fmt.Errorf("resolve: %w\n%s%s", err, res.Stdout, res.Stderr)
 	}
 
 	versionsPath := filepath.Join(repoDir, "apks", "versions.json")
@@ -82,28 +83,12 @@ func Build(ctx context.Context, repoDir string, apps string, onLine func(string)
 	}
 	args = append(args, "revanced")
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = repoDir
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("stdout pipe: %w", err)
+	// The log only goes to onLine, so none of it is kept in memory.
+	opts := system.StreamOptions{Dir: repoDir, MaxOutput: -1}
+	if onLine != nil {
+		opts.OnLine = func(line system.Line) { onLine(line.Text) }
 	}
-	cmd.Stderr = cmd.Stdout // merge stderr into stdout
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("start build: %w", err)
-	}
-
-	scanner := bufio.NewScanner(stdout)
-	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
-	for scanner.Scan() {
-		if onLine != nil {
-			onLine(scanner.Text())
-		}
-	}
-
-	if err := cmd.Wait(); err != nil {
+	if _, err := runner.Stream(ctx, opts, "docker", args...); err != nil {
 		return fmt.Errorf("build: %w", err)
 	}
 	return nil
@@ -123,17 +108,15 @@ func MergeAPKM(ctx context.Context, repoDir, apkmName, apkName string) error {
 		"java -jar apks/apkeditor-output.jar m -i apks/%s -o apks/%s -f",
 		apkmName, apkName,
 	)
-	cmd := exec.CommandContext(ctx, "docker", "compose",
+	res, err := runner.Stream(ctx, system.StreamOptions{Dir: repoDir}, "docker", "compose",
 		"-f", filepath.Join(repoDir, "docker-compose-local.yml"),
 		"--profile", "build", "run", "--rm",
 		"--entrypoint", "bash",
 		"revanced",
 		"-c", script,
 	)
-	cmd.Dir = repoDir
-	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("merge apkm: %w\n%s", err, string(out))
+		return fmt.Errorf("merge apkm: %w\n%s%s", err, res.Stdout, res.Stderr)
 	}
 	return nil
 }
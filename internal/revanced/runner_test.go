@@ -0,0 +1,99 @@
+package revanced
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"serverbot/internal/system"
+)
+
+type fakeStreamRunner struct {
+	opts  system.StreamOptions
+	args  []string
+	lines []string
+	res   system.Result
+	err   error
+	// run is called before returning, to leave files behind like the container.
+	run func()
+}
+
+func (f *fakeStreamRunner) Stream(ctx context.Context, opts system.StreamOptions, name string, args ...string) (system.Result, error) {
+	f.opts, f.args = opts, append([]string{name}, args...)
+	for _, line := range f.lines {
+		if opts.OnLine != nil {
+			opts.OnLine(system.Line{Text: line})
+		}
+	}
+	if f.run != nil {
+		f.run()
+	}
+	return f.res, f.err
+}
+
+func useRunner(t *testing.T, f *fakeStreamRunner) {
+	t.Helper()
+	old := runner
+	runner = f
+	t.Cleanup(func() { runner = old })
+}
+
+func TestResolveReadsVersions(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "apks"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeStreamRunner{run: func() {
+		_ = os.WriteFile(filepath.Join(repo, "apks", "versions.json"),
+			[]byte(`[{"app_name":"youtube","package_name":"com.google.android.youtube","suggested_version":"19.16.39"}]`), 0o644)
+	}}
+	useRunner(t, fake)
+
+	versions, err := Resolve(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(versions) != 1 || versions[0].Version != "19.16.39" {
+		t.Fatalf("versions = %+v", versions)
+	}
+	if fake.opts.Dir != repo || !slices.Contains(fake.args, "--resolve-only") {
+		t.Fatalf("ran %v in %q", fake.args, fake.opts.Dir)
+	}
+
+	fake.res = system.Result{Stderr: "no such service"}
+	fake.err = errors.New("exit status 1")
+	if _, err := Resolve(context.Background(), repo); err == nil || !strings.Contains(err.Error(), "no such service") {
+		t.Fatalf("Resolve() error = %v, want the command output", err)
+	}
+}
+
+func TestBuildStreamsLines(t *testing.T) {
+	repo := t.TempDir()
+	stale := filepath.Join(repo, "apks", "ReVanced-output.apk")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeStreamRunner{lines: []string{"Patching youtube", "Done"}}
+	useRunner(t, fake)
+
+	var got []string
+	if err := Build(context.Background(), repo, "youtube", func(line string) { got = append(got, line) }); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if strings.Join(got, "|") != "Patching youtube|Done" {
+		t.Fatalf("lines = %v", got)
+	}
+	if !slices.Contains(fake.args, "PATCH_APPS=youtube") || fake.opts.MaxOutput >= 0 {
+		t.Fatalf("ran %v with %+v", fake.args, fake.opts)
+	}
+	if _, err := os.Stat(stale); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("previous output not removed: %v", err)
+	}
+}
@@ -36,6 +36,18 @@ type State struct {
 	ChatID       int64         `json:"chat_id,omitempty"`
 	StartedAt    time.Time     `json:"started_at,omitempty"`
 	Error        string        `json:"error,omitempty"`
+	LastBuild    *BuildResult  `json:"last_build,omitempty"`
+	// Lang is the language of the chat that started the pipeline, used by
+	// the messages sent after the command returns.
+	Lang string `json:"lang,omitempty"`
+}
+
+// BuildResult summarises the outcome of the most recent finished build.
+type BuildResult struct {
+	FinishedAt time.Time `json:"finished_at"`
+	OK         bool      `json:"ok"`
+	Error      string    `json:"error,omitempty"`
+	Published  []string  `json:"published,omitempty"`
 }
 
 // StateStore provides atomic read/write of pipeline state backed by a JSON
@@ -65,7 +77,8 @@ func (s *StateStore) Load() (State, error) {
 	return s.readUnsafe()
 }
 
-// Save atomically writes the state to disk.
+// Save atomically writes the state to disk.  The last build summary is
+// carried over from the stored state unless st sets a new one.
 func (s *StateStore) Save(st State) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -76,6 +89,11 @@ func (s *StateStore) Save(st State) error {
 	}
 	defer fl.Unlock()
 
+	if st.LastBuild == nil {
+		if cur, err := s.readUnsafe(); err == nil {
+			st.LastBuild = cur.LastBuild
+		}
+	}
 	return s.writeUnsafe(st)
 }
 
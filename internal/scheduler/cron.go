@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/i18n"
+)
+
+// Schedule is a parsed five-field cron expression (minute hour dom month dow).
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domAny/dowAny record a "*" in the day fields, which changes how the
+	// two are combined (classic cron matches either one when both are set).
+	domAny bool
+	dowAny bool
+}
+
+// ParseError describes an invalid cron expression as a catalog message, so
+// it can be shown in the language of the chat.
+type ParseError struct {
+	Key   string // cron.* message
+	Field string // cron.field.* name the message starts with, if any
+	Args  []any
+}
+
+// Error returns the message in English, the language of logs and errors.
+func (e *ParseError) Error() string {
+	return e.Message("en")
+}
+
+// Message returns the error in lang.
+func (e *ParseError) Message(lang string) string {
+	if e.Field == "" {
+		return i18n.T(lang, e.Key, e.Args...)
+	}
+	args := append([]any{i18n.T(lang, "cron.field."+e.Field)}, e.Args...)
+	return i18n.T(lang, e.Key, args...)
+}
+
+type cronField struct {
+	name     string // suffix of the cron.field.* message
+	min, max int
+	names    map[string]int
+}
+
+func (f cronField) errorf(key string, args ...any) error {
+	return &ParseError{Key: key, Field: f.name, Args: args}
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "weekday", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// Parse parses a standard five-field cron expression. Lists, ranges, steps,
+// month/weekday names and the usual @daily style macros are supported.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(strings.ToLower(expr))
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, &ParseError{Key: "cron.fields", Args: []any{len(fields)}}
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return Schedule{}, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return Schedule{}, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return Schedule{}, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return Schedule{}, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return Schedule{}, err
+	}
+
+	// Sunday can be written as 0 or 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	// Like Vixie cron, a day field starting with "*" (such as "*/2") counts
+	// as unrestricted.
+	s.domAny = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	s.dowAny = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return s, nil
+}
+
+func parseField(raw string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(raw, ",") {
+		if part == "" {
+			return 0, f.errorf("cron.empty")
+		}
+
+		step := 1
+		if idx := strings.IndexByte(part, '/'); idx >= 0 {
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, f.errorf("cron.bad_step", part)
+			}
+			step = n
+			part = part[:idx]
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, f.errorf("cron.bad_range", part)
+			}
+		default:
+			v, err := f.value(part)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(raw string) (int, error) {
+	if v, ok := f.names[raw]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, f.errorf("cron.bad_value", raw)
+	}
+	if v < f.min || v > f.max {
+		return 0, f.errorf("cron.out_of_range", f.min, f.max, v)
+	}
+	return v, nil
+}
+
+// Next returns the first activation strictly after t, or the zero time when
+// the expression never matches (e.g. 30 February) within the next five years.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) expected error", expr)
+		}
+	}
+}
+
+func TestParseErrorIsLocalized(t *testing.T) {
+	_, err := Parse("* * * * 9")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Parse() error = %v, want a *ParseError", err)
+	}
+	if got := perr.Message("es"); got != "dia de la semana fuera de rango (0-7): 9" {
+		t.Errorf("Message(es) = %q", got)
+	}
+	if got := err.Error(); got != "day of week out of range (0-7): 9" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	base := time.Date(2025, time.March, 14, 10, 30, 15, 0, time.UTC) // Friday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.March, 14, 10, 31, 0, 0, time.UTC)},
+		{"0 8 * * *", time.Date(2025, time.March, 15, 8, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC)},
+		{"30 6 1 * *", time.Date(2025, time.April, 1, 6, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches.
+		{"0 12 20 * fri", time.Date(2025, time.March, 14, 12, 0, 0, 0, time.UTC)},
+		// A stepped "*" is still unrestricted: odd days that are Mondays.
+		{"0 0 */2 * 1", time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			sched, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := sched.Next(base); !got.Equal(tt.want) {
+				t.Fatalf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextImpossibleDate(t *testing.T) {
+	sched, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := sched.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestCountMissed(t *testing.T) {
+	sched, err := Parse("0 * * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	from := time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)
+	now := time.Date(2025, time.March, 14, 13, 5, 0, 0, time.UTC)
+	count, last := countMissed(sched, from, now)
+	if count != 3 {
+		t.Fatalf("count = %d, want 3", count)
+	}
+	if want := time.Date(2025, time.March, 14, 13, 0, 0, 0, time.UTC); !last.Equal(want) {
+		t.Fatalf("last = %v, want %v", last, want)
+	}
+
+	if count, _ := countMissed(sched, now, now); count != 0 {
+		t.Fatalf("count without downtime = %d, want 0", count)
+	}
+}
@@ -0,0 +1,399 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// maxIdleWait bounds how long the loop sleeps so clock jumps and
+	// external edits of the state file are picked up reasonably fast.
+	maxIdleWait = time.Minute
+	// maxMissedCount caps the catch-up scan for very frequent jobs after a
+	// long downtime.
+	maxMissedCount = 1000
+	timeLayout     = "2006-01-02 15:04"
+)
+
+// Service runs persisted jobs through the command registry.
+type Service struct {
+	Store    *Store
+	Registry *commands.Registry
+	Logger   *log.Logger
+	// Languages picks the language of missed-run notices; nil uses the
+	// default language.
+	Languages *i18n.Store
+
+	wake chan struct{}
+}
+
+// NewService creates a ready-to-use Service persisting jobs at stateFile.
+func NewService(stateFile string, registry *commands.Registry, logger *log.Logger) *Service {
+	return &Service{
+		Store:    NewStore(stateFile),
+		Registry: registry,
+		Logger:   logger,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Run reports runs missed while the bot was down and then fires jobs as
+// they become due until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, bot *tgbotapi.BotAPI) {
+	s.reportMissed(bot, time.Now())
+
+	for {
+		timer := time.NewTimer(s.untilNext(time.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+		s.runDue(ctx, bot, time.Now())
+	}
+}
+
+// HandleSchedule is the handler for /schedule add|list|rm.
+func (s *Service) HandleSchedule(ctx *commands.Context) error {
+	sub, rest := cutFields(ctx.Args(), 1)
+	if len(sub) == 0 {
+		return ctx.Reply(ctx.T("schedule.usage"))
+	}
+
+	switch strings.ToLower(sub[0]) {
+	case "add":
+		return s.handleAdd(ctx, rest)
+	case "list", "ls":
+		return s.handleList(ctx)
+	case "rm", "del":
+		return s.handleRemove(ctx, rest)
+	default:
+		return ctx.Reply(ctx.T("schedule.usage"))
+	}
+}
+
+func (s *Service) handleAdd(ctx *commands.Context, raw string) error {
+	chatID := ctx.Update.Message.Chat.ID
+	if head, rest := cutFields(raw, 2); len(head) == 2 && head[0] == "--chat" {
+		id, err := strconv.ParseInt(head[1], 10, 64)
+		if err != nil {
+			return ctx.Reply(ctx.T("schedule.bad_chat"))
+		}
+		chatID, raw = id, rest
+	}
+
+	spec, rest, err := cutSpec(raw)
+	if err != nil {
+		return ctx.Reply(ctx.T("schedule.bad_cron", cronError(ctx.Lang, err)))
+	}
+	sched, err := Parse(spec)
+	if err != nil {
+		return ctx.Reply(ctx.T("schedule.bad_cron", cronError(ctx.Lang, err)))
+	}
+
+	cmdField, args := cutFields(rest, 1)
+	if len(cmdField) == 0 || !strings.HasPrefix(cmdField[0], "/") {
+		return ctx.Reply(ctx.T("schedule.usage"))
+	}
+	name := strings.ToLower(strings.TrimPrefix(cmdField[0], "/"))
+	if at := strings.IndexByte(name, '@'); at >= 0 {
+		name = name[:at]
+	}
+	if name == ctx.Command || !s.Registry.Has(name) {
+		return ctx.Reply(ctx.T("schedule.not_allowed", name))
+	}
+
+	job := Job{
+		Spec:      spec,
+		Command:   name,
+		Args:      strings.TrimSpace(args),
+		ChatID:    chatID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.Store.Update(func(st *State) error {
+		job.ID = st.NextID
+		st.NextID++
+		st.Jobs = append(st.Jobs, job)
+		return nil
+	}); err != nil {
+		return ctx.ReplyError(ctx.T("schedule.save_failed"), err)
+	}
+	s.notify()
+
+	next := sched.Next(job.CreatedAt)
+	return ctx.ReplyHTML(ctx.T("schedule.created",
+		job.ID, html.EscapeString(formatTime(ctx.Lang, next))), false)
+}
+
+func (s *Service) handleList(ctx *commands.Context) error {
+	st, err := s.Store.Load()
+	if err != nil {
+		return ctx.ReplyError(ctx.T("schedule.read_failed"), err)
+	}
+	if len(st.Jobs) == 0 {
+		return ctx.Reply(ctx.T("schedule.empty"))
+	}
+
+	var b strings.Builder
+	b.WriteString(ctx.T("schedule.title") + "\n\n")
+	for _, job := range st.Jobs {
+		next := "-"
+		if sched, err := Parse(job.Spec); err == nil {
+			next = formatTime(ctx.Lang, sched.Next(time.Now()))
+		}
+		command := "/" + job.Command
+		if job.Args != "" {
+			command += " " + job.Args
+		}
+		fmt.Fprintf(&b, "<b>#%d</b> <code>%s</code> %s\n", job.ID, html.EscapeString(job.Spec), html.EscapeString(command))
+		b.WriteString(ctx.T("schedule.next", job.ChatID, html.EscapeString(next)) + "\n")
+	}
+	return ctx.ReplyHTML(b.String(), false)
+}
+
+func (s *Service) handleRemove(ctx *commands.Context, raw string) error {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return ctx.Reply(ctx.T("schedule.rm_usage"))
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(fields[0], "#"))
+	if err != nil {
+		return ctx.Reply(ctx.T("schedule.bad_id"))
+	}
+
+	removed := false
+	if err := s.Store.Update(func(st *State) error {
+		for i, job := range st.Jobs {
+			if job.ID == id {
+				st.Jobs = append(st.Jobs[:i], st.Jobs[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		return nil
+	}); err != nil {
+		return ctx.ReplyError(ctx.T("schedule.remove_failed"), err)
+	}
+	if !removed {
+		return ctx.Reply(ctx.T("schedule.not_found", id))
+	}
+	s.notify()
+	return ctx.Reply(ctx.T("schedule.removed", id))
+}
+
+// runDue dispatches every job whose next activation is not after now.
+func (s *Service) runDue(ctx context.Context, bot *tgbotapi.BotAPI, now time.Time) {
+	var due []Job
+	if err := s.Store.Update(func(st *State) error {
+		for i := range st.Jobs {
+			job := &st.Jobs[i]
+			sched, err := Parse(job.Spec)
+			if err != nil {
+				continue
+			}
+			next := sched.Next(job.reference())
+			if next.IsZero() || next.After(now) {
+				continue
+			}
+			job.LastRun = now
+			due = append(due, *job)
+		}
+		return nil
+	}); err != nil {
+		s.log("update schedule error: %v", err)
+		return
+	}
+
+	for _, job := range due {
+		go s.dispatch(ctx, bot, job)
+	}
+}
+
+func (s *Service) dispatch(ctx context.Context, bot *tgbotapi.BotAPI, job Job) {
+	update := commands.SyntheticUpdate(job.ChatID, job.Command, job.Args)
+	if err := s.Registry.Dispatch(ctx, bot, update); err != nil {
+		s.log("job #%d (/%s) failed: %v", job.ID, job.Command, err)
+	}
+}
+
+// reportMissed tells each job's chat how many activations were skipped while
+// the bot was not running, then moves the job forward so they are not
+// replayed in bulk.
+func (s *Service) reportMissed(bot *tgbotapi.BotAPI, now time.Time) {
+	type missed struct {
+		job   Job
+		count int
+		last  time.Time
+	}
+
+	var report []missed
+	if err := s.Store.Update(func(st *State) error {
+		for i := range st.Jobs {
+			job := &st.Jobs[i]
+			sched, err := Parse(job.Spec)
+			if err != nil {
+				continue
+			}
+			count, last := countMissed(sched, job.reference(), now)
+			if count == 0 {
+				continue
+			}
+			job.LastRun = now
+			report = append(report, missed{job: *job, count: count, last: last})
+		}
+		return nil
+	}); err != nil {
+		s.log("update schedule error: %v", err)
+		return
+	}
+
+	for _, m := range report {
+		count := strconv.Itoa(m.count)
+		if m.count >= maxMissedCount {
+			count += "+"
+		}
+		lang := s.Languages.Resolve(m.job.ChatID, "")
+		text := i18n.N(lang, "schedule.missed", m.count,
+			m.job.ID, m.job.Command, count, formatTime(lang, m.last))
+		if err := sendText(bot, m.job.ChatID, text); err != nil {
+			s.log("missed-run notice error: %v", err)
+		}
+	}
+}
+
+// countMissed counts activations in (from, now].
+func countMissed(sched Schedule, from, now time.Time) (int, time.Time) {
+	var (
+		count int
+		last  time.Time
+	)
+	for t := sched.Next(from); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		count++
+		last = t
+		if count >= maxMissedCount {
+			break
+		}
+	}
+	return count, last
+}
+
+// untilNext returns how long to sleep before the next job is due.
+func (s *Service) untilNext(now time.Time) time.Duration {
+	wait := maxIdleWait
+	st, err := s.Store.Load()
+	if err != nil {
+		s.log("load schedule error: %v", err)
+		return wait
+	}
+	for _, job := range st.Jobs {
+		sched, err := Parse(job.Spec)
+		if err != nil {
+			continue
+		}
+		next := sched.Next(job.reference())
+		if next.IsZero() {
+			continue
+		}
+		if d := next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("scheduler: "+format, args...)
+	}
+}
+
+// reference is the instant from which the next activation is computed.
+func (j Job) reference() time.Time {
+	if !j.LastRun.IsZero() {
+		return j.LastRun
+	}
+	return j.CreatedAt
+}
+
+// cutSpec splits a leading cron expression (five fields or an @macro) from
+// the rest of the input.
+func cutSpec(raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "@") {
+		fields, rest := cutFields(raw, 1)
+		return fields[0], rest, nil
+	}
+	fields, rest := cutFields(raw, 5)
+	if len(fields) < 5 {
+		return "", "", &ParseError{Key: "cron.fields", Args: []any{len(fields)}}
+	}
+	return strings.Join(fields, " "), rest, nil
+}
+
+// cutFields pops up to n whitespace-separated fields and returns the
+// remainder verbatim, so quoting in the command arguments is preserved.
+func cutFields(raw string, n int) ([]string, string) {
+	var fields []string
+	rest := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	for len(fields) < n && rest != "" {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			fields = append(fields, rest)
+			rest = ""
+			break
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	return fields, rest
+}
+
+func formatTime(lang string, t time.Time) string {
+	if t.IsZero() {
+		return i18n.T(lang, "schedule.never")
+	}
+	return t.Format(timeLayout)
+}
+
+// cronError renders an invalid expression error in lang.
+func cronError(lang string, err error) string {
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		return perr.Message(lang)
+	}
+	return err.Error()
+}
+
+func sendText(bot *tgbotapi.BotAPI, chatID int64, text string) error {
+	if bot == nil || chatID == 0 || text == "" {
+		return nil
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err := bot.Send(msg)
+	return err
+}
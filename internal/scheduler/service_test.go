@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/testutil"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func newTestService(t *testing.T) (*Service, *commands.Registry) {
+	t.Helper()
+	reg := commands.NewRegistry(commands.Dependencies{Config: app.Config{OwnerID: 1}})
+	svc := NewService(filepath.Join(t.TempDir(), "schedule.json"), reg, nil)
+	reg.Handle("schedule", "desc", commands.ScopeOwner, svc.HandleSchedule, commands.OwnerOnly())
+	return svc, reg
+}
+
+func scheduleContext(bot *tgbotapi.BotAPI, args string) *commands.Context {
+	return &commands.Context{
+		AppConfig:      app.Config{OwnerID: 1},
+		RequestContext: context.Background(),
+		Bot:            bot,
+		Update: tgbotapi.Update{
+			Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1}},
+		},
+		Command:   "schedule",
+		Arguments: args,
+	}
+}
+
+func TestHandleScheduleAddListRemove(t *testing.T) {
+	svc, reg := newTestService(t)
+	reg.Handle("docker_exec", "desc", commands.ScopeAdmin, func(ctx *commands.Context) error { return nil })
+
+	bot, client := testutil.NewFakeBot()
+	if err := svc.HandleSchedule(scheduleContext(bot, `add --chat 42 */5 * * * * /docker_exec mc-server say "hola mundo"`)); err != nil {
+		t.Fatalf("add returned error: %v", err)
+	}
+
+	st, err := svc.Store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(st.Jobs) != 1 {
+		t.Fatalf("jobs = %v, want one job", st.Jobs)
+	}
+	job := st.Jobs[0]
+	if job.ID != 1 || job.Spec != "*/5 * * * *" || job.Command != "docker_exec" || job.ChatID != 42 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if job.Args != `mc-server say "hola mundo"` {
+		t.Fatalf("Args = %q, want quoting preserved", job.Args)
+	}
+
+	if err := svc.HandleSchedule(scheduleContext(bot, "list")); err != nil {
+		t.Fatalf("list returned error: %v", err)
+	}
+	if err := svc.HandleSchedule(scheduleContext(bot, "rm 1")); err != nil {
+		t.Fatalf("rm returned error: %v", err)
+	}
+
+	reqs := client.Requests()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 replies, got %d", len(reqs))
+	}
+	if got := reqs[1].Values.Get("text"); !strings.Contains(got, "/docker_exec mc-server") || !strings.Contains(got, "Chat 42") {
+		t.Fatalf("list reply = %q", got)
+	}
+	if got := reqs[2].Values.Get("text"); got != "Tarea #1 eliminada." {
+		t.Fatalf("rm reply = %q", got)
+	}
+
+	st, _ = svc.Store.Load()
+	if len(st.Jobs) != 0 {
+		t.Fatalf("jobs after rm = %v, want none", st.Jobs)
+	}
+}
+
+func TestHandleScheduleRejectsUnknownCommand(t *testing.T) {
+	svc, _ := newTestService(t)
+	bot, client := testutil.NewFakeBot()
+
+	for _, args := range []string{"add 0 8 * * * /missing", "add 0 8 * * * /schedule list"} {
+		if err := svc.HandleSchedule(scheduleContext(bot, args)); err != nil {
+			t.Fatalf("add returned error: %v", err)
+		}
+	}
+
+	for i, req := range client.Requests() {
+		if got := req.Values.Get("text"); !strings.Contains(got, "no se puede programar") {
+			t.Fatalf("reply %d = %q, want rejection", i, got)
+		}
+	}
+	st, _ := svc.Store.Load()
+	if len(st.Jobs) != 0 {
+		t.Fatalf("jobs = %v, want none", st.Jobs)
+	}
+}
+
+func TestHandleScheduleRepliesInChatLanguage(t *testing.T) {
+	svc, reg := newTestService(t)
+	reg.Handle("stats", "desc", commands.ScopePublic, func(ctx *commands.Context) error { return nil })
+	bot, client := testutil.NewFakeBot()
+
+	for _, args := range []string{"add 0 8 * /stats", "add 0 25 * * * /stats", "add 0 0 30 2 * /stats", "list"} {
+		ctx := scheduleContext(bot, args)
+		ctx.Lang = "en"
+		if err := svc.HandleSchedule(ctx); err != nil {
+			t.Fatalf("%s returned error: %v", args, err)
+		}
+	}
+
+	want := []string{
+		"Invalid cron expression: expected 5 fields, got 4",
+		"Invalid cron expression: hour out of range (0-23): 25",
+		"Next run: never",
+		"next: never",
+	}
+	for i, req := range client.Requests() {
+		if got := req.Values.Get("text"); !strings.Contains(got, want[i]) {
+			t.Fatalf("reply %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestRunDueDispatchesThroughRegistry(t *testing.T) {
+	svc, reg := newTestService(t)
+
+	called := make(chan *commands.Context, 2)
+	reg.Handle("stats", "desc", commands.ScopePublic, func(ctx *commands.Context) error {
+		called <- ctx
+		return nil
+	})
+	reg.Handle("reboot", "desc", commands.ScopeOwner, func(ctx *commands.Context) error {
+		called <- ctx
+		return nil
+	}, commands.OwnerOnly())
+
+	created := time.Date(2025, time.March, 14, 7, 59, 0, 0, time.Local)
+	if err := svc.Store.Update(func(st *State) error {
+		st.Jobs = []Job{
+			{ID: 1, Spec: "0 8 * * *", Command: "stats", Args: "fresh", ChatID: 7, CreatedAt: created},
+			{ID: 2, Spec: "0 8 * * *", Command: "reboot", ChatID: 7, CreatedAt: created},
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	bot, client := testutil.NewFakeBot()
+	svc.runDue(context.Background(), bot, created.Add(90*time.Second))
+
+	select {
+	case ctx := <-called:
+		if ctx.Command != "stats" || ctx.Arguments != "fresh" || ctx.Update.Message.Chat.ID != 7 {
+			t.Fatalf("unexpected context: command=%s args=%q", ctx.Command, ctx.Arguments)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("stats job was not dispatched")
+	}
+
+	// The owner-only job is rejected by the middleware for a non-owner chat.
+	deadline := time.Now().Add(time.Second)
+	for len(client.Requests()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reqs := client.Requests(); len(reqs) != 1 || reqs[0].Values.Get("text") != "No autorizado." {
+		t.Fatalf("requests = %v, want single rejection", reqs)
+	}
+	select {
+	case ctx := <-called:
+		t.Fatalf("unexpected dispatch of /%s", ctx.Command)
+	default:
+	}
+
+	st, _ := svc.Store.Load()
+	for _, job := range st.Jobs {
+		if job.LastRun.IsZero() {
+			t.Fatalf("job #%d LastRun not updated", job.ID)
+		}
+	}
+}
+
+func TestReportMissedNotifiesChat(t *testing.T) {
+	svc, _ := newTestService(t)
+	created := time.Date(2025, time.March, 14, 10, 30, 0, 0, time.Local)
+	if err := svc.Store.Update(func(st *State) error {
+		st.Jobs = []Job{{ID: 3, Spec: "0 * * * *", Command: "stats", ChatID: 9, CreatedAt: created}}
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	languages, err := i18n.NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := languages.Set(9, "en"); err != nil {
+		t.Fatal(err)
+	}
+	svc.Languages = languages
+
+	bot, client := testutil.NewFakeBot()
+	now := created.Add(3 * time.Hour)
+	svc.reportMissed(bot, now)
+
+	reqs := client.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 notice, got %d", len(reqs))
+	}
+	if reqs[0].Values.Get("chat_id") != "9" {
+		t.Fatalf("chat_id = %s, want 9", reqs[0].Values.Get("chat_id"))
+	}
+	if got := reqs[0].Values.Get("text"); !strings.Contains(got, "#3") || !strings.Contains(got, "3 runs") {
+		t.Fatalf("notice = %q", got)
+	}
+
+	st, _ := svc.Store.Load()
+	if !st.Jobs[0].LastRun.Equal(now) {
+		t.Fatalf("LastRun = %v, want %v", st.Jobs[0].LastRun, now)
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Job is a persisted scheduled command.
+type Job struct {
+	ID        int       `json:"id"`
+	Spec      string    `json:"spec"`
+	Command   string    `json:"command"`
+	Args      string    `json:"args,omitempty"`
+	ChatID    int64     `json:"chat_id"`
+	CreatedAt time.Time `json:"created_at"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+}
+
+// State is the on-disk representation of every scheduled job.
+type State struct {
+	NextID int   `json:"next_id"`
+	Jobs   []Job `json:"jobs"`
+}
+
+// Store provides atomic read/write of the job list backed by a JSON file
+// protected with a file lock.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store that reads/writes at the given path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the current job list from disk. A missing file yields an empty
+// state.
+func (s *Store) Load() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return State{}, fmt.Errorf("lock schedule file: %w", err)
+	}
+	defer fl.Unlock()
+
+	return s.readUnsafe()
+}
+
+// Update applies fn to the current state and writes back the result.
+func (s *Store) Update(fn func(*State) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("lock schedule file: %w", err)
+	}
+	defer fl.Unlock()
+
+	st, err := s.readUnsafe()
+	if err != nil {
+		return err
+	}
+	if err := fn(&st); err != nil {
+		return err
+	}
+	return s.writeUnsafe(st)
+}
+
+func (s *Store) readUnsafe() (State, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return State{NextID: 1}, nil
+		}
+		return State{}, fmt.Errorf("read schedule: %w", err)
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return State{}, fmt.Errorf("parse schedule: %w", err)
+	}
+	if st.NextID <= 0 {
+		st.NextID = 1
+	}
+	return st, nil
+}
+
+func (s *Store) writeUnsafe(st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal schedule: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("write schedule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package system
+
+import "os/exec"
+
+// setProcessGroup is a no-op where process groups are unavailable; cancelling
+// kills only the command itself.
+func setProcessGroup(cmd *exec.Cmd) {}
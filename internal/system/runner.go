@@ -1,14 +1,20 @@
 package system
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
+
+	"serverbot/internal/redact"
 )
 
+// waitDelay bounds how long Stream waits for output after the process has
+// exited or been killed.
+const waitDelay = 5 * time.Second
+
 // Runner executes system commands with context support and output capture.
 type Runner interface {
 	Run(ctx context.Context, name string, args ...string) (stdout string, stderr string, err error)
@@ -23,40 +29,60 @@ func NewCommandRunner() *CommandRunner {
 	return &CommandRunner{}
 }
 
-// Run executes the command, returning stdout, stderr and a wrapped error when it fails.
+// Run executes the command, returning stdout, stderr and a wrapped error when
+// it fails. Each stream keeps at most DefaultMaxOutput bytes.
 func (r *CommandRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	res, err := r.Stream(ctx, StreamOptions{}, name, args...)
+	return res.Stdout, res.Stderr, err
+}
+
+// Stream executes the command in its own process group, passing output to
+// opts.OnLine as it arrives. Cancelling ctx kills the whole group, so
+// children such as "docker compose run" do not outlive the command. A
+// non-zero exit is reported as *ExitError.
+func (r *CommandRunner) Stream(ctx context.Context, opts StreamOptions, name string, args ...string) (Result, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.Dir
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	setProcessGroup(cmd)
+	// Give up on pipes still held open by a stray grandchild after a kill.
+	cmd.WaitDelay = waitDelay
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	stdout, stderr := newSinks(opts)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
 	err := cmd.Run()
-	outStr := stdout.String()
-	errStr := stderr.String()
+	exitCode := -1
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	res := result(stdout, stderr, exitCode)
 
 	if ctxErr := ctx.Err(); ctxErr != nil {
-		return outStr, errStr, fmt.Errorf("command cancelled: %w", ctxErr)
+		return res, fmt.Errorf("command cancelled: %w", ctxErr)
+	}
+	if exitCode > 0 {
+		err = &ExitError{Code: exitCode}
 	}
 	if err != nil {
-		return outStr, errStr, fmt.Errorf("command %q failed: %w", buildCommandString(name, args), err)
+		return res, fmt.Errorf("command %q failed: %w", buildCommandString(name, args), err)
 	}
-
-	return outStr, errStr, nil
+	return res, nil
 }
 
+// buildCommandString renders a command line for error messages. Secrets are
+// redacted, since the errors end up in logs, replies and the audit log.
 func buildCommandString(name string, args []string) string {
 	var b strings.Builder
 	b.WriteString(name)
-	if len(args) == 0 {
-		return b.String()
-	}
-
 	for _, arg := range args {
 		b.WriteByte(' ')
 		b.WriteString(arg)
 	}
-	return b.String()
+	return redact.String(b.String())
 }
 
 // WithTimeout creates a cancellable context with the provided timeout.
@@ -15,6 +15,10 @@ func TestBuildCommandString(t *testing.T) {
 	if got := buildCommandString("go", nil); got != "go" {
 		t.Fatalf("buildCommandString() with nil args = %q, want go", got)
 	}
+	got = buildCommandString("mysqldump", []string{"--password=hunter22", "app"})
+	if got != "mysqldump --password=******** app" {
+		t.Fatalf("buildCommandString() with a password = %q", got)
+	}
 }
 
 func TestWithTimeout(t *testing.T) {
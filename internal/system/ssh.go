@@ -0,0 +1,383 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHMaxSessions = 8
+	defaultSSHMaxConns    = 2
+	defaultSSHIdleTimeout = 5 * time.Minute
+	sshDialTimeout        = 10 * time.Second
+)
+
+var sshHostNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// SSHTarget is one SSH_HOSTS entry.
+type SSHTarget struct {
+	Name string
+	User string
+	Addr string // host:port
+}
+
+// ParseSSHTarget reads "name=user@host[:port]"; the port defaults to 22.
+func ParseSSHTarget(spec string) (SSHTarget, error) {
+	name, dest, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	user, host, hasUser := strings.Cut(strings.TrimSpace(dest), "@")
+	if !ok || !hasUser || name == "" || user == "" || host == "" {
+		return SSHTarget{}, fmt.Errorf("%q: want name=user@host[:port]", spec)
+	}
+	if !sshHostNamePattern.MatchString(name) {
+		return SSHTarget{}, fmt.Errorf("%q: host name may only contain a-z, 0-9, _ and -", spec)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	return SSHTarget{Name: name, User: user, Addr: host}, nil
+}
+
+// LoadSSHAuth reads a private key and a known_hosts file. Host keys missing
+// from known_hosts are rejected; there is no trust-on-first-use.
+func LoadSSHAuth(keyFile, knownHostsFile string) (ssh.Signer, ssh.HostKeyCallback, error) {
+	pem, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read SSH key: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse SSH key %s: %w", keyFile, err)
+	}
+	hostKeys, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read known_hosts: %w", err)
+	}
+	return signer, hostKeys, nil
+}
+
+// SSHRunner runs commands on a remote host over SSH. Sessions share pooled
+// connections: a connection carries up to MaxSessions commands at once, a new
+// one is dialed when all are busy (up to MaxConns), and idle connections are
+// closed after IdleTimeout.
+type SSHRunner struct {
+	Addr   string
+	Config *ssh.ClientConfig
+
+	MaxSessions int
+	MaxConns    int
+	IdleTimeout time.Duration
+
+	mu    sync.Mutex
+	conns []*sshConn
+	// dialing holds a token while a connection is dialed; waiting for it
+	// gives up when the caller's context ends.
+	dialing chan struct{}
+}
+
+type sshConn struct {
+	client *ssh.Client
+	active int
+	idle   *time.Timer
+}
+
+// NewSSHRunner returns a runner for user@addr authenticated with signer and
+// verifying the server against hostKeys.
+func NewSSHRunner(user, addr string, signer ssh.Signer, hostKeys ssh.HostKeyCallback) *SSHRunner {
+	return &SSHRunner{
+		Addr: addr,
+		Config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeys,
+			Timeout:         sshDialTimeout,
+		},
+	}
+}
+
+// Run executes the command through the remote shell with every argument
+// quoted, returning stdout, stderr and a wrapped error when it fails. Each
+// stream keeps at most DefaultMaxOutput bytes.
+func (r *SSHRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	res, err := r.Stream(ctx, StreamOptions{}, name, args...)
+	return res.Stdout, res.Stderr, err
+}
+
+// Stream runs the command remotely, passing output to opts.OnLine as it
+// arrives. Dir and Env are applied through the remote shell. Cancelling ctx
+// sends SIGKILL and closes the session. A non-zero exit is reported as
+// *ExitError.
+func (r *SSHRunner) Stream(ctx context.Context, opts StreamOptions, name string, args ...string) (Result, error) {
+	command := shellJoin(name, args)
+	if len(opts.Env) > 0 {
+		command = shellJoin("env", opts.Env) + " " + command
+	}
+	if opts.Dir != "" {
+		command = "cd " + shellQuote(opts.Dir) + " && " + command
+	}
+
+	stdout, stderr := newSinks(opts)
+	conn, session, err := r.session(ctx)
+	if err != nil {
+		return result(stdout, stderr, -1), fmt.Errorf("command %q on %s: %w", buildCommandString(name, args), r.Addr, err)
+	}
+	defer r.release(conn)
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if err := session.Start(command); err != nil {
+		return result(stdout, stderr, -1), fmt.Errorf("command %q on %s: %w", buildCommandString(name, args), r.Addr, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		<-done
+		return result(stdout, stderr, -1), fmt.Errorf("command cancelled: %w", ctx.Err())
+	case err = <-done:
+	}
+
+	exitCode := 0
+	var exitErr *ssh.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.Signal() == "":
+		exitCode = exitErr.ExitStatus()
+		err = &ExitError{Code: exitCode}
+	case err != nil:
+		exitCode = -1
+	}
+	res := result(stdout, stderr, exitCode)
+	if err != nil {
+		return res, fmt.Errorf("command %q on %s failed: %w", buildCommandString(name, args), r.Addr, err)
+	}
+	return res, nil
+}
+
+// Close closes every pooled connection.
+func (r *SSHRunner) Close() error {
+	r.mu.Lock()
+	conns := r.conns
+	r.conns = nil
+	for _, c := range conns {
+		if c.idle != nil {
+			c.idle.Stop()
+		}
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	for _, c := range conns {
+		if err := c.client.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// session opens a session on a pooled connection. A broken connection is
+// dropped and the session is retried once on a fresh dial.
+func (r *SSHRunner) session(ctx context.Context) (*sshConn, *ssh.Session, error) {
+	for attempt := 0; ; attempt++ {
+		conn, err := r.acquire(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		session, err := conn.client.NewSession()
+		if err == nil {
+			return conn, session, nil
+		}
+		r.release(conn)
+		// A refused channel comes from a live connection (for example the
+		// server's MaxSessions); anything else means it is gone.
+		var refused *ssh.OpenChannelError
+		if errors.As(err, &refused) || attempt > 0 {
+			return nil, nil, err
+		}
+		r.discard(conn)
+	}
+}
+
+// acquire returns the least busy connection with a free session slot,
+// dialing a new one when none has room and the pool is not full. Dials are
+// serialized so a burst of commands shares the first new connection; both
+// the wait and the dial end with ctx.
+func (r *SSHRunner) acquire(ctx context.Context) (*sshConn, error) {
+	if c := r.pick(); c != nil {
+		return c, nil
+	}
+
+	r.mu.Lock()
+	if r.dialing == nil {
+		r.dialing = make(chan struct{}, 1)
+	}
+	dialing := r.dialing
+	r.mu.Unlock()
+	select {
+	case dialing <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("ssh dial: %w", ctx.Err())
+	}
+	defer func() { <-dialing }()
+	if c := r.pick(); c != nil {
+		return c, nil
+	}
+
+	client, err := r.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ssh dial: %w", err)
+	}
+
+	conn := &sshConn{client: client}
+	r.mu.Lock()
+	r.conns = append(r.conns, conn)
+	r.claim(conn)
+	r.mu.Unlock()
+
+	// Drop the connection from the pool as soon as the server closes it.
+	go func() {
+		_ = client.Wait()
+		r.discard(conn)
+	}()
+	return conn, nil
+}
+
+// dial connects and runs the SSH handshake, giving up when ctx ends or after
+// Config.Timeout.
+func (r *SSHRunner) dial(ctx context.Context) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: r.Config.Timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", r.Addr)
+	if err != nil {
+		return nil, err
+	}
+	// The handshake has no context of its own: a deadline bounds it and
+	// closing the connection interrupts it.
+	if r.Config.Timeout > 0 {
+		_ = nc.SetDeadline(time.Now().Add(r.Config.Timeout))
+	}
+	stop := context.AfterFunc(ctx, func() { nc.Close() })
+	conn, chans, reqs, err := ssh.NewClientConn(nc, r.Addr, r.Config)
+	if !stop() {
+		err = errors.Join(ctx.Err(), err)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	_ = nc.SetDeadline(time.Time{})
+	return ssh.NewClient(conn, chans, reqs), nil
+}
+
+// pick claims the least busy pooled connection if it has a free slot, or
+// if the pool is full. It returns nil when a new connection should be dialed.
+func (r *SSHRunner) pick() *sshConn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var best *sshConn
+	for _, c := range r.conns {
+		if best == nil || c.active < best.active {
+			best = c
+		}
+	}
+	if best == nil || (best.active >= r.maxSessions() && len(r.conns) < r.maxConns()) {
+		return nil
+	}
+	r.claim(best)
+	return best
+}
+
+// claim marks one more session on c. r.mu must be held.
+func (r *SSHRunner) claim(c *sshConn) {
+	c.active++
+	if c.idle != nil {
+		c.idle.Stop()
+		c.idle = nil
+	}
+}
+
+// release ends a session on c and schedules the idle close.
+func (r *SSHRunner) release(c *sshConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.active--
+	if c.active > 0 {
+		return
+	}
+	c.idle = time.AfterFunc(r.idleTimeout(), func() {
+		r.mu.Lock()
+		idle := c.active == 0
+		r.mu.Unlock()
+		if idle {
+			r.discard(c)
+		}
+	})
+}
+
+// discard removes c from the pool and closes it.
+func (r *SSHRunner) discard(c *sshConn) {
+	r.mu.Lock()
+	for i, other := range r.conns {
+		if other == c {
+			r.conns = append(r.conns[:i], r.conns[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+	_ = c.client.Close()
+}
+
+func (r *SSHRunner) maxSessions() int {
+	if r.MaxSessions > 0 {
+		return r.MaxSessions
+	}
+	return defaultSSHMaxSessions
+}
+
+func (r *SSHRunner) maxConns() int {
+	if r.MaxConns > 0 {
+		return r.MaxConns
+	}
+	return defaultSSHMaxConns
+}
+
+func (r *SSHRunner) idleTimeout() time.Duration {
+	if r.IdleTimeout > 0 {
+		return r.IdleTimeout
+	}
+	return defaultSSHIdleTimeout
+}
+
+// shellJoin quotes name and args for a POSIX shell, since SSH hands the
+// remote shell a single command string.
+func shellJoin(name string, args []string) string {
+	words := make([]string, 0, len(args)+1)
+	for _, word := range append([]string{name}, args...) {
+		words = append(words, shellQuote(word))
+	}
+	return strings.Join(words, " ")
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@%+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,347 @@
+package system
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshTestServer is an in-process SSH server. "exec" requests echo the
+// command on stdout, except "fail" (stderr and exit status 3) and "sleep"
+// (blocks until the client signals or closes the session).
+type sshTestServer struct {
+	addr    string
+	hostKey ssh.Signer
+	dials   atomic.Int32
+
+	mu    sync.Mutex
+	conns []*ssh.ServerConn
+}
+
+func newTestKey(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, priv
+}
+
+func startSSHServer(t *testing.T, authorized ssh.PublicKey) *sshTestServer {
+	t.Helper()
+	hostKey, _ := newTestKey(t)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	srv := &sshTestServer{addr: ln.Addr().String(), hostKey: hostKey}
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(nc, config)
+		}
+	}()
+	return srv
+}
+
+func (s *sshTestServer) serve(nc net.Conn, config *ssh.ServerConfig) {
+	conn, chans, reqs, err := ssh.NewServerConn(nc, config)
+	if err != nil {
+		nc.Close()
+		return
+	}
+	s.dials.Add(1)
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+
+	go ssh.DiscardRequests(reqs)
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			nch.Reject(ssh.UnknownChannelType, "session only")
+			continue
+		}
+		ch, requests, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		go s.session(ch, requests)
+	}
+}
+
+func (s *sshTestServer) session(ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range requests {
+		if req.Type != "exec" {
+			req.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			req.Reply(false, nil)
+			return
+		}
+		req.Reply(true, nil)
+
+		status := uint32(0)
+		switch payload.Command {
+		case "sleep":
+			for req := range requests {
+				if req.Type == "signal" {
+					break
+				}
+			}
+			return
+		case "fail":
+			ch.Stderr().Write([]byte("boom\n"))
+			status = 3
+		default:
+			ch.Write([]byte(payload.Command + "\n"))
+		}
+		ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+		return
+	}
+}
+
+// dropConnections closes every server-side connection.
+func (s *sshTestServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+// newTestSSHRunner returns a runner for srv using key files on disk, the way
+// the bot loads them.
+func newTestSSHRunner(t *testing.T, srv *sshTestServer, priv ed25519.PrivateKey, hostKey ssh.PublicKey) (*SSHRunner, error) {
+	t.Helper()
+	dir := t.TempDir()
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	knownHosts := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(knownHosts, []byte(knownhosts.Line([]string{srv.addr}, hostKey)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, hostKeys, err := LoadSSHAuth(keyFile, knownHosts)
+	if err != nil {
+		return nil, err
+	}
+	runner := NewSSHRunner("bot", srv.addr, signer, hostKeys)
+	t.Cleanup(func() { runner.Close() })
+	return runner, nil
+}
+
+func TestSSHRunnerRun(t *testing.T) {
+	clientKey, priv := newTestKey(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	runner, err := newTestSSHRunner(t, srv, priv, srv.hostKey.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	stdout, _, err := runner.Run(ctx, "docker", "logs", "--tail", "20", "it's; rm -rf /")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := `docker logs --tail 20 'it'\''s; rm -rf /'` + "\n"; stdout != want {
+		t.Fatalf("remote command = %q, want %q", stdout, want)
+	}
+
+	_, stderr, err := runner.Run(ctx, "fail")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || stderr != "boom\n" {
+		t.Fatalf("Run(fail) = stderr %q, err %v; want exit status 3", stderr, err)
+	}
+	if !strings.Contains(err.Error(), srv.addr) {
+		t.Fatalf("error %q does not name the host", err)
+	}
+}
+
+func TestSSHRunnerStream(t *testing.T) {
+	clientKey, priv := newTestKey(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	runner, err := newTestSSHRunner(t, srv, priv, srv.hostKey.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []Line
+	res, err := runner.Stream(context.Background(), StreamOptions{
+		Dir:    "/srv/my app",
+		Env:    []string{"LANG=C"},
+		OnLine: func(l Line) { lines = append(lines, l) },
+	}, "uptime")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	want := "cd '/srv/my app' && env LANG=C uptime"
+	if res.ExitCode != 0 || len(lines) != 1 || lines[0] != (Line{Text: want}) {
+		t.Fatalf("Stream() = %+v, lines %+v; want %q", res, lines, want)
+	}
+}
+
+func TestSSHRunnerPoolsConnections(t *testing.T) {
+	clientKey, priv := newTestKey(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	runner, err := newTestSSHRunner(t, srv, priv, srv.hostKey.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := runner.Run(context.Background(), "uptime"); err != nil {
+				t.Errorf("Run() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, _, err := runner.Run(context.Background(), "uptime"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.dials.Load(); got > 2 {
+		t.Fatalf("7 commands opened %d connections, want them pooled", got)
+	}
+
+	// A connection closed by the server is replaced transparently.
+	before := srv.dials.Load()
+	srv.dropConnections()
+	if _, _, err := runner.Run(context.Background(), "uptime"); err != nil {
+		t.Fatalf("Run() after the server dropped the connection: %v", err)
+	}
+	if srv.dials.Load() != before+1 {
+		t.Fatalf("dials = %d, want a reconnect", srv.dials.Load())
+	}
+}
+
+func TestSSHRunnerRejectsUnknownHostKey(t *testing.T) {
+	clientKey, priv := newTestKey(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	other, _ := newTestKey(t)
+	runner, err := newTestSSHRunner(t, srv, priv, other.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = runner.Run(context.Background(), "uptime")
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		t.Fatalf("Run() error = %v, want a known_hosts mismatch", err)
+	}
+}
+
+func TestSSHRunnerCancel(t *testing.T) {
+	clientKey, priv := newTestKey(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	runner, err := newTestSSHRunner(t, srv, priv, srv.hostKey.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = runner.Run(ctx, "sleep")
+	if err == nil || !strings.Contains(err.Error(), "command cancelled") {
+		t.Fatalf("Run() error = %v, want cancelled", err)
+	}
+}
+
+func TestSSHRunnerDialHonoursContext(t *testing.T) {
+	// A server that accepts TCP but never answers the SSH handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer nc.Close()
+		}
+	}()
+
+	signer, _ := newTestKey(t)
+	runner := NewSSHRunner("admin", ln.Addr().String(), signer, ssh.InsecureIgnoreHostKey())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, _, err := runner.Run(ctx, "uptime")
+			errs <- err
+		}()
+	}
+	for range 2 {
+		if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Run() error = %v, want the context deadline", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Run() returned after %v, want it to stop with the context", elapsed)
+	}
+}
+
+func TestParseSSHTarget(t *testing.T) {
+	target, err := ParseSSHTarget(" NAS = admin@10.0.0.5 ")
+	if err != nil || target != (SSHTarget{Name: "nas", User: "admin", Addr: "10.0.0.5:22"}) {
+		t.Fatalf("ParseSSHTarget() = %+v, %v", target, err)
+	}
+	if target, _ := ParseSSHTarget("pi=root@[fe80::1]:2222"); target.Addr != "[fe80::1]:2222" {
+		t.Fatalf("IPv6 target addr = %q", target.Addr)
+	}
+	for _, spec := range []string{"nas", "nas=10.0.0.5", "=root@x", "n@s=root@x"} {
+		if _, err := ParseSSHTarget(spec); err == nil {
+			t.Errorf("ParseSSHTarget(%q) expected error", spec)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultMaxOutput is how much of each stream Run keeps in memory.
+	DefaultMaxOutput = 4 << 20
+	// maxLineLength splits longer lines into several OnLine calls.
+	maxLineLength = 64 << 10
+)
+
+// StreamRunner runs a command while delivering its output as it arrives.
+// CommandRunner and SSHRunner implement it.
+type StreamRunner interface {
+	Stream(ctx context.Context, opts StreamOptions, name string, args ...string) (Result, error)
+}
+
+// StreamOptions configures a Stream call.
+type StreamOptions struct {
+	// Dir is the working directory; empty keeps the runner's.
+	Dir string
+	// Env entries ("KEY=value") are added to the inherited environment.
+	Env []string
+	// OnLine receives every line of output without its newline, in order
+	// within each stream. Calls are serialized.
+	OnLine func(Line)
+	// MaxOutput caps the bytes of each stream kept in the Result. Output past
+	// the cap still reaches OnLine and is replaced by a truncation marker.
+	// 0 selects DefaultMaxOutput; a negative value keeps nothing.
+	MaxOutput int
+}
+
+// Line is one line of command output.
+type Line struct {
+	Text   string
+	Stderr bool
+}
+
+// Result is the outcome of a finished command.
+type Result struct {
+	Stdout string
+	Stderr string
+	// ExitCode is the exit status, or -1 when the command did not exit on
+	// its own (it failed to start, was killed or was cancelled).
+	ExitCode int
+	// Truncated reports that MaxOutput dropped part of the output.
+	Truncated bool
+}
+
+// ExitError reports a command that exited with a non-zero status.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// outputSink splits one stream into lines for OnLine and keeps up to max
+// bytes of it.
+type outputSink struct {
+	stderr  bool
+	max     int
+	onLine  func(Line)
+	emitMu  *sync.Mutex
+	kept    bytes.Buffer
+	dropped int
+	partial []byte
+}
+
+func newSinks(opts StreamOptions) (stdout, stderr *outputSink) {
+	limit := opts.MaxOutput
+	if limit == 0 {
+		limit = DefaultMaxOutput
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	var mu sync.Mutex
+	stdout = &outputSink{max: limit, onLine: opts.OnLine, emitMu: &mu}
+	stderr = &outputSink{stderr: true, max: limit, onLine: opts.OnLine, emitMu: &mu}
+	return stdout, stderr
+}
+
+func (s *outputSink) Write(p []byte) (int, error) {
+	if room := s.max - s.kept.Len(); room > 0 {
+		s.kept.Write(p[:min(room, len(p))])
+		s.dropped += max(len(p)-room, 0)
+	} else {
+		s.dropped += len(p)
+	}
+
+	if s.onLine == nil {
+		return len(p), nil
+	}
+	data := p
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			s.partial = append(s.partial, data...)
+			if len(s.partial) >= maxLineLength {
+				s.emit(s.partial)
+				s.partial = s.partial[:0]
+			}
+			break
+		}
+		s.partial = append(s.partial, data[:i]...)
+		s.emit(s.partial)
+		s.partial = s.partial[:0]
+		data = data[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits a final line that had no trailing newline.
+func (s *outputSink) flush() {
+	if s.onLine != nil && len(s.partial) > 0 {
+		s.emit(s.partial)
+		s.partial = nil
+	}
+}
+
+func (s *outputSink) emit(line []byte) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+	s.onLine(Line{Text: string(bytes.TrimSuffix(line, []byte("\r"))), Stderr: s.stderr})
+}
+
+// String returns the kept output, followed by a marker when some was dropped.
+func (s *outputSink) String() string {
+	if s.dropped == 0 || s.max == 0 {
+		return s.kept.String()
+	}
+	return s.kept.String() + fmt.Sprintf("\n[... %d bytes truncated]\n", s.dropped)
+}
+
+// result assembles the Result from both sinks.
+func result(stdout, stderr *outputSink, exitCode int) Result {
+	stdout.flush()
+	stderr.flush()
+	return Result{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		ExitCode:  exitCode,
+		Truncated: stdout.max > 0 && (stdout.dropped > 0 || stderr.dropped > 0),
+	}
+}
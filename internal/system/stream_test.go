@@ -0,0 +1,98 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestStreamDeliversLines(t *testing.T) {
+	requireShell(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "marker"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var lines []Line
+	res, err := NewCommandRunner().Stream(context.Background(), StreamOptions{
+		Dir:    dir,
+		Env:    []string{"GREETING=hola"},
+		OnLine: func(l Line) { lines = append(lines, l) },
+	}, "sh", "-c", `echo "$GREETING"; test -f marker || exit 9; echo oops >&2; printf 'sin salto'`)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if res.ExitCode != 0 || res.Stdout != "hola\nsin salto" || res.Stderr != "oops\n" {
+		t.Fatalf("Stream() = %+v", res)
+	}
+
+	var stdout []string
+	sawStderr := false
+	for _, l := range lines {
+		if l.Stderr {
+			sawStderr = l.Text == "oops"
+			continue
+		}
+		stdout = append(stdout, l.Text)
+	}
+	if strings.Join(stdout, "|") != "hola|sin salto" || !sawStderr {
+		t.Fatalf("OnLine got %+v", lines)
+	}
+}
+
+func TestStreamTruncatesOutput(t *testing.T) {
+	requireShell(t)
+	count := 0
+	res, err := NewCommandRunner().Stream(context.Background(), StreamOptions{
+		MaxOutput: 10,
+		OnLine:    func(Line) { count++ },
+	}, "sh", "-c", "for i in 1 2 3 4 5 6 7 8; do echo line$i; done")
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if !res.Truncated || !strings.HasPrefix(res.Stdout, "line1\nline") || !strings.Contains(res.Stdout, "[... 38 bytes truncated]") {
+		t.Fatalf("Stream() = %+v, want truncated output", res)
+	}
+	if count != 8 {
+		t.Fatalf("OnLine called %d times, want every line", count)
+	}
+}
+
+func TestStreamExitCode(t *testing.T) {
+	requireShell(t)
+	res, err := NewCommandRunner().Stream(context.Background(), StreamOptions{}, "sh", "-c", "exit 3")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || res.ExitCode != 3 {
+		t.Fatalf("Stream() = %+v, %v; want exit status 3", res, err)
+	}
+}
+
+func TestStreamCancelKillsProcessGroup(t *testing.T) {
+	requireShell(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	// The backgrounded sleep keeps stdout open; only a group kill ends it.
+	res, err := NewCommandRunner().Stream(ctx, StreamOptions{}, "sh", "-c", "sleep 30 & wait")
+	if err == nil || !strings.Contains(err.Error(), "command cancelled") {
+		t.Fatalf("Stream() error = %v, want cancelled", err)
+	}
+	if res.ExitCode != -1 {
+		t.Fatalf("ExitCode = %d, want -1", res.ExitCode)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("Stream() returned after %s, want the children killed", elapsed)
+	}
+}
@@ -0,0 +1,136 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/system"
+)
+
+// showProperties are the unit properties requested from systemctl show.
+const showProperties = "Id,Description,LoadState,ActiveState,SubState,UnitFileState,MainPID,StateChangeTimestamp"
+
+// CLIClient drives systemd through systemctl. State-changing verbs run under
+// sudo, like /reboot.
+type CLIClient struct {
+	Runner system.Runner
+}
+
+// Status implements Client.
+func (c *CLIClient) Status(ctx context.Context, unit string) (Unit, error) {
+	stdout, stderr, err := c.Runner.Run(ctx, "systemctl", "show", unit, "--no-pager", "--property="+showProperties)
+	if err != nil {
+		return Unit{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	u := parseShow(stdout)
+	if u.Name == "" {
+		u.Name = unit
+	}
+	return u, nil
+}
+
+// Start implements Client.
+func (c *CLIClient) Start(ctx context.Context, unit string) error {
+	return c.control(ctx, "start", unit)
+}
+
+// Stop implements Client.
+func (c *CLIClient) Stop(ctx context.Context, unit string) error {
+	return c.control(ctx, "stop", unit)
+}
+
+// Restart implements Client.
+func (c *CLIClient) Restart(ctx context.Context, unit string) error {
+	return c.control(ctx, "restart", unit)
+}
+
+// Enable implements Client.
+func (c *CLIClient) Enable(ctx context.Context, unit string) error {
+	return c.control(ctx, "enable", unit)
+}
+
+// Failed implements Client.
+func (c *CLIClient) Failed(ctx context.Context) ([]Unit, error) {
+	stdout, stderr, err := c.Runner.Run(ctx, "systemctl", "list-units", "--state=failed", "--all", "--plain", "--no-legend", "--no-pager")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return parseListUnits(stdout), nil
+}
+
+func (c *CLIClient) control(ctx context.Context, verb, unit string) error {
+	_, stderr, err := c.Runner.Run(ctx, "sudo", "systemctl", verb, "--no-ask-password", unit)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// parseShow decodes the KEY=value lines printed by systemctl show.
+func parseShow(out string) Unit {
+	var u Unit
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			u.Name = value
+		case "Description":
+			u.Description = value
+		case "LoadState":
+			u.LoadState = value
+		case "ActiveState":
+			u.ActiveState = value
+		case "SubState":
+			u.SubState = value
+		case "UnitFileState":
+			u.UnitFileState = value
+		case "MainPID":
+			if pid, err := strconv.ParseUint(value, 10, 32); err == nil {
+				u.MainPID = uint32(pid)
+			}
+		case "StateChangeTimestamp":
+			u.Since = parseTimestamp(value)
+		}
+	}
+	return u
+}
+
+// parseTimestamp reads systemd's "Mon 2006-01-02 15:04:05 MST" format.
+func parseTimestamp(value string) time.Time {
+	if value == "" || value == "n/a" {
+		return time.Time{}
+	}
+	if t, err := time.Parse("Mon 2006-01-02 15:04:05 MST", value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// parseListUnits decodes `systemctl list-units --plain --no-legend` rows:
+// UNIT LOAD ACTIVE SUB DESCRIPTION...
+func parseListUnits(out string) []Unit {
+	var units []Unit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (fields[0] == "●" || fields[0] == "*") {
+			fields = fields[1:]
+		}
+		if len(fields) < 4 {
+			continue
+		}
+		units = append(units, Unit{
+			Name:        fields[0],
+			LoadState:   fields[1],
+			ActiveState: fields[2],
+			SubState:    fields[3],
+			Description: strings.Join(fields[4:], " "),
+		})
+	}
+	return units
+}
@@ -0,0 +1,116 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"serverbot/internal/system"
+)
+
+// ErrInvalidUnit is returned for unit names that could be mistaken for flags
+// or contain characters systemd does not allow.
+var ErrInvalidUnit = errors.New("invalid unit name")
+
+// Unit is the typed state of a systemd unit.
+type Unit struct {
+	Name          string
+	Description   string
+	LoadState     string
+	ActiveState   string
+	SubState      string
+	UnitFileState string
+	MainPID       uint32
+	Since         time.Time
+}
+
+// Failed reports whether the unit is in the failed state.
+func (u Unit) Failed() bool {
+	return u.ActiveState == "failed"
+}
+
+// Settled reports whether the unit is not transitioning between states.
+func (u Unit) Settled() bool {
+	switch u.ActiveState {
+	case "activating", "deactivating", "reloading":
+		return false
+	}
+	return true
+}
+
+// Client manages systemd units.
+type Client interface {
+	Status(ctx context.Context, unit string) (Unit, error)
+	Start(ctx context.Context, unit string) error
+	Stop(ctx context.Context, unit string) error
+	Restart(ctx context.Context, unit string) error
+	Enable(ctx context.Context, unit string) error
+	Failed(ctx context.Context) ([]Unit, error)
+}
+
+// NewClient connects to systemd over the system D-Bus and falls back to the
+// systemctl CLI when the bus is unavailable. Calls the bus refuses for lack of
+// privileges are retried through the CLI, which runs systemctl under sudo.
+func NewClient(runner system.Runner, logger *log.Logger) Client {
+	cli := &CLIClient{Runner: runner}
+	bus, err := NewDBusClient(cli)
+	if err != nil {
+		if logger != nil {
+			logger.Printf("systemd: D-Bus unavailable, using systemctl: %v", err)
+		}
+		return cli
+	}
+	return bus
+}
+
+var unitTypes = map[string]bool{
+	"service": true, "socket": true, "timer": true, "target": true, "mount": true,
+	"automount": true, "path": true, "slice": true, "scope": true, "swap": true, "device": true,
+}
+
+var unitPattern = regexp.MustCompile(`^[A-Za-z0-9:_.@\\-]+$`)
+
+// NormalizeUnit validates a unit name and appends ".service" when it does not
+// end in a known unit type.
+func NormalizeUnit(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" || strings.HasPrefix(name, "-") || len(name) > 256 || !unitPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUnit, raw)
+	}
+	if !unitTypes[name[strings.LastIndex(name, ".")+1:]] {
+		name += ".service"
+	}
+	return name, nil
+}
+
+// WaitSettled polls the unit until it leaves a transitional state or ctx
+// ends, returning the last state seen.
+func WaitSettled(ctx context.Context, client Client, unit string) (Unit, error) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		state, err := client.Status(ctx, unit)
+		if err != nil || state.Settled() {
+			return state, err
+		}
+		select {
+		case <-ctx.Done():
+			return state, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// JournalTail returns the last lines journald holds for unit.
+func JournalTail(ctx context.Context, runner system.Runner, unit string, lines int) (string, error) {
+	stdout, stderr, err := runner.Run(ctx, "journalctl", "-u", unit, "-n", fmt.Sprint(lines), "--no-pager", "-o", "short-iso")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return strings.TrimSpace(stdout), nil
+}
@@ -0,0 +1,176 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	systemdDest     = "org.freedesktop.systemd1"
+	systemdPath     = dbus.ObjectPath("/org/freedesktop/systemd1")
+	managerIface    = "org.freedesktop.systemd1.Manager"
+	unitIface       = "org.freedesktop.systemd1.Unit"
+	serviceIface    = "org.freedesktop.systemd1.Service"
+	propertiesIface = "org.freedesktop.DBus.Properties"
+)
+
+// DBusClient talks to the systemd manager over the system bus.
+type DBusClient struct {
+	conn *dbus.Conn
+
+	// fallback handles state changes the bus rejects for lack of privileges.
+	fallback Client
+}
+
+// NewDBusClient connects to the system bus. fallback may be nil.
+func NewDBusClient(fallback Client) (*DBusClient, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect system bus: %w", err)
+	}
+	return &DBusClient{conn: conn, fallback: fallback}, nil
+}
+
+// Close releases the bus connection.
+func (c *DBusClient) Close() error {
+	return c.conn.Close()
+}
+
+// Status implements Client.
+func (c *DBusClient) Status(ctx context.Context, unit string) (Unit, error) {
+	var path dbus.ObjectPath
+	if err := c.manager().CallWithContext(ctx, managerIface+".LoadUnit", 0, unit).Store(&path); err != nil {
+		return Unit{}, fmt.Errorf("load unit %s: %w", unit, err)
+	}
+
+	obj := c.conn.Object(systemdDest, path)
+	var props map[string]dbus.Variant
+	if err := obj.CallWithContext(ctx, propertiesIface+".GetAll", 0, unitIface).Store(&props); err != nil {
+		return Unit{}, fmt.Errorf("unit properties %s: %w", unit, err)
+	}
+
+	u := Unit{
+		Name:          variantString(props["Id"]),
+		Description:   variantString(props["Description"]),
+		LoadState:     variantString(props["LoadState"]),
+		ActiveState:   variantString(props["ActiveState"]),
+		SubState:      variantString(props["SubState"]),
+		UnitFileState: variantString(props["UnitFileState"]),
+	}
+	if usec, ok := props["StateChangeTimestamp"].Value().(uint64); ok && usec > 0 {
+		u.Since = time.UnixMicro(int64(usec))
+	}
+
+	// MainPID only exists on service units.
+	var pid dbus.Variant
+	if err := obj.CallWithContext(ctx, propertiesIface+".Get", 0, serviceIface, "MainPID").Store(&pid); err == nil {
+		if v, ok := pid.Value().(uint32); ok {
+			u.MainPID = v
+		}
+	}
+	return u, nil
+}
+
+// Start implements Client.
+func (c *DBusClient) Start(ctx context.Context, unit string) error {
+	return c.job(ctx, "StartUnit", unit, func(f Client) error { return f.Start(ctx, unit) })
+}
+
+// Stop implements Client.
+func (c *DBusClient) Stop(ctx context.Context, unit string) error {
+	return c.job(ctx, "StopUnit", unit, func(f Client) error { return f.Stop(ctx, unit) })
+}
+
+// Restart implements Client.
+func (c *DBusClient) Restart(ctx context.Context, unit string) error {
+	return c.job(ctx, "RestartUnit", unit, func(f Client) error { return f.Restart(ctx, unit) })
+}
+
+// Enable implements Client.
+func (c *DBusClient) Enable(ctx context.Context, unit string) error {
+	var carriesInstallInfo bool
+	var changes [][]any
+	err := c.manager().CallWithContext(ctx, managerIface+".EnableUnitFiles", 0, []string{unit}, false, true).
+		Store(&carriesInstallInfo, &changes)
+	if err == nil {
+		err = c.manager().CallWithContext(ctx, managerIface+".Reload", 0).Err
+	}
+	if err != nil && c.fallback != nil && permissionDenied(err) {
+		return c.fallback.Enable(ctx, unit)
+	}
+	if err != nil {
+		return fmt.Errorf("enable %s: %w", unit, err)
+	}
+	return nil
+}
+
+// Failed implements Client.
+func (c *DBusClient) Failed(ctx context.Context) ([]Unit, error) {
+	var rows []struct {
+		Name        string
+		Description string
+		LoadState   string
+		ActiveState string
+		SubState    string
+		Following   string
+		Path        dbus.ObjectPath
+		JobID       uint32
+		JobType     string
+		JobPath     dbus.ObjectPath
+	}
+	if err := c.manager().CallWithContext(ctx, managerIface+".ListUnitsFiltered", 0, []string{"failed"}).Store(&rows); err != nil {
+		return nil, fmt.Errorf("list failed units: %w", err)
+	}
+
+	units := make([]Unit, 0, len(rows))
+	for _, row := range rows {
+		units = append(units, Unit{
+			Name:        row.Name,
+			Description: row.Description,
+			LoadState:   row.LoadState,
+			ActiveState: row.ActiveState,
+			SubState:    row.SubState,
+		})
+	}
+	return units, nil
+}
+
+func (c *DBusClient) job(ctx context.Context, method, unit string, fallback func(Client) error) error {
+	var jobPath dbus.ObjectPath
+	err := c.manager().CallWithContext(ctx, managerIface+"."+method, 0, unit, "replace").Store(&jobPath)
+	if err != nil && c.fallback != nil && permissionDenied(err) {
+		return fallback(c.fallback)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, unit, err)
+	}
+	return nil
+}
+
+func (c *DBusClient) manager() dbus.BusObject {
+	return c.conn.Object(systemdDest, systemdPath)
+}
+
+// permissionDenied reports whether polkit refused a call, which happens when
+// the bot does not run as root.
+func permissionDenied(err error) bool {
+	var dbusErr dbus.Error
+	if !errors.As(err, &dbusErr) {
+		return false
+	}
+	switch dbusErr.Name {
+	case "org.freedesktop.DBus.Error.AccessDenied",
+		"org.freedesktop.DBus.Error.InteractiveAuthorizationRequired":
+		return true
+	}
+	return false
+}
+
+func variantString(v dbus.Variant) string {
+	s, _ := v.Value().(string)
+	return s
+}
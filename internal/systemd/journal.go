@@ -0,0 +1,208 @@
+package systemd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/system"
+)
+
+// priorityNames maps syslog priorities to the names journalctl accepts.
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// ParsePriority accepts a syslog priority name or number (0-7).
+func ParsePriority(raw string) (int, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if n, err := strconv.Atoi(raw); err == nil && n >= 0 && n < len(priorityNames) {
+		return n, nil
+	}
+	for i, name := range priorityNames {
+		if raw == name {
+			return i, nil
+		}
+	}
+	switch raw {
+	case "error":
+		return 3, nil
+	case "warn":
+		return 4, nil
+	}
+	return 0, fmt.Errorf("unknown priority %q", raw)
+}
+
+// PriorityName returns the journalctl name of priority p.
+func PriorityName(p int) string {
+	if p < 0 || p >= len(priorityNames) {
+		return "?"
+	}
+	return priorityNames[p]
+}
+
+// JournalEntry is one record decoded from `journalctl -o json`.
+type JournalEntry struct {
+	Time       time.Time
+	Priority   int
+	Unit       string
+	Identifier string
+	PID        string
+	Message    string
+	Cursor     string
+}
+
+// JournalQuery selects entries for Journal.
+type JournalQuery struct {
+	Units       []string
+	Since       string
+	Priority    int // entries at this priority or more severe; -1 for all
+	Grep        string
+	Lines       int
+	AfterCursor string
+}
+
+// Args builds the journalctl arguments. Values are passed as --flag=value so
+// user input can never be read as a separate option.
+func (q JournalQuery) Args() []string {
+	args := []string{"--no-pager", "-o", "json"}
+	for _, unit := range q.Units {
+		args = append(args, "--unit="+unit)
+	}
+	if q.Since != "" {
+		args = append(args, "--since="+q.Since)
+	}
+	if q.Priority >= 0 {
+		args = append(args, "--priority="+strconv.Itoa(q.Priority))
+	}
+	if q.Grep != "" {
+		args = append(args, "--grep="+q.Grep)
+	}
+	if q.AfterCursor != "" {
+		args = append(args, "--after-cursor="+q.AfterCursor)
+	}
+	if q.Lines > 0 {
+		args = append(args, "--lines="+strconv.Itoa(q.Lines))
+	}
+	return args
+}
+
+// Journal runs journalctl for q and decodes its entries, oldest first.
+func Journal(ctx context.Context, runner system.Runner, q JournalQuery) ([]JournalEntry, error) {
+	stdout, stderr, err := runner.Run(ctx, "journalctl", q.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return ParseJournalJSON(stdout)
+}
+
+// ParseJournalJSON decodes the line-delimited output of `journalctl -o json`.
+func ParseJournalJSON(out string) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return nil, fmt.Errorf("decode journal entry: %w", err)
+		}
+
+		entry := JournalEntry{
+			Unit:       journalField(raw["_SYSTEMD_UNIT"]),
+			Identifier: journalField(raw["SYSLOG_IDENTIFIER"]),
+			PID:        journalField(raw["_PID"]),
+			Message:    journalField(raw["MESSAGE"]),
+			Cursor:     journalField(raw["__CURSOR"]),
+			Priority:   6,
+		}
+		if p, err := strconv.Atoi(journalField(raw["PRIORITY"])); err == nil {
+			entry.Priority = p
+		}
+		if usec, err := strconv.ParseInt(journalField(raw["__REALTIME_TIMESTAMP"]), 10, 64); err == nil {
+			entry.Time = time.UnixMicro(usec)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// journalField decodes a journal value: a string, or an array of bytes when
+// the field holds non-UTF-8 data.
+func journalField(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var b []byte
+	var ints []int
+	if err := json.Unmarshal(raw, &ints); err == nil {
+		for _, v := range ints {
+			b = append(b, byte(v))
+		}
+		return strings.ToValidUTF8(string(b), "?")
+	}
+	return ""
+}
+
+// FormatEntry renders an entry as a single line prefixed by a priority marker.
+func FormatEntry(e JournalEntry) string {
+	source := e.Identifier
+	if source == "" {
+		source = e.Unit
+	}
+	if e.PID != "" {
+		source += "[" + e.PID + "]"
+	}
+	return fmt.Sprintf("%s %s %s %s: %s", priorityMarker(e.Priority), e.Time.Local().Format("01-02 15:04:05"),
+		strings.ToUpper(PriorityName(e.Priority)), source, e.Message)
+}
+
+func priorityMarker(p int) string {
+	switch {
+	case p <= 3:
+		return "🔴"
+	case p == 4:
+		return "🟠"
+	case p == 5:
+		return "🔵"
+	default:
+		return "⚪"
+	}
+}
+
+// splitQuoted splits s on whitespace, keeping double-quoted sections together
+// without their quotes.
+func splitQuoted(s string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
@@ -0,0 +1,206 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/testutil"
+)
+
+const journalFixture = `{"__CURSOR":"s=1;i=1","__REALTIME_TIMESTAMP":"1704535200000000","PRIORITY":"3","_SYSTEMD_UNIT":"nginx.service","SYSLOG_IDENTIFIER":"nginx","_PID":"42","MESSAGE":"bind() failed"}
+{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1704535201000000","PRIORITY":"6","_SYSTEMD_UNIT":"nginx.service","SYSLOG_IDENTIFIER":"nginx","MESSAGE":[104,105,255]}
+`
+
+type queuedRunner struct {
+	calls   []string
+	outputs []string
+}
+
+func (q *queuedRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	q.calls = append(q.calls, name+" "+strings.Join(args, " "))
+	if len(q.outputs) == 0 {
+		return "", "", nil
+	}
+	out := q.outputs[0]
+	q.outputs = q.outputs[1:]
+	return out, "", nil
+}
+
+func TestParseJournalJSON(t *testing.T) {
+	entries, err := ParseJournalJSON(journalFixture)
+	if err != nil {
+		t.Fatalf("ParseJournalJSON() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	first := entries[0]
+	if first.Priority != 3 || first.Unit != "nginx.service" || first.PID != "42" || first.Cursor != "s=1;i=1" ||
+		first.Time.Unix() != 1704535200 || first.Message != "bind() failed" {
+		t.Fatalf("first entry = %+v", first)
+	}
+	if entries[1].Message != "hi?" {
+		t.Fatalf("binary message = %q, want %q", entries[1].Message, "hi?")
+	}
+	if line := FormatEntry(first); !strings.HasPrefix(line, "🔴 ") || !strings.HasSuffix(line, "ERR nginx[42]: bind() failed") {
+		t.Fatalf("FormatEntry() = %q", line)
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for raw, want := range map[string]int{"err": 3, "ERROR": 3, "4": 4, "warning": 4, "debug": 7} {
+		if got, err := ParsePriority(raw); err != nil || got != want {
+			t.Fatalf("ParsePriority(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+	if _, err := ParsePriority("9"); err == nil {
+		t.Fatalf("ParsePriority(9) should fail")
+	}
+}
+
+func TestHandleJournalBuildsQuery(t *testing.T) {
+	runner := &queuedRunner{outputs: []string{journalFixture}}
+	svc := NewService(&fakeClient{}, runner, ACL{}, nil)
+
+	bot, client := testutil.NewFakeBot()
+	if err := svc.HandleJournal(serviceContext(bot, 1, `nginx --since "1 hour ago" -p err --grep=bind`)); err != nil {
+		t.Fatalf("HandleJournal() error = %v", err)
+	}
+
+	want := "journalctl --no-pager -o json --unit=nginx.service --since=1 hour ago --priority=3 --grep=bind --lines=50"
+	if runner.calls[0] != want {
+		t.Fatalf("command = %q\nwant    %q", runner.calls[0], want)
+	}
+	text := client.Requests()[0].Values.Get("text")
+	if !strings.HasPrefix(text, "<pre>") || !strings.Contains(text, "bind() failed") {
+		t.Fatalf("reply = %q", text)
+	}
+}
+
+func TestHandleJournalSendsLargeResultsAsDocument(t *testing.T) {
+	var out strings.Builder
+	for i := 0; i < 100; i++ {
+		out.WriteString(`{"__REALTIME_TIMESTAMP":"1704535200000000","PRIORITY":"6","SYSLOG_IDENTIFIER":"app","MESSAGE":"` + strings.Repeat("x", 80) + `"}` + "\n")
+	}
+	runner := &queuedRunner{outputs: []string{out.String()}}
+	svc := NewService(&fakeClient{}, runner, ACL{}, nil)
+
+	bot, client := testutil.NewFakeBot()
+	if err := svc.HandleJournal(serviceContext(bot, 1, "app")); err != nil {
+		t.Fatalf("HandleJournal() error = %v", err)
+	}
+	if got := client.Requests()[0].Endpoint; got != "sendDocument" {
+		t.Fatalf("endpoint = %q, want sendDocument", got)
+	}
+}
+
+func TestHandleJournalRejectsInjection(t *testing.T) {
+	runner := &queuedRunner{}
+	svc := NewService(&fakeClient{}, runner, ACL{}, nil)
+
+	bot, client := testutil.NewFakeBot()
+	if err := svc.HandleJournal(serviceContext(bot, 1, "--file=/etc/shadow")); err != nil {
+		t.Fatalf("HandleJournal() error = %v", err)
+	}
+	if len(runner.calls) != 0 {
+		t.Fatalf("journalctl ran: %v", runner.calls)
+	}
+	if got := client.Requests()[0].Values.Get("text"); got != "Nombre de unidad invalido." {
+		t.Fatalf("reply = %q", got)
+	}
+}
+
+func TestJournalSourceFollowsCursor(t *testing.T) {
+	runner := &queuedRunner{outputs: []string{journalFixture, ""}}
+	source := journalSource(runner, "nginx.service", "", time.Second)
+
+	lines, err := source(context.Background())
+	if err != nil || len(lines) != 2 {
+		t.Fatalf("first poll = %v, %v", lines, err)
+	}
+	if _, err := source(context.Background()); err != nil {
+		t.Fatalf("second poll error = %v", err)
+	}
+	if !strings.HasSuffix(runner.calls[0], "--lines=20") {
+		t.Fatalf("first call = %q", runner.calls[0])
+	}
+	if !strings.HasSuffix(runner.calls[1], "--after-cursor=s=1;i=2") {
+		t.Fatalf("second call = %q", runner.calls[1])
+	}
+}
+
+func TestJournalSourceCapsLargeBatches(t *testing.T) {
+	var batch strings.Builder
+	for i := 1; i <= 500; i++ {
+		fmt.Fprintf(&batch, `{"__CURSOR":"s=1;i=%d","__REALTIME_TIMESTAMP":"1704535200000000","PRIORITY":"6","SYSLOG_IDENTIFIER":"nginx","MESSAGE":"request %d"}`+"\n", i, i)
+	}
+	runner := &queuedRunner{outputs: []string{journalFixture, batch.String(), ""}}
+	source := journalSource(runner, "nginx.service", "en", time.Second)
+
+	if _, err := source(context.Background()); err != nil {
+		t.Fatalf("first poll error = %v", err)
+	}
+	lines, err := source(context.Background())
+	if err != nil {
+		t.Fatalf("second poll error = %v", err)
+	}
+	if len(lines) != journalFollowLines+1 || lines[0] != "[480 lines skipped]" || !strings.HasSuffix(lines[len(lines)-1], "request 500") {
+		t.Fatalf("second poll = %d lines, first %q, last %q", len(lines), lines[0], lines[len(lines)-1])
+	}
+	if _, err := source(context.Background()); err != nil {
+		t.Fatalf("third poll error = %v", err)
+	}
+	if !strings.HasSuffix(runner.calls[2], "--after-cursor=s=1;i=500") {
+		t.Fatalf("cursor did not move past the skipped entries: %q", runner.calls[2])
+	}
+}
+
+func TestJournalWatcherAlertsOnErrors(t *testing.T) {
+	var sent []string
+	notifier := alerts.NewNotifier(time.Hour, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	now := time.Date(2024, 1, 6, 10, 0, 0, 0, time.Local)
+	runner := &queuedRunner{outputs: []string{journalFixture, "", ""}}
+	w := &JournalWatcher{
+		Runner:   runner,
+		Notifier: notifier,
+		Units:    []string{"nginx.service"},
+		Priority: 3,
+		Interval: time.Minute,
+		Timeout:  time.Second,
+		Quiet:    5 * time.Minute,
+		now:      func() time.Time { return now },
+	}
+
+	w.Check(context.Background())
+	if len(sent) != 1 || !strings.Contains(sent[0], "2 entradas con prioridad err o superior en nginx.service") {
+		t.Fatalf("sent = %v", sent)
+	}
+	if !strings.Contains(runner.calls[0], "--since=2024-01-06 09:59:00") || !strings.Contains(runner.calls[0], "--priority=3") {
+		t.Fatalf("first query = %q", runner.calls[0])
+	}
+
+	now = now.Add(time.Minute)
+	w.Check(context.Background())
+	if !strings.Contains(runner.calls[1], "--after-cursor=s=1;i=2") {
+		t.Fatalf("second query = %q", runner.calls[1])
+	}
+	if !notifier.Active("journal:nginx.service") {
+		t.Fatalf("alert should stay active within the quiet period")
+	}
+
+	now = now.Add(10 * time.Minute)
+	w.Check(context.Background())
+	if notifier.Active("journal:nginx.service") {
+		t.Fatalf("alert should resolve after the quiet period")
+	}
+	if len(sent) != 1 {
+		t.Fatalf("resolution should be silent, sent = %v", sent)
+	}
+}
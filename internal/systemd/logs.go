@@ -0,0 +1,149 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/system"
+)
+
+const (
+	journalDefaultLines  = 50
+	journalMaxLines      = 2000
+	journalFollowLines   = 20
+	journalInlineLimit   = 3500
+	journalFollowDefault = time.Minute
+)
+
+// HandleJournal is the handler for /journal.
+// Usage: /journal <unit> [--since <time>] [--priority <p>] [--grep <regex>] [--lines <n>]
+func (s *Service) HandleJournal(ctx *commands.Context) error {
+	usage := ctx.T("journal.usage")
+
+	fields := splitQuoted(ctx.Args())
+	if len(fields) == 0 {
+		return ctx.Reply(usage)
+	}
+	unit, err := NormalizeUnit(fields[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("journal.bad_unit"))
+	}
+
+	q := JournalQuery{Units: []string{unit}, Priority: -1, Lines: journalDefaultLines}
+	for i := 1; i < len(fields); i++ {
+		flag, value, hasValue := strings.Cut(fields[i], "=")
+		if !hasValue {
+			if i+1 >= len(fields) {
+				return ctx.Reply(usage)
+			}
+			i++
+			value = fields[i]
+		}
+		switch flag {
+		case "--since", "-S":
+			q.Since = value
+		case "--priority", "-p":
+			p, err := ParsePriority(value)
+			if err != nil {
+				return ctx.Reply(ctx.T("journal.bad_priority"))
+			}
+			q.Priority = p
+		case "--grep", "-g":
+			q.Grep = value
+		case "--lines", "-n":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return ctx.Reply(usage)
+			}
+			q.Lines = min(n, journalMaxLines)
+		default:
+			return ctx.Reply(usage)
+		}
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	entries, err := Journal(runCtx, s.Runner, q)
+	if err != nil {
+		return ctx.ReplyError(ctx.T("journal.read_failed"), err)
+	}
+	if len(entries) == 0 {
+		return ctx.Reply(ctx.T("journal.empty", unit))
+	}
+
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, FormatEntry(e))
+	}
+	body := strings.Join(lines, "\n")
+	if len(body) > journalInlineLimit {
+		name := fmt.Sprintf("journal-%s-%s.txt", unit, time.Now().Format("20060102-150405"))
+		return ctx.ReplyDocument(name, []byte(body+"\n"), ctx.N("journal.entries", len(entries), len(entries), unit))
+	}
+	return ctx.ReplyPre(body)
+}
+
+// HandleJournalFollow is the handler for /journal_follow.
+// Usage: /journal_follow <unit> [duration]
+func (s *Service) HandleJournalFollow(ctx *commands.Context) error {
+	args := ctx.ArgsList()
+	if len(args) == 0 || len(args) > 2 {
+		return ctx.Reply(ctx.T("journal.follow_usage"))
+	}
+	unit, err := NormalizeUnit(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("journal.bad_unit"))
+	}
+
+	duration := journalFollowDefault
+	if len(args) > 1 {
+		if parsed, err := time.ParseDuration(args[1]); err == nil && parsed > 0 {
+			duration = parsed
+		}
+	}
+
+	return ctx.Subscribe(unit, duration, journalSource(s.Runner, unit, ctx.Lang, ctx.AppConfig.CommandTimeout))
+}
+
+// journalSource follows a unit's journal with a cursor, so no entry is
+// repeated between polls. Each poll returns at most journalFollowLines
+// entries; older ones are replaced by a count in lang and the cursor still
+// moves past them.
+func journalSource(runner system.Runner, unit, lang string, timeout time.Duration) commands.LineSource {
+	cursor := ""
+	return func(ctx context.Context) ([]string, error) {
+		runCtx, cancel := system.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		q := JournalQuery{Units: []string{unit}, Priority: -1, AfterCursor: cursor}
+		if cursor == "" {
+			q.Lines = journalFollowLines
+		}
+		entries, err := Journal(runCtx, runner, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if e.Cursor != "" {
+				cursor = e.Cursor
+			}
+		}
+
+		lines := make([]string, 0, journalFollowLines+1)
+		if skipped := len(entries) - journalFollowLines; skipped > 0 {
+			lines = append(lines, i18n.N(lang, "journal.skipped", skipped))
+			entries = entries[skipped:]
+		}
+		for _, e := range entries {
+			lines = append(lines, FormatEntry(e))
+		}
+		return lines, nil
+	}
+}
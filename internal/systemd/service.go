@@ -0,0 +1,181 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"serverbot/internal/audit"
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+	"serverbot/internal/system"
+)
+
+// settleTimeout bounds how long a handler waits for a unit to finish starting
+// or stopping before reporting its state.
+const settleTimeout = 10 * time.Second
+
+// ACL restricts which units each role may control. Entries are glob patterns
+// matched against the normalized unit name ("nginx.service", "app@*").
+type ACL struct {
+	Owner []string
+	Admin []string
+}
+
+// Allowed reports whether a user of the given role may control unit. An empty
+// owner list allows every unit; an empty admin list allows none.
+func (a ACL) Allowed(unit string, owner bool) bool {
+	patterns := a.Admin
+	if owner {
+		if len(a.Owner) == 0 {
+			return true
+		}
+		patterns = a.Owner
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" {
+			return true
+		}
+		if normalized, err := NormalizeUnit(pattern); err == nil {
+			pattern = normalized
+		}
+		if ok, _ := path.Match(pattern, unit); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Service exposes systemd management commands.
+type Service struct {
+	Client Client
+	Runner system.Runner
+	ACL    ACL
+	Logger *log.Logger
+}
+
+// NewService builds a Service backed by client.
+func NewService(client Client, runner system.Runner, acl ACL, logger *log.Logger) *Service {
+	return &Service{Client: client, Runner: runner, ACL: acl, Logger: logger}
+}
+
+// HandleStart is the handler for /service_start.
+func (s *Service) HandleStart(ctx *commands.Context) error {
+	return s.control(ctx, "start", s.Client.Start)
+}
+
+// HandleStop is the handler for /service_stop.
+func (s *Service) HandleStop(ctx *commands.Context) error {
+	return s.control(ctx, "stop", s.Client.Stop)
+}
+
+// HandleRestart is the handler for /service_restart.
+func (s *Service) HandleRestart(ctx *commands.Context) error {
+	return s.control(ctx, "restart", s.Client.Restart)
+}
+
+// HandleEnable is the handler for /service_enable.
+func (s *Service) HandleEnable(ctx *commands.Context) error {
+	return s.control(ctx, "enable", s.Client.Enable)
+}
+
+// HandleFailed is the handler for /failed_units.
+func (s *Service) HandleFailed(ctx *commands.Context) error {
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout)
+	defer cancel()
+
+	units, err := s.Client.Failed(runCtx)
+	if err != nil {
+		return ctx.ReplyError(ctx.T("service.failed_list_error"), err)
+	}
+	if len(units) == 0 {
+		return ctx.Reply(ctx.T("service.no_failed"))
+	}
+
+	lines := make([]string, 0, len(units))
+	for _, u := range units {
+		line := fmt.Sprintf("%s (%s)", u.Name, u.SubState)
+		if u.Description != "" {
+			line += " - " + u.Description
+		}
+		lines = append(lines, line)
+	}
+
+	var b strings.Builder
+	b.WriteString(ctx.T("service.failed_title", len(units)) + "\n")
+	metrics.WriteSection(&b, "▫️", "systemd", lines)
+	return ctx.ReplyHTML(strings.TrimSpace(b.String()), false)
+}
+
+// control runs action on the unit named in the arguments. The reply uses the
+// "service.<verb>" message as the past participle of verb.
+func (s *Service) control(ctx *commands.Context, verb string, action func(context.Context, string) error) error {
+	args := ctx.ArgsList()
+	if len(args) != 1 {
+		return ctx.Reply(ctx.T("service.control_usage", verb))
+	}
+	unit, err := NormalizeUnit(args[0])
+	if err != nil {
+		return ctx.Reply(ctx.T("service.bad_name"))
+	}
+
+	entry := audit.Entry{Action: "service_" + verb, Target: unit}
+	if !s.ACL.Allowed(unit, ctx.IsOwner()) {
+		entry.Outcome = audit.OutcomeDenied
+		ctx.Audit(entry)
+		return ctx.Reply(ctx.T("service.denied", unit))
+	}
+
+	runCtx, cancel := system.WithTimeout(ctx.RequestContext, ctx.AppConfig.CommandTimeout+settleTimeout)
+	defer cancel()
+
+	if err := action(runCtx, unit); err != nil {
+		entry.Outcome, entry.Error = audit.OutcomeFailed, err.Error()
+		ctx.Audit(entry)
+		return ctx.ReplyError(ctx.T("service.control_failed", verb, unit), err)
+	}
+	entry.Outcome = audit.OutcomeOK
+	ctx.Audit(entry)
+
+	waitCtx, waitCancel := context.WithTimeout(runCtx, settleTimeout)
+	defer waitCancel()
+	state, err := WaitSettled(waitCtx, s.Client, unit)
+	if err != nil {
+		s.log("status %s: %v", unit, err)
+		return ctx.Reply(ctx.T("service.done", unit, ctx.T("service."+verb)))
+	}
+	return ctx.ReplyHTML(FormatUnit(ctx.Lang, state, ctx.T("service.title", ctx.T("service."+verb))), false)
+}
+
+// FormatUnit renders a unit state using the FormatHTML section layout, with
+// labels in lang.
+func FormatUnit(lang string, u Unit, title string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("<b>⚙️ %s</b>\n", title))
+
+	lines := []string{
+		i18n.T(lang, "service.state", u.ActiveState, u.SubState),
+		i18n.T(lang, "service.load", u.LoadState),
+	}
+	if u.UnitFileState != "" {
+		lines = append(lines, i18n.T(lang, "service.boot", u.UnitFileState))
+	}
+	if u.MainPID > 0 {
+		lines = append(lines, i18n.T(lang, "service.main_pid", u.MainPID))
+	}
+	if !u.Since.IsZero() {
+		lines = append(lines, i18n.T(lang, "service.since", u.Since.Local().Format("2006-01-02 15:04:05")))
+	}
+	metrics.WriteSection(&b, "▫️", u.Name, lines)
+	return strings.TrimSpace(b.String())
+}
+
+func (s *Service) log(format string, args ...any) {
+	if s.Logger != nil {
+		s.Logger.Printf("systemd: "+format, args...)
+	}
+}
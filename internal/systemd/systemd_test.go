@@ -0,0 +1,260 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/app"
+	"serverbot/internal/commands"
+	"serverbot/internal/testutil"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type fakeClient struct {
+	units   map[string]Unit
+	failed  []Unit
+	calls   []string
+	failErr error
+}
+
+func (f *fakeClient) Status(ctx context.Context, unit string) (Unit, error) {
+	return f.units[unit], nil
+}
+
+func (f *fakeClient) record(verb, unit string) error {
+	f.calls = append(f.calls, verb+" "+unit)
+	return f.failErr
+}
+
+func (f *fakeClient) Start(ctx context.Context, unit string) error { return f.record("start", unit) }
+func (f *fakeClient) Stop(ctx context.Context, unit string) error  { return f.record("stop", unit) }
+func (f *fakeClient) Restart(ctx context.Context, unit string) error {
+	return f.record("restart", unit)
+}
+func (f *fakeClient) Enable(ctx context.Context, unit string) error { return f.record("enable", unit) }
+
+func (f *fakeClient) Failed(ctx context.Context) ([]Unit, error) {
+	return f.failed, nil
+}
+
+type fakeRunner struct {
+	calls  []string
+	stdout string
+}
+
+func (f *fakeRunner) Run(ctx context.Context, name string, args ...string) (string, string, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	return f.stdout, "", nil
+}
+
+func serviceContext(bot *tgbotapi.BotAPI, chatID int64, args string) *commands.Context {
+	return &commands.Context{
+		AppConfig:      app.Config{OwnerID: 1, AdminIDs: []int64{2}, CommandTimeout: time.Second},
+		RequestContext: context.Background(),
+		Bot:            bot,
+		Update: tgbotapi.Update{
+			Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}},
+		},
+		Arguments: args,
+	}
+}
+
+func TestNormalizeUnit(t *testing.T) {
+	cases := map[string]string{
+		"nginx":            "nginx.service",
+		"docker.socket":    "docker.socket",
+		"app@blue":         "app@blue.service",
+		"my.app":           "my.app.service",
+		"backup.timer":     "backup.timer",
+		"-H":               "",
+		"nginx; rm -rf /":  "",
+		"../etc/passwd":    "",
+		"--property=Id":    "",
+		"systemd-journald": "systemd-journald.service",
+	}
+	for raw, want := range cases {
+		got, err := NormalizeUnit(raw)
+		if want == "" {
+			if !errors.Is(err, ErrInvalidUnit) {
+				t.Fatalf("NormalizeUnit(%q) = %q, want ErrInvalidUnit", raw, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Fatalf("NormalizeUnit(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+}
+
+func TestACLAllowed(t *testing.T) {
+	acl := ACL{Admin: []string{"nginx", "app@*"}}
+	if !acl.Allowed("anything.service", true) {
+		t.Fatalf("empty owner list should allow everything")
+	}
+	if !acl.Allowed("nginx.service", false) || !acl.Allowed("app@blue.service", false) {
+		t.Fatalf("admin patterns should match")
+	}
+	if acl.Allowed("sshd.service", false) {
+		t.Fatalf("sshd should not be allowed for admins")
+	}
+	if (ACL{Owner: []string{"nginx"}}).Allowed("sshd.service", true) {
+		t.Fatalf("owner list should restrict the owner")
+	}
+}
+
+func TestParseShow(t *testing.T) {
+	u := parseShow(`Id=nginx.service
+Description=A high performance web server
+LoadState=loaded
+ActiveState=active
+SubState=running
+UnitFileState=enabled
+MainPID=1234
+StateChangeTimestamp=Sat 2024-01-06 10:00:00 UTC
+`)
+	want := Unit{
+		Name: "nginx.service", Description: "A high performance web server",
+		LoadState: "loaded", ActiveState: "active", SubState: "running",
+		UnitFileState: "enabled", MainPID: 1234,
+		Since: time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC),
+	}
+	if u.Since.Unix() != want.Since.Unix() {
+		t.Fatalf("Since = %v, want %v", u.Since, want.Since)
+	}
+	u.Since = want.Since
+	if u != want {
+		t.Fatalf("parseShow() = %+v, want %+v", u, want)
+	}
+}
+
+func TestParseListUnits(t *testing.T) {
+	units := parseListUnits(`● backup.service loaded failed failed Nightly backup
+certbot.timer  loaded failed failed Renew certificates
+`)
+	if len(units) != 2 {
+		t.Fatalf("units = %+v", units)
+	}
+	if units[0].Name != "backup.service" || units[0].Description != "Nightly backup" || !units[0].Failed() {
+		t.Fatalf("first unit = %+v", units[0])
+	}
+	if units[1].Name != "certbot.timer" || units[1].SubState != "failed" {
+		t.Fatalf("second unit = %+v", units[1])
+	}
+}
+
+func TestCLIClientControlUsesSudo(t *testing.T) {
+	runner := &fakeRunner{}
+	client := &CLIClient{Runner: runner}
+	if err := client.Restart(context.Background(), "nginx.service"); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	if got := runner.calls[0]; got != "sudo systemctl restart --no-ask-password nginx.service" {
+		t.Fatalf("command = %q", got)
+	}
+}
+
+func TestHandleRestartChecksACL(t *testing.T) {
+	client := &fakeClient{units: map[string]Unit{
+		"nginx.service": {Name: "nginx.service", LoadState: "loaded", ActiveState: "active", SubState: "running", MainPID: 99},
+	}}
+	svc := NewService(client, nil, ACL{Admin: []string{"nginx"}}, nil)
+
+	bot, client2 := testutil.NewFakeBot()
+	if err := svc.HandleRestart(serviceContext(bot, 2, "sshd")); err != nil {
+		t.Fatalf("HandleRestart() error = %v", err)
+	}
+	if len(client.calls) != 0 {
+		t.Fatalf("denied unit was restarted: %v", client.calls)
+	}
+	if got := client2.Requests()[0].Values.Get("text"); !strings.Contains(got, "No tienes permiso") {
+		t.Fatalf("reply = %q", got)
+	}
+
+	if err := svc.HandleRestart(serviceContext(bot, 2, "nginx")); err != nil {
+		t.Fatalf("HandleRestart() error = %v", err)
+	}
+	if strings.Join(client.calls, ",") != "restart nginx.service" {
+		t.Fatalf("calls = %v", client.calls)
+	}
+	got := client2.Requests()[1].Values.Get("text")
+	for _, needle := range []string{"Servicio reiniciado", "nginx.service", "active (running)", "PID principal: 99"} {
+		if !strings.Contains(got, needle) {
+			t.Fatalf("reply missing %q:\n%s", needle, got)
+		}
+	}
+}
+
+func TestWatcherFiresAndResolves(t *testing.T) {
+	var sent []string
+	notifier := alerts.NewNotifier(time.Hour, func(message string) error {
+		sent = append(sent, message)
+		return nil
+	})
+	client := &fakeClient{failed: []Unit{{Name: "backup.service", ActiveState: "failed", SubState: "failed"}}}
+	runner := &fakeRunner{stdout: "2024-01-06T10:00:00+0000 host backup[1]: disk full\n"}
+	w := &Watcher{Client: client, Runner: runner, Notifier: notifier, Timeout: time.Second}
+
+	w.Check(context.Background())
+	w.Check(context.Background())
+	if len(sent) != 1 {
+		t.Fatalf("sent = %v, want a single alert", sent)
+	}
+	if !strings.Contains(sent[0], "backup.service ha fallado") || !strings.Contains(sent[0], "disk full") {
+		t.Fatalf("alert = %q", sent[0])
+	}
+	if runner.calls[0] != "journalctl -u backup.service -n 10 --no-pager -o short-iso" {
+		t.Fatalf("journal command = %q", runner.calls[0])
+	}
+
+	client.failed = nil
+	w.Check(context.Background())
+	if len(sent) != 2 || !strings.Contains(sent[1], "RECUPERADO") {
+		t.Fatalf("sent = %v, want recovery notice", sent)
+	}
+}
+
+func TestWatcherFollowsReloadedAlertSettings(t *testing.T) {
+	sent := make(chan string, 10)
+	notifier := alerts.NewNotifier(time.Hour, func(message string) error {
+		sent <- message
+		return nil
+	})
+	var enabled atomic.Bool
+	w := &Watcher{
+		Client:   &fakeClient{failed: []Unit{{Name: "backup.service", SubState: "failed"}}},
+		Notifier: notifier,
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		Config: func() app.Config {
+			return app.Config{Alerts: app.AlertConfig{Enabled: enabled.Load(), Interval: 5 * time.Millisecond}}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The first tick picks up the reloaded interval; later ones stay quiet
+	// while alerts are disabled.
+	w.Interval = 5 * time.Millisecond
+	go w.Run(ctx)
+
+	select {
+	case msg := <-sent:
+		t.Fatalf("alert %q sent while alerts are disabled", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	enabled.Store(true)
+	select {
+	case msg := <-sent:
+		if !strings.Contains(msg, "backup.service") {
+			t.Fatalf("alert = %q", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no alert after alerts were enabled")
+	}
+}
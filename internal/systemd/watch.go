@@ -0,0 +1,270 @@
+package systemd
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/app"
+	"serverbot/internal/i18n"
+	"serverbot/internal/system"
+)
+
+const (
+	failedKeyPrefix     = "unit:"
+	defaultJournalLines = 10
+)
+
+// Watcher fires an alert whenever a unit enters the failed state and sends a
+// recovery notice once it leaves it.
+type Watcher struct {
+	Client       Client
+	Runner       system.Runner
+	Notifier     *alerts.Notifier
+	Interval     time.Duration
+	Timeout      time.Duration
+	JournalLines int
+	Logger       *log.Logger
+	// Config returns the configuration in effect. When set, Run follows its
+	// alert interval and skips cycles while alerts are disabled, so a
+	// reload applies to the watcher.
+	Config func() app.Config
+}
+
+// Run checks failed units every Interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	runAlertLoop(ctx, w.Interval, w.Config, func(alerts app.AlertConfig) {
+		if alerts.Enabled {
+			w.Check(ctx)
+		}
+	})
+}
+
+// runAlertLoop calls cycle every interval until ctx is cancelled. With
+// config set, the interval follows Alerts.Interval and cycle gets the alert
+// settings in effect; otherwise it gets alerts enabled.
+func runAlertLoop(ctx context.Context, interval time.Duration, config func() app.Config, cycle func(app.AlertConfig)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			alerts := app.AlertConfig{Enabled: true, Interval: interval}
+			if config != nil {
+				alerts = config().Alerts
+			}
+			if alerts.Interval > 0 && alerts.Interval != interval {
+				interval = alerts.Interval
+				ticker.Reset(interval)
+			}
+			cycle(alerts)
+		}
+	}
+}
+
+// Check runs a single failed-unit cycle.
+func (w *Watcher) Check(ctx context.Context) {
+	checkCtx, cancel := system.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	units, err := w.Client.Failed(checkCtx)
+	if err != nil {
+		w.log("list failed units: %v", err)
+		return
+	}
+
+	failing := make(map[string]bool, len(units))
+	for _, u := range units {
+		key := failedKeyPrefix + u.Name
+		failing[key] = true
+		if w.Notifier.Active(key) {
+			continue
+		}
+		if err := w.Notifier.Fire(key, w.failedMessage(checkCtx, u)); err != nil {
+			w.log("alert send error: %v", err)
+		}
+	}
+
+	for _, key := range w.Notifier.ActiveKeys(failedKeyPrefix) {
+		if failing[key] {
+			continue
+		}
+		unit := strings.TrimPrefix(key, failedKeyPrefix)
+		if err := w.Notifier.Resolve(key, i18n.Tf("alert.unit_recovered", unit)); err != nil {
+			w.log("alert send error: %v", err)
+		}
+	}
+}
+
+func (w *Watcher) failedMessage(ctx context.Context, u Unit) i18n.Text {
+	journal := w.journalTail(ctx, u)
+	return func(lang string) string {
+		var b strings.Builder
+		b.WriteString(i18n.T(lang, "alert.unit_failed", u.Name, u.SubState))
+		if u.Description != "" {
+			b.WriteString("\n" + u.Description)
+		}
+		if journal != "" {
+			b.WriteString("\n\n" + i18n.T(lang, "alert.unit_journal") + "\n")
+			b.WriteString(journal)
+		}
+		return b.String()
+	}
+}
+
+// journalTail returns the last JournalLines lines of u's journal, or "" when
+// they cannot be read.
+func (w *Watcher) journalTail(ctx context.Context, u Unit) string {
+	if w.Runner == nil {
+		return ""
+	}
+	lines := w.JournalLines
+	if lines <= 0 {
+		lines = defaultJournalLines
+	}
+	journal, err := JournalTail(ctx, w.Runner, u.Name, lines)
+	if err != nil {
+		w.log("journal %s: %v", u.Name, err)
+		return ""
+	}
+	return journal
+}
+
+func (w *Watcher) log(format string, args ...any) {
+	if w.Logger != nil {
+		w.Logger.Printf("systemd: "+format, args...)
+	}
+}
+
+const (
+	journalKeyPrefix   = "journal:"
+	journalAlertSample = 5
+)
+
+// JournalWatcher alerts when the configured units log entries at Priority or
+// more severe. A unit's alert resolves silently after Quiet without new
+// entries, so a noisy unit is throttled by the notifier cooldown instead of
+// re-firing every cycle.
+type JournalWatcher struct {
+	Runner   system.Runner
+	Notifier *alerts.Notifier
+	Units    []string
+	Priority int
+	Interval time.Duration
+	Timeout  time.Duration
+	Quiet    time.Duration
+	Logger   *log.Logger
+	// Config returns the configuration in effect. When set, Run follows its
+	// alert interval and cooldown, which replace Interval and Quiet, and
+	// skips cycles while alerts are disabled.
+	Config func() app.Config
+
+	cursor    string
+	since     time.Time
+	lastError map[string]time.Time
+	now       func() time.Time
+}
+
+// Run checks the journal every Interval until ctx is cancelled. Only entries
+// logged after Run starts, or after alerts are enabled again, are
+// considered.
+func (w *JournalWatcher) Run(ctx context.Context) {
+	runAlertLoop(ctx, w.Interval, w.Config, func(alerts app.AlertConfig) {
+		if !alerts.Enabled {
+			w.cursor, w.since = "", time.Time{}
+			return
+		}
+		if w.Config != nil {
+			w.Interval, w.Quiet = alerts.Interval, alerts.Cooldown
+		}
+		w.Check(ctx)
+	})
+}
+
+// Check runs a single journal cycle.
+func (w *JournalWatcher) Check(ctx context.Context) {
+	if w.now == nil {
+		w.now = time.Now
+	}
+	now := w.now()
+	if w.since.IsZero() && w.cursor == "" {
+		w.since = now.Add(-w.Interval)
+	}
+	if w.lastError == nil {
+		w.lastError = make(map[string]time.Time)
+	}
+
+	checkCtx, cancel := system.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	q := JournalQuery{Units: w.Units, Priority: w.Priority, AfterCursor: w.cursor}
+	if w.cursor == "" {
+		q.Since = w.since.Format("2006-01-02 15:04:05")
+	}
+	entries, err := Journal(checkCtx, w.Runner, q)
+	if err != nil {
+		w.log("read journal: %v", err)
+		return
+	}
+
+	byUnit := make(map[string][]JournalEntry)
+	var order []string
+	for _, e := range entries {
+		if e.Cursor != "" {
+			w.cursor = e.Cursor
+		}
+		unit := e.Unit
+		if unit == "" && len(w.Units) == 1 {
+			unit = w.Units[0]
+		}
+		if _, ok := byUnit[unit]; !ok {
+			order = append(order, unit)
+		}
+		byUnit[unit] = append(byUnit[unit], e)
+	}
+
+	for _, unit := range order {
+		w.lastError[unit] = now
+		if err := w.Notifier.Fire(journalKeyPrefix+unit, w.message(unit, byUnit[unit])); err != nil {
+			w.log("alert send error: %v", err)
+		}
+	}
+
+	for _, key := range w.Notifier.ActiveKeys(journalKeyPrefix) {
+		unit := strings.TrimPrefix(key, journalKeyPrefix)
+		if now.Sub(w.lastError[unit]) < w.Quiet {
+			continue
+		}
+		delete(w.lastError, unit)
+		if err := w.Notifier.Resolve(key, nil); err != nil {
+			w.log("alert send error: %v", err)
+		}
+	}
+}
+
+func (w *JournalWatcher) message(unit string, entries []JournalEntry) i18n.Text {
+	count, priority := len(entries), PriorityName(w.Priority)
+	if len(entries) > journalAlertSample {
+		entries = entries[len(entries)-journalAlertSample:]
+	}
+	return func(lang string) string {
+		var b strings.Builder
+		b.WriteString(i18n.N(lang, "alert.journal_entries", count, count, priority, unit) + "\n")
+		for _, e := range entries {
+			b.WriteString("\n")
+			b.WriteString(FormatEntry(e))
+		}
+		return b.String()
+	}
+}
+
+func (w *JournalWatcher) log(format string, args ...any) {
+	if w.Logger != nil {
+		w.Logger.Printf("systemd: "+format, args...)
+	}
+}
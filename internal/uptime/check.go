@@ -0,0 +1,143 @@
+package uptime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"serverbot/internal/i18n"
+	"serverbot/internal/netdiag"
+	"serverbot/internal/system"
+)
+
+// Kind is the probe used by a check.
+type Kind string
+
+const (
+	KindHTTP Kind = "http"
+	KindTCP  Kind = "tcp"
+	KindICMP Kind = "icmp"
+)
+
+// Check is one monitored endpoint.
+type Check struct {
+	Name   string
+	Kind   Kind
+	Target string // URL for HTTP, host:port for TCP, host for ICMP
+}
+
+// ParseCheck reads "[name=]target" where target is an http(s) URL,
+// tcp://host:port or icmp://host. Without a name the target is used.
+func ParseCheck(spec string) (Check, error) {
+	spec = strings.TrimSpace(spec)
+	name, target, named := strings.Cut(spec, "=")
+	if !named || strings.Contains(name, "/") {
+		name, target = "", spec
+	}
+	name, target = strings.TrimSpace(name), strings.TrimSpace(target)
+
+	var c Check
+	var err error
+	switch {
+	case strings.HasPrefix(target, "tcp://"):
+		c.Kind = KindTCP
+		c.Target, err = netdiag.ValidateHostPort(strings.TrimPrefix(target, "tcp://"))
+	case strings.HasPrefix(target, "icmp://"):
+		c.Kind = KindICMP
+		c.Target, err = netdiag.ValidateHost(strings.TrimPrefix(target, "icmp://"))
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		c.Kind = KindHTTP
+		c.Target, err = netdiag.ValidateURL(target)
+	default:
+		return Check{}, fmt.Errorf("check %q: target must start with http://, https://, tcp:// or icmp://", spec)
+	}
+	if err != nil {
+		return Check{}, fmt.Errorf("check %q: %w", spec, err)
+	}
+
+	c.Name = name
+	if c.Name == "" {
+		c.Name = c.Target
+	}
+	return c, nil
+}
+
+// Result is the outcome of a single probe.
+type Result struct {
+	OK      bool
+	Latency time.Duration
+	Detail  i18n.Text // status code, loss or error text
+}
+
+// Describe returns Detail in lang.
+func (r Result) Describe(lang string) string {
+	if r.Detail == nil {
+		return ""
+	}
+	return r.Detail(lang)
+}
+
+// Prober runs checks. The zero value uses http.DefaultClient semantics and
+// requires Runner only for ICMP checks.
+type Prober struct {
+	HTTPClient *http.Client
+	Runner     system.Runner
+}
+
+// Probe runs c once within ctx.
+func (p *Prober) Probe(ctx context.Context, c Check) Result {
+	switch c.Kind {
+	case KindHTTP:
+		res, err := netdiag.HTTPCheck(ctx, c.Target, p.HTTPClient)
+		if err != nil {
+			return Result{Latency: res.Latency, Detail: probeError(err)}
+		}
+		return Result{
+			OK:      res.Status < http.StatusBadRequest,
+			Latency: res.Latency,
+			Detail:  i18n.Raw(fmt.Sprintf("HTTP %d", res.Status)),
+		}
+	case KindTCP:
+		elapsed, err := netdiag.PortCheck(ctx, c.Target)
+		if err != nil {
+			return Result{Latency: elapsed, Detail: probeError(err)}
+		}
+		return Result{OK: true, Latency: elapsed, Detail: i18n.Tf("uptime.tcp_open")}
+	case KindICMP:
+		return p.ping(ctx, c.Target)
+	}
+	return Result{Detail: i18n.Tf("uptime.unknown_kind", c.Kind)}
+}
+
+func (p *Prober) ping(ctx context.Context, host string) Result {
+	if p.Runner == nil {
+		return Result{Detail: i18n.Tf("uptime.ping_unavailable")}
+	}
+	start := time.Now()
+	stdout, stderr, err := p.Runner.Run(ctx, "ping", "-c", "3", "-W", "2", host)
+	summary, ok := netdiag.ParsePing(stdout)
+	if !ok {
+		if err == nil {
+			err = errors.New("unparsable ping output")
+		}
+		return Result{Latency: time.Since(start), Detail: probeError(fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr)))}
+	}
+	return Result{
+		OK:      summary.Received > 0,
+		Latency: time.Duration(summary.Avg * float64(time.Millisecond)),
+		Detail:  i18n.Tf("uptime.loss", summary.LossPercent),
+	}
+}
+
+func probeError(err error) i18n.Text {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return i18n.Tf("uptime.timeout")
+	case netdiag.IsTLSError(err):
+		return i18n.Tf("uptime.bad_tls")
+	}
+	return i18n.Raw(err.Error())
+}
@@ -0,0 +1,229 @@
+package uptime
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/i18n"
+	"serverbot/internal/system"
+)
+
+const (
+	keyPrefix = "uptime:"
+	// historyWindow is the span used for the uptime percentage.
+	historyWindow = 24 * time.Hour
+)
+
+// Monitor probes every check each Interval and alerts once a check has failed
+// Failures times in a row. A recovery notice is sent when it answers again.
+type Monitor struct {
+	Checks   []Check
+	Prober   *Prober
+	Notifier *alerts.Notifier
+	Interval time.Duration
+	Timeout  time.Duration
+	Failures int
+	Logger   *log.Logger
+
+	mu     sync.Mutex
+	states map[string]*state
+	now    func() time.Time
+}
+
+type sample struct {
+	at time.Time
+	ok bool
+}
+
+type state struct {
+	checked   bool
+	failures  int
+	downSince time.Time // first failure of the current streak
+	last      Result
+	lastAt    time.Time
+	history   []sample
+}
+
+// Status is the current view of one check.
+type Status struct {
+	Check     Check
+	Checked   bool
+	Failures  int  // consecutive failed probes
+	Down      bool // Failures reached the alert threshold
+	DownSince time.Time
+	Last      Result
+	LastAt    time.Time
+	Samples   int
+	OK        int
+}
+
+// Uptime returns the percentage of successful probes in the last 24 hours,
+// or -1 when there are no samples yet.
+func (s Status) Uptime() float64 {
+	if s.Samples == 0 {
+		return -1
+	}
+	return float64(s.OK) * 100 / float64(s.Samples)
+}
+
+// Run probes the checks immediately and then every Interval until ctx is
+// cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	m.Check(ctx)
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Check probes all checks concurrently and applies the results.
+func (m *Monitor) Check(ctx context.Context) {
+	results := make([]Result, len(m.Checks))
+	var wg sync.WaitGroup
+	for i, c := range m.Checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := system.WithTimeout(ctx, m.Timeout)
+			defer cancel()
+			results[i] = m.Prober.Probe(probeCtx, c)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+	for i, c := range m.Checks {
+		m.apply(c, results[i])
+	}
+}
+
+func (m *Monitor) apply(c Check, res Result) {
+	now := m.clock()
+	key := keyPrefix + c.Name
+
+	m.mu.Lock()
+	st := m.state(c.Name)
+	st.checked = true
+	st.last, st.lastAt = res, now
+	st.history = append(st.history, sample{at: now, ok: res.OK})
+	st.prune(now)
+
+	downSince := st.downSince
+	if res.OK {
+		st.failures, st.downSince = 0, time.Time{}
+	} else {
+		if st.failures == 0 {
+			st.downSince = now
+		}
+		st.failures++
+	}
+	failures := st.failures
+	m.mu.Unlock()
+
+	var err error
+	switch {
+	case res.OK:
+		if m.Notifier.Active(key) {
+			downtime := now.Sub(downSince).Round(time.Second)
+			err = m.Notifier.Resolve(key, func(lang string) string {
+				return i18n.T(lang, "alert.uptime_recovered", c.Name, downtime, res.Describe(lang))
+			})
+		}
+	case failures >= m.threshold() && !m.Notifier.Active(key):
+		m.log("%s down after %d failures: %s", c.Name, failures, res.Describe("en"))
+		err = m.Notifier.Fire(key, func(lang string) string {
+			return i18n.N(lang, "alert.uptime_down", failures, c.Name, c.Target, res.Describe(lang), failures)
+		})
+	}
+	if err != nil {
+		m.log("alert send error: %v", err)
+	}
+}
+
+// Statuses returns the state of every check in configuration order.
+func (m *Monitor) Statuses() []Status {
+	now := m.clock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Status, 0, len(m.Checks))
+	for _, c := range m.Checks {
+		st := m.state(c.Name)
+		st.prune(now)
+		status := Status{
+			Check:     c,
+			Checked:   st.checked,
+			Failures:  st.failures,
+			Down:      st.failures >= m.threshold(),
+			DownSince: st.downSince,
+			Last:      st.last,
+			LastAt:    st.lastAt,
+			Samples:   len(st.history),
+		}
+		for _, s := range st.history {
+			if s.ok {
+				status.OK++
+			}
+		}
+		out = append(out, status)
+	}
+	return out
+}
+
+// state returns the state for name, creating it. Callers hold m.mu.
+func (m *Monitor) state(name string) *state {
+	if m.states == nil {
+		m.states = make(map[string]*state)
+	}
+	st, ok := m.states[name]
+	if !ok {
+		st = &state{}
+		m.states[name] = st
+	}
+	return st
+}
+
+func (st *state) prune(now time.Time) {
+	cutoff := now.Add(-historyWindow)
+	drop := 0
+	for drop < len(st.history) && st.history[drop].at.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		st.history = append([]sample(nil), st.history[drop:]...)
+	}
+}
+
+func (m *Monitor) threshold() int {
+	if m.Failures <= 0 {
+		return 1
+	}
+	return m.Failures
+}
+
+func (m *Monitor) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
+
+func (m *Monitor) log(format string, args ...any) {
+	if m.Logger != nil {
+		m.Logger.Printf("uptime: "+format, args...)
+	}
+}
@@ -0,0 +1,55 @@
+package uptime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"serverbot/internal/commands"
+	"serverbot/internal/i18n"
+	"serverbot/internal/metrics"
+)
+
+// HandleStatusPage shows the state and 24h uptime of every check.
+func (m *Monitor) HandleStatusPage(ctx *commands.Context) error {
+	return ctx.ReplyHTML(FormatStatuses(ctx.Lang, m.Statuses()), false)
+}
+
+// FormatStatuses renders statuses as the /status_page HTML message in lang.
+func FormatStatuses(lang string, statuses []Status) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "uptime.title") + "\n")
+
+	for _, s := range statuses {
+		icon, state := statusLabel(lang, s)
+		lines := []string{
+			fmt.Sprintf("%s %s", s.Check.Kind, s.Check.Target),
+			i18n.T(lang, "uptime.state", state),
+		}
+		if s.Checked {
+			last := i18n.T(lang, "uptime.last", s.LastAt.Format("15:04:05"), s.Last.Describe(lang))
+			if s.Last.Latency > 0 {
+				last += fmt.Sprintf(" (%s)", s.Last.Latency.Round(time.Millisecond))
+			}
+			lines = append(lines, last)
+		}
+		if uptime := s.Uptime(); uptime >= 0 {
+			lines = append(lines, fmt.Sprintf("Uptime 24h: %.2f%% (%d/%d)", uptime, s.OK, s.Samples))
+		}
+		metrics.WriteSection(&b, icon, s.Check.Name, lines)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func statusLabel(lang string, s Status) (string, string) {
+	switch {
+	case !s.Checked:
+		return "⚪", i18n.T(lang, "uptime.pending")
+	case s.Down:
+		return "🔴", i18n.T(lang, "uptime.down_since", s.DownSince.Format("2006-01-02 15:04"))
+	case s.Failures > 0:
+		return "🟡", i18n.N(lang, "uptime.failing", s.Failures)
+	default:
+		return "🟢", i18n.T(lang, "uptime.up")
+	}
+}
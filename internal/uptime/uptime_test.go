@@ -0,0 +1,150 @@
+package uptime
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"serverbot/internal/alerts"
+	"serverbot/internal/i18n"
+)
+
+func TestParseCheck(t *testing.T) {
+	cases := []struct {
+		spec string
+		want Check
+	}{
+		{"web=https://example.com/health", Check{Name: "web", Kind: KindHTTP, Target: "https://example.com/health"}},
+		{"http://example.com", Check{Name: "http://example.com", Kind: KindHTTP, Target: "http://example.com"}},
+		{"db = tcp://10.0.0.5:5432", Check{Name: "db", Kind: KindTCP, Target: "10.0.0.5:5432"}},
+		{"icmp://router.lan", Check{Name: "router.lan", Kind: KindICMP, Target: "router.lan"}},
+	}
+	for _, tc := range cases {
+		got, err := ParseCheck(tc.spec)
+		if err != nil {
+			t.Fatalf("ParseCheck(%q) error = %v", tc.spec, err)
+		}
+		if got != tc.want {
+			t.Fatalf("ParseCheck(%q) = %+v, want %+v", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{"example.com", "tcp://db", "icmp://-c1", "ftp://example.com"} {
+		if _, err := ParseCheck(spec); err == nil {
+			t.Fatalf("ParseCheck(%q) expected error", spec)
+		}
+	}
+}
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestMonitorAlertsAfterConsecutiveFailuresAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	var sent []string
+	clk := &clock{now: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	m := &Monitor{
+		Checks:   []Check{{Name: "web", Kind: KindHTTP, Target: srv.URL}},
+		Prober:   &Prober{HTTPClient: srv.Client()},
+		Notifier: alerts.NewNotifier(time.Hour, func(msg string) error { sent = append(sent, msg); return nil }),
+		Timeout:  time.Second,
+		Failures: 3,
+		now:      clk.Now,
+	}
+
+	for i := 0; i < 2; i++ {
+		m.Check(context.Background())
+		clk.now = clk.now.Add(time.Minute)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("alert sent before threshold: %v", sent)
+	}
+	if st := m.Statuses()[0]; st.Down || st.Failures != 2 {
+		t.Fatalf("status after 2 failures = %+v", st)
+	}
+
+	m.Check(context.Background())
+	clk.now = clk.now.Add(time.Minute)
+	m.Check(context.Background())
+	if len(sent) != 1 || !strings.Contains(sent[0], "[⚠️ ALERTA] web no responde") || !strings.Contains(sent[0], "HTTP 502") {
+		t.Fatalf("alerts = %v", sent)
+	}
+
+	healthy.Store(true)
+	clk.now = clk.now.Add(time.Minute)
+	m.Check(context.Background())
+	if len(sent) != 2 || !strings.Contains(sent[1], "[✅ RECUPERADO] web vuelve a responder tras 4m0s") {
+		t.Fatalf("recovery = %v", sent)
+	}
+
+	st := m.Statuses()[0]
+	if st.Down || st.Failures != 0 || st.Samples != 5 || st.OK != 1 || st.Uptime() != 20 {
+		t.Fatalf("status after recovery = %+v", st)
+	}
+}
+
+func TestMonitorUptimeWindow(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	clk := &clock{now: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)}
+	m := &Monitor{
+		Checks:   []Check{{Name: "db", Kind: KindTCP, Target: ln.Addr().String()}},
+		Prober:   &Prober{},
+		Notifier: alerts.NewNotifier(time.Hour, nil),
+		Timeout:  time.Second,
+		Failures: 1,
+		now:      clk.Now,
+	}
+	m.Check(context.Background())
+
+	ln.Close()
+	clk.now = clk.now.Add(time.Hour)
+	m.Check(context.Background())
+	if st := m.Statuses()[0]; !st.Down || st.Uptime() != 50 {
+		t.Fatalf("status = %+v, uptime %v", st, st.Uptime())
+	}
+
+	// The successful sample falls out of the 24h window.
+	clk.now = clk.now.Add(23*time.Hour + time.Minute)
+	if st := m.Statuses()[0]; st.Samples != 1 || st.Uptime() != 0 {
+		t.Fatalf("status after window = %+v", st)
+	}
+}
+
+func TestFormatStatuses(t *testing.T) {
+	at := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	got := FormatStatuses("", []Status{
+		{Check: Check{Name: "web", Kind: KindHTTP, Target: "https://example.com"}, Checked: true,
+			Last: Result{OK: true, Detail: i18n.Raw("HTTP 200"), Latency: 120 * time.Millisecond}, LastAt: at, Samples: 4, OK: 3},
+		{Check: Check{Name: "db", Kind: KindTCP, Target: "db:5432"}, Checked: true, Down: true, Failures: 3,
+			DownSince: at, Last: Result{Detail: i18n.Raw("timeout")}, LastAt: at, Samples: 3},
+		{Check: Check{Name: "gw", Kind: KindICMP, Target: "10.0.0.1"}},
+	})
+	for _, needle := range []string{
+		"<b>📡 Estado de servicios</b>",
+		"🟢 <b>web</b>", "Estado: operativo", "HTTP 200 (120ms)", "Uptime 24h: 75.00% (3/4)",
+		"🔴 <b>db</b>", "caido desde 2026-05-01 09:30", "Uptime 24h: 0.00% (0/3)",
+		"⚪ <b>gw</b>", "Estado: pendiente",
+	} {
+		if !strings.Contains(got, needle) {
+			t.Fatalf("output missing %q:\n%s", needle, got)
+		}
+	}
+}